@@ -0,0 +1,804 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The SRD conditions, offered when a condition is typed in part. Anything
+// else typed is kept as written, e.g. "Concentrating".
+var srdConditions = []string{
+	"Blinded", "Charmed", "Deafened", "Exhaustion", "Frightened", "Grappled", "Incapacitated", "Invisible",
+	"Paralyzed", "Petrified", "Poisoned", "Prone", "Restrained", "Stunned", "Unconscious",
+}
+
+// Entries kept in an encounter's log.
+const maxCombatLog = 50
+
+// Combatant is one creature in an encounter.
+type Combatant struct {
+	Name       string      `json:"name"`
+	HP         int         `json:"hp"`
+	MaxHP      int         `json:"max_hp"`
+	TempHP     int         `json:"temp_hp,omitempty"`
+	AC         int         `json:"ac"`
+	InitMod    int         `json:"initiative_modifier"`
+	Initiative int         `json:"initiative"`
+	Rolled     bool        `json:"rolled,omitempty"`    // initiative has been rolled or set
+	Character  string      `json:"character,omitempty"` // roster ID, when imported from a saved character
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Condition is a condition on a combatant. Rounds counts down at the end of
+// each of the combatant's turns, so it lasts that many rounds; 0 lasts until
+// it is removed.
+type Condition struct {
+	Name   string `json:"name"`
+	Rounds int    `json:"rounds,omitempty"`
+}
+
+// Encounter is a fight: the combatants in turn order, whose turn it is and
+// the round.
+type Encounter struct {
+	Name       string      `json:"name"`
+	Combatants []Combatant `json:"combatants"`
+	Round      int         `json:"round"` // 0 until initiative is rolled
+	Turn       int         `json:"turn"`  // index of the combatant whose turn it is
+	Log        []string    `json:"log,omitempty"`
+	Updated    time.Time   `json:"updated"`
+}
+
+func (e Encounter) logEvent(format string, args ...interface{}) Encounter {
+	e.Log = append(e.Log, fmt.Sprintf(format, args...))
+	if len(e.Log) > maxCombatLog {
+		e.Log = e.Log[len(e.Log)-maxCombatLog:]
+	}
+	return e
+}
+
+// rollInitiative rolls a d20 plus the combatant's modifier through the dice
+// engine.
+func rollInitiative(c Combatant) (Combatant, error) {
+	result, err := rollDiceExpression(fmt.Sprintf("1d20%+d", c.InitMod))
+	if err != nil {
+		return c, err
+	}
+	c.Initiative = result.Total
+	c.Rolled = true
+	return c, nil
+}
+
+// sortByInitiative puts the combatants in turn order, highest initiative
+// first with ties going to the higher modifier, and keeps the turn with the
+// combatant whose turn it was.
+func sortByInitiative(e Encounter) Encounter {
+	if len(e.Combatants) == 0 {
+		return e
+	}
+	current := min(e.Turn, len(e.Combatants)-1)
+	order := make([]int, len(e.Combatants))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := e.Combatants[order[i]], e.Combatants[order[j]]
+		if a.Initiative != b.Initiative {
+			return a.Initiative > b.Initiative
+		}
+		return a.InitMod > b.InitMod
+	})
+	combatants := make([]Combatant, len(order))
+	for i, from := range order {
+		combatants[i] = e.Combatants[from]
+		if from == current {
+			e.Turn = i
+		}
+	}
+	e.Combatants = combatants
+	return e
+}
+
+// startCombat rolls initiative for everyone and starts round 1 with the
+// highest roll.
+func startCombat(e Encounter) (Encounter, error) {
+	for i := range e.Combatants {
+		c, err := rollInitiative(e.Combatants[i])
+		if err != nil {
+			return e, err
+		}
+		e.Combatants[i] = c
+	}
+	e.Turn = 0
+	e = sortByInitiative(e)
+	e.Turn, e.Round = 0, 1
+	var rolls []string
+	for _, c := range e.Combatants {
+		rolls = append(rolls, fmt.Sprintf("%s %d", c.Name, c.Initiative))
+	}
+	return e.logEvent("Round 1 • Initiative: %s", strings.Join(rolls, ", ")), nil
+}
+
+// addCombatant adds a combatant. Once combat has started they roll
+// initiative straight away and join the turn order.
+func addCombatant(e Encounter, c Combatant) (Encounter, error) {
+	if e.Round > 0 && !c.Rolled {
+		var err error
+		if c, err = rollInitiative(c); err != nil {
+			return e, err
+		}
+	}
+	e.Combatants = append(e.Combatants, c)
+	if e.Round > 0 {
+		e = sortByInitiative(e)
+		return e.logEvent("%s joins with initiative %d", c.Name, c.Initiative), nil
+	}
+	return e, nil
+}
+
+// removeCombatant takes a combatant out, keeping the turn with whoever has
+// it, or passing it on if it was the removed combatant's. The fight ends
+// when the last combatant leaves.
+func removeCombatant(e Encounter, i int) Encounter {
+	name := e.Combatants[i].Name
+	e.Combatants = append(e.Combatants[:i], e.Combatants[i+1:]...)
+	if len(e.Combatants) == 0 {
+		e.Round, e.Turn = 0, 0
+		return e.logEvent("%s leaves the fight, which is over", name)
+	}
+	if i < e.Turn {
+		e.Turn--
+	}
+	if e.Turn >= len(e.Combatants) {
+		e.Turn = 0
+		if e.Round > 0 {
+			e.Round++
+		}
+	}
+	return e.logEvent("%s leaves the fight", name)
+}
+
+// uniqueCombatantName numbers a name that is already taken, as in
+// "Goblin 2".
+func uniqueCombatantName(e Encounter, name string) string {
+	taken := func(candidate string) bool {
+		for _, c := range e.Combatants {
+			if strings.EqualFold(c.Name, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+	if !taken(name) {
+		return name
+	}
+	for n := 2; ; n++ {
+		if candidate := fmt.Sprintf("%s %d", name, n); !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// parseCombatants reads an ad hoc entry of "name [xN] HP AC [initiative]",
+// such as "Goblin x3 2d6 15 +2". HP can be dice, rolled for each creature,
+// and a count numbers the creatures "Goblin 1", "Goblin 2" and so on.
+func parseCombatants(input string) ([]Combatant, error) {
+	usage := fmt.Errorf("enter a name, HP and AC, then optionally an initiative modifier, e.g. Goblin x3 2d6 15 +2")
+	fields := strings.Fields(input)
+	numbers := 0
+	for i := len(fields) - 1; i > 0 && numbers < 3; i-- {
+		if _, err := parseDiceExpression(fields[i]); err != nil {
+			break
+		}
+		numbers++
+	}
+	if numbers < 2 {
+		return nil, usage
+	}
+	if numbers == 2 {
+		fields = append(fields, "+0")
+	}
+	name, stats := fields[:len(fields)-3], fields[len(fields)-3:]
+	if len(name) == 0 {
+		return nil, usage
+	}
+
+	count := 1
+	if last := strings.ToLower(name[len(name)-1]); len(name) > 1 && strings.HasPrefix(last, "x") {
+		if n, err := strconv.Atoi(last[1:]); err == nil && n > 0 {
+			count, name = n, name[:len(name)-1]
+		}
+	}
+	ac, err := strconv.Atoi(stats[1])
+	if err != nil {
+		return nil, fmt.Errorf("AC must be a number, not %q", stats[1])
+	}
+	initMod, err := strconv.Atoi(strings.TrimPrefix(stats[2], "+"))
+	if err != nil {
+		return nil, fmt.Errorf("initiative modifier must be a number, not %q", stats[2])
+	}
+
+	var combatants []Combatant
+	for i := 0; i < count; i++ {
+		hp, err := rollDiceExpression(stats[0])
+		if err != nil {
+			return nil, fmt.Errorf("HP: %v", err)
+		}
+		if hp.Total < 1 {
+			return nil, fmt.Errorf("HP must be at least 1")
+		}
+		c := Combatant{Name: strings.Join(name, " "), HP: hp.Total, MaxHP: hp.Total, AC: ac, InitMod: initMod}
+		if count > 1 {
+			c.Name = fmt.Sprintf("%s %d", c.Name, i+1)
+		}
+		combatants = append(combatants, c)
+	}
+	return combatants, nil
+}
+
+// characterCombatant makes a combatant from a saved character, with stats
+// worked out using the content pack the character was made with.
+func (m model) characterCombatant(c Character) Combatant {
+	pack := m.rpgPack
+	if c.Pack != "" && c.Pack != pack.ID {
+		if i := findContentPack(m.rpgPacks, c.Pack); i >= 0 && len(m.rpgPacks[i].Errors) == 0 {
+			pack = m.rpgPacks[i]
+		}
+	}
+	derived := pack.deriveStats(c)
+	return Combatant{
+		Name:      characterDisplayName(c),
+		HP:        derived.HitPoints,
+		MaxHP:     derived.HitPoints,
+		AC:        derived.ArmorClass,
+		InitMod:   derived.Initiative,
+		Character: c.ID,
+	}
+}
+
+// rollAmount reads a damage or healing amount, which can be dice.
+func rollAmount(input string) (int, string, error) {
+	result, err := rollDiceExpression(input)
+	if err != nil {
+		return 0, "", err
+	}
+	if result.Total < 0 {
+		return 0, "", fmt.Errorf("the amount can't be negative")
+	}
+	detail := ""
+	if _, err := strconv.Atoi(strings.TrimSpace(input)); err != nil {
+		detail = fmt.Sprintf(" (%s)", result.Expression)
+	}
+	return result.Total, detail, nil
+}
+
+// applyDamage takes damage from temporary hit points first, then hit points,
+// stopping at 0.
+func applyDamage(c Combatant, amount int) Combatant {
+	absorbed := min(c.TempHP, amount)
+	c.TempHP -= absorbed
+	c.HP = max(0, c.HP-(amount-absorbed))
+	return c
+}
+
+func applyHealing(c Combatant, amount int) Combatant {
+	c.HP = min(c.MaxHP, c.HP+amount)
+	return c
+}
+
+// parseCondition reads a condition and an optional number of rounds, such as
+// "poisoned 3". A partial name is completed from the SRD conditions.
+func parseCondition(input string) (Condition, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return Condition{}, fmt.Errorf("enter a condition")
+	}
+	condition := Condition{}
+	if rounds, err := strconv.Atoi(fields[len(fields)-1]); err == nil && len(fields) > 1 {
+		if rounds < 0 {
+			return condition, fmt.Errorf("rounds can't be negative")
+		}
+		condition.Rounds = rounds
+		fields = fields[:len(fields)-1]
+	}
+	condition.Name = capitalizeName(strings.Join(fields, " "))
+	for _, name := range srdConditions {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(condition.Name)) {
+			condition.Name = name
+			break
+		}
+	}
+	return condition, nil
+}
+
+// toggleCondition adds a condition, replacing the same one with a new
+// duration, or removes it if it is given again without a duration.
+func toggleCondition(c Combatant, condition Condition) (Combatant, bool) {
+	conditions := append([]Condition{}, c.Conditions...)
+	for i, existing := range conditions {
+		if strings.EqualFold(existing.Name, condition.Name) {
+			if condition.Rounds == 0 {
+				c.Conditions = append(conditions[:i], conditions[i+1:]...)
+				return c, false
+			}
+			conditions[i] = condition
+			c.Conditions = conditions
+			return c, true
+		}
+	}
+	c.Conditions = append(conditions, condition)
+	return c, true
+}
+
+func formatCondition(condition Condition) string {
+	if condition.Rounds > 0 {
+		return fmt.Sprintf("%s (%d)", condition.Name, condition.Rounds)
+	}
+	return condition.Name
+}
+
+// nextTurn ends the current combatant's turn, ticking down their conditions,
+// and passes the turn on, starting a new round after the last combatant.
+func nextTurn(e Encounter) Encounter {
+	if e.Round == 0 || len(e.Combatants) == 0 {
+		return e
+	}
+	c := e.Combatants[e.Turn]
+	var kept []Condition
+	var ended []string
+	for _, condition := range c.Conditions {
+		if condition.Rounds == 0 {
+			kept = append(kept, condition)
+			continue
+		}
+		if condition.Rounds--; condition.Rounds == 0 {
+			ended = append(ended, condition.Name)
+		} else {
+			kept = append(kept, condition)
+		}
+	}
+	e.Combatants[e.Turn].Conditions = kept
+	if len(ended) > 0 {
+		e = e.logEvent("%s is no longer %s", c.Name, strings.Join(ended, " or "))
+	}
+
+	e.Turn++
+	if e.Turn >= len(e.Combatants) {
+		e.Turn = 0
+		e.Round++
+		e = e.logEvent("Round %d", e.Round)
+	}
+	return e
+}
+
+// previousTurn steps the turn back, for when it was passed by mistake.
+// Conditions that were ticked down are not restored.
+func previousTurn(e Encounter) Encounter {
+	if e.Round == 0 || len(e.Combatants) == 0 || (e.Round == 1 && e.Turn == 0) {
+		return e
+	}
+	e.Turn--
+	if e.Turn < 0 {
+		e.Turn = len(e.Combatants) - 1
+		e.Round--
+	}
+	return e
+}
+
+var combatPromptLabels = map[string]string{
+	"add":        "Add combatants as name [xN] HP AC [initiative], e.g. Goblin x3 2d6 15 +2:",
+	"damage":     "Damage (a number or dice, e.g. 2d6+3):",
+	"heal":       "Healing (a number or dice, e.g. 2d4+2):",
+	"temp":       "Temporary hit points:",
+	"condition":  "Condition and rounds, e.g. poisoned 3 (a condition it already has, without rounds, is removed):",
+	"initiative": "Initiative (the total rolled):",
+	"save":       "Save encounter as:",
+}
+
+func (m model) updateCombat(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.combatPrompt != "" {
+		return m.updateCombatPrompt(keyMsg)
+	}
+	if m.combatShowImport {
+		return m.updateCombatImport(keyMsg)
+	}
+	if m.combatShowPicker {
+		return m.updateCombatPicker(keyMsg)
+	}
+
+	e := m.combatEncounter
+	selected := m.combatCursor < len(e.Combatants)
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = menuView
+	case "up", "k":
+		if m.combatCursor > 0 {
+			m.combatCursor--
+		}
+	case "down", "j":
+		if m.combatCursor < len(e.Combatants)-1 {
+			m.combatCursor++
+		}
+	case "a":
+		m = m.openCombatPrompt("add", "")
+	case "i":
+		var err error
+		m.combatRoster, err = loadCharacters()
+		m.combatImportCursor = 0
+		m.combatShowImport = true
+		m.combatMessage = ""
+		if err != nil {
+			m.combatMessage = "❌ " + err.Error()
+		}
+	case "r":
+		if len(e.Combatants) == 0 {
+			m.combatMessage = "❌ Add combatants first"
+			break
+		}
+		started, err := startCombat(e)
+		if err != nil {
+			m.combatMessage = "❌ " + err.Error()
+			break
+		}
+		m.combatCursor = 0
+		m = m.storeCombat(started, "✅ Initiative rolled • Round 1")
+	case "n", " ", "tab":
+		if e.Round == 0 || len(e.Combatants) == 0 {
+			m.combatMessage = "❌ Roll initiative with R first"
+			break
+		}
+		e = nextTurn(e)
+		m.combatCursor = e.Turn
+		m = m.storeCombat(e, fmt.Sprintf("⚔️  %s's turn", e.Combatants[e.Turn].Name))
+	case "p", "shift+tab":
+		e = previousTurn(e)
+		m.combatCursor = min(e.Turn, max(len(e.Combatants)-1, 0))
+		m = m.storeCombat(e, "")
+	case "d", "h", "t", "c", "e":
+		if selected {
+			prompt := map[string]string{"d": "damage", "h": "heal", "t": "temp", "c": "condition", "e": "initiative"}[keyMsg.String()]
+			m = m.openCombatPrompt(prompt, "")
+		}
+	case "x":
+		if selected {
+			e = removeCombatant(e, m.combatCursor)
+			m.combatCursor = min(m.combatCursor, max(len(e.Combatants)-1, 0))
+			m = m.storeCombat(e, "")
+		}
+	case "s":
+		m = m.openCombatPrompt("save", e.Name)
+	case "l":
+		var err error
+		m.combatSaved, err = loadEncounters()
+		m.combatPickerCursor = 0
+		m.combatShowPicker = true
+		m.combatMessage = ""
+		if err != nil {
+			m.combatMessage = "❌ " + err.Error()
+		}
+	case "X":
+		m.combatEncounter = Encounter{}
+		m.combatCursor = 0
+		m.combatMessage = "✅ New encounter"
+	}
+	return m, nil
+}
+
+func (m model) openCombatPrompt(prompt, input string) model {
+	m.combatPrompt = prompt
+	m.combatInput = input
+	m.combatMessage = ""
+	return m
+}
+
+func (m model) updateCombatPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.combatPrompt = ""
+		m.combatInput = ""
+	case "backspace":
+		if len(m.combatInput) > 0 {
+			m.combatInput = m.combatInput[:len(m.combatInput)-1]
+		}
+	case "enter":
+		input := strings.TrimSpace(m.combatInput)
+		if input == "" {
+			return m, nil
+		}
+		var err error
+		m, err = m.applyCombatPrompt(input)
+		if err != nil {
+			m.combatMessage = "❌ " + err.Error()
+			return m, nil
+		}
+		m.combatPrompt = ""
+		m.combatInput = ""
+	default:
+		if len(msg.String()) == 1 {
+			m.combatInput += msg.String()
+		}
+	}
+	return m, nil
+}
+
+// applyCombatPrompt carries out what was typed at a prompt. On an error the
+// prompt stays open so the entry can be fixed.
+func (m model) applyCombatPrompt(input string) (model, error) {
+	e := m.combatEncounter
+	if m.combatPrompt == "add" {
+		combatants, err := parseCombatants(input)
+		if err != nil {
+			return m, err
+		}
+		for _, c := range combatants {
+			c.Name = uniqueCombatantName(e, c.Name)
+			if e, err = addCombatant(e, c); err != nil {
+				return m, err
+			}
+		}
+		return m.storeCombat(e, fmt.Sprintf("✅ Added %d combatant(s)", len(combatants))), nil
+	}
+	if m.combatPrompt == "save" {
+		e.Name = input
+		saved, err := loadEncounters()
+		if err == nil {
+			saved = storeEncounter(saved, e)
+			err = saveEncounters(saved)
+		}
+		if err != nil {
+			return m, fmt.Errorf("save failed: %v", err)
+		}
+		m.combatSaved = saved
+		m.combatEncounter = e
+		m.combatMessage = fmt.Sprintf("✅ Saved %q", input)
+		return m, nil
+	}
+
+	if m.combatCursor >= len(e.Combatants) {
+		return m, nil
+	}
+	c := e.Combatants[m.combatCursor]
+	switch m.combatPrompt {
+	case "damage", "heal", "temp":
+		amount, detail, err := rollAmount(input)
+		if err != nil {
+			return m, err
+		}
+		switch m.combatPrompt {
+		case "damage":
+			c = applyDamage(c, amount)
+			e = e.logEvent("%s takes %d damage%s, %d HP left", c.Name, amount, detail, c.HP)
+			if c.HP == 0 {
+				e = e.logEvent("%s is down", c.Name)
+			}
+		case "heal":
+			c = applyHealing(c, amount)
+			e = e.logEvent("%s heals %d%s, now %d HP", c.Name, amount, detail, c.HP)
+		case "temp":
+			// Temporary hit points don't stack; the higher amount is kept
+			c.TempHP = max(c.TempHP, amount)
+			e = e.logEvent("%s has %d temporary HP", c.Name, c.TempHP)
+		}
+		e.Combatants[m.combatCursor] = c
+	case "condition":
+		condition, err := parseCondition(input)
+		if err != nil {
+			return m, err
+		}
+		var added bool
+		c, added = toggleCondition(c, condition)
+		e.Combatants[m.combatCursor] = c
+		if added {
+			e = e.logEvent("%s is %s", c.Name, formatCondition(condition))
+		} else {
+			e = e.logEvent("%s is no longer %s", c.Name, condition.Name)
+		}
+	case "initiative":
+		initiative, err := strconv.Atoi(input)
+		if err != nil {
+			return m, fmt.Errorf("initiative must be a number")
+		}
+		c.Initiative, c.Rolled = initiative, true
+		e.Combatants[m.combatCursor] = c
+		e = e.logEvent("%s's initiative is %d", c.Name, initiative)
+		if e.Round > 0 {
+			e = sortByInitiative(e)
+			// Keep the cursor on the combatant, who may have moved; names are unique
+			for i := range e.Combatants {
+				if e.Combatants[i].Name == c.Name {
+					m.combatCursor = i
+				}
+			}
+		}
+	}
+	return m.storeCombat(e, ""), nil
+}
+
+// storeCombat makes e the current encounter, saving it if it has been saved
+// before, and reports status in the message line.
+func (m model) storeCombat(e Encounter, status string) model {
+	m.combatEncounter = e
+	m.combatMessage = status
+	if e.Name == "" {
+		return m
+	}
+	saved, err := loadEncounters()
+	if err == nil {
+		saved = storeEncounter(saved, e)
+		err = saveEncounters(saved)
+	}
+	if err != nil {
+		m.combatMessage = fmt.Sprintf("❌ Save failed: %v", err)
+		return m
+	}
+	m.combatSaved = saved
+	return m
+}
+
+// hpColor shades hit points green, yellow or red as they run down.
+func hpColor(c Combatant) lipgloss.Color {
+	switch {
+	case c.HP == 0:
+		return lipgloss.Color("#8A8A8A")
+	case c.HP*2 <= c.MaxHP:
+		if c.HP*4 <= c.MaxHP {
+			return lipgloss.Color("#EF4444")
+		}
+		return lipgloss.Color("#F59E0B")
+	}
+	return lipgloss.Color("#10B981")
+}
+
+func (m model) viewCombat() string {
+	if m.combatShowImport {
+		return m.viewCombatImport()
+	}
+	if m.combatShowPicker {
+		return m.viewCombatPicker()
+	}
+
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#DC2626")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#DC2626")).
+		Width(90).
+		AlignHorizontal(lipgloss.Center)
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#DC2626")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(90)
+
+	logStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#626262")).
+		Foreground(lipgloss.Color("#A0A0A0")).
+		Padding(0, 2).
+		MarginBottom(1).
+		Width(90)
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3498DB")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(90)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#DC2626"))
+
+	turnStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FFD700"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(90)
+
+	e := m.combatEncounter
+	heading := "⚔️  Initiative Tracker"
+	if e.Name != "" {
+		heading += " • " + e.Name
+	}
+	if e.Round > 0 {
+		heading += fmt.Sprintf(" • Round %d", e.Round)
+	}
+	elements := []string{titleStyle.Render(heading)}
+
+	var rows []string
+	if len(e.Combatants) == 0 {
+		rows = append(rows, "No combatants yet.\n\nPress A to add monsters or NPCs, or I to bring in saved characters.")
+	} else {
+		rows = append(rows, fmt.Sprintf("     %4s  %-22s %-12s %3s  %s", "Init", "Name", "HP", "AC", "Conditions"))
+	}
+	for i, c := range e.Combatants {
+		initiative := "—"
+		if c.Rolled {
+			initiative = fmt.Sprint(c.Initiative)
+		}
+		hp := fmt.Sprintf("%d/%d", c.HP, c.MaxHP)
+		if c.TempHP > 0 {
+			hp += fmt.Sprintf(" +%d", c.TempHP)
+		}
+		var conditions []string
+		for _, condition := range c.Conditions {
+			conditions = append(conditions, formatCondition(condition))
+		}
+		if c.HP == 0 {
+			conditions = append([]string{"💀 down"}, conditions...)
+		}
+
+		turn := "  "
+		if e.Round > 0 && i == e.Turn {
+			turn = "⚔ "
+		}
+		name := []rune(c.Name)
+		if len(name) > 22 {
+			name = append(name[:21], '…')
+		}
+		hpText := lipgloss.NewStyle().Foreground(hpColor(c)).Render(fmt.Sprintf("%-12s", hp))
+		rest := fmt.Sprintf("%3d  %s", c.AC, strings.Join(conditions, ", "))
+		switch {
+		case i == m.combatCursor:
+			rows = append(rows, selectedStyle.Render(fmt.Sprintf("▶ %s%4s  %-22s", turn, initiative, string(name)))+" "+hpText+" "+rest)
+		case turn != "  ":
+			rows = append(rows, turnStyle.Render(fmt.Sprintf("  %s%4s  %-22s", turn, initiative, string(name)))+" "+hpText+" "+rest)
+		default:
+			rows = append(rows, fmt.Sprintf("  %s%4s  %-22s", turn, initiative, string(name))+" "+hpText+" "+rest)
+		}
+	}
+	elements = append(elements, panelStyle.Render(strings.Join(rows, "\n")))
+
+	if len(e.Log) > 0 {
+		elements = append(elements, logStyle.Render(strings.Join(e.Log[max(0, len(e.Log)-5):], "\n")))
+	}
+	if m.combatPrompt != "" {
+		label := combatPromptLabels[m.combatPrompt]
+		if m.combatPrompt != "add" && m.combatPrompt != "save" && m.combatCursor < len(e.Combatants) {
+			label = e.Combatants[m.combatCursor].Name + " • " + label
+		}
+		elements = append(elements, inputStyle.Render(label+"\n"+fmt.Sprintf("▶ %s█", m.combatInput)))
+	}
+	if m.combatMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.combatMessage))
+	}
+
+	help := "↑/↓ select • A add • I add saved characters • R roll initiative • N/Space next • P back\n" +
+		"D damage • H heal • T temp HP • C condition • E set initiative • X remove\n" +
+		"S save • L saved encounters • Shift+X new encounter • ESC back"
+	if m.combatPrompt != "" {
+		help = "Enter to confirm • ESC to cancel"
+	}
+	elements = append(elements, helpStyle.Render(help))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
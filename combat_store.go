@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type encountersFile struct {
+	Encounters []Encounter `json:"encounters"`
+}
+
+func getEncountersFilePath() string {
+	return filepath.Join(getDataDir(), "encounters.json")
+}
+
+// loadEncounters reads the saved encounters. A missing file means none are
+// saved, but a file that can't be read is an error, and saveEncounters won't
+// write over it.
+func loadEncounters() ([]Encounter, error) {
+	data, err := os.ReadFile(getEncountersFilePath())
+	if os.IsNotExist(err) {
+		return []Encounter{}, nil
+	}
+	if err != nil {
+		return []Encounter{}, err
+	}
+
+	var file encountersFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return []Encounter{}, fmt.Errorf("%s is damaged (%s) • fix or move it to save encounters again", getEncountersFilePath(), describeJSONError(data, 0, err))
+	}
+	return file.Encounters, nil
+}
+
+// saveEncounters writes the saved encounters, most recently played first.
+func saveEncounters(encounters []Encounter) error {
+	if _, err := loadEncounters(); err != nil {
+		return err
+	}
+	sort.SliceStable(encounters, func(i, j int) bool {
+		return encounters[i].Updated.After(encounters[j].Updated)
+	})
+	data, err := json.MarshalIndent(encountersFile{Encounters: encounters}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getEncountersFilePath(), data, 0644)
+}
+
+// findEncounter returns the index of the saved encounter with the given
+// name, ignoring case, or -1.
+func findEncounter(encounters []Encounter, name string) int {
+	for i, encounter := range encounters {
+		if strings.EqualFold(encounter.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// storeEncounter saves e under its name, replacing any encounter with the
+// same name.
+func storeEncounter(encounters []Encounter, e Encounter) []Encounter {
+	e.Updated = time.Now()
+	e.Combatants = append([]Combatant{}, e.Combatants...)
+	if i := findEncounter(encounters, e.Name); i >= 0 {
+		encounters[i] = e
+		return encounters
+	}
+	return append(encounters, e)
+}
+
+func (m model) updateCombatPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "l":
+		m.combatShowPicker = false
+		m.combatMessage = ""
+	case "up", "k":
+		if m.combatPickerCursor > 0 {
+			m.combatPickerCursor--
+		}
+	case "down", "j":
+		if m.combatPickerCursor < len(m.combatSaved)-1 {
+			m.combatPickerCursor++
+		}
+	case "enter":
+		if m.combatPickerCursor < len(m.combatSaved) {
+			e := m.combatSaved[m.combatPickerCursor]
+			m.combatEncounter = e
+			m.combatCursor = 0
+			if e.Round > 0 {
+				m.combatCursor = e.Turn
+			}
+			m.combatShowPicker = false
+			m.combatMessage = fmt.Sprintf("✅ Resumed %q", e.Name)
+		}
+	case "d":
+		if m.combatPickerCursor < len(m.combatSaved) {
+			name := m.combatSaved[m.combatPickerCursor].Name
+			saved := append(append([]Encounter{}, m.combatSaved[:m.combatPickerCursor]...), m.combatSaved[m.combatPickerCursor+1:]...)
+			if err := saveEncounters(saved); err != nil {
+				m.combatMessage = fmt.Sprintf("❌ Delete failed: %v", err)
+				break
+			}
+			m.combatSaved = saved
+			m.combatMessage = fmt.Sprintf("✅ Deleted %q", name)
+			if strings.EqualFold(m.combatEncounter.Name, name) {
+				// Keep playing it, but stop saving it back under the old name
+				m.combatEncounter.Name = ""
+			}
+			if m.combatPickerCursor > 0 && m.combatPickerCursor >= len(m.combatSaved) {
+				m.combatPickerCursor--
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewCombatPicker() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#DC2626")).
+		Padding(1, 2).
+		MarginBottom(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#DC2626")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#DC2626")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(70)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#DC2626"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render("💾 Saved Encounters")
+
+	var rows []string
+	if len(m.combatSaved) == 0 {
+		rows = append(rows, "No saved encounters yet.\n\nPress S in the tracker to save one; it then saves itself as you play.")
+	}
+	for i, e := range m.combatSaved {
+		round := "not started"
+		if e.Round > 0 {
+			round = fmt.Sprintf("round %d", e.Round)
+		}
+		row := fmt.Sprintf("%-22s %2d in the fight • %-11s • %s", e.Name, len(e.Combatants), round, formatTimeRelative(e.Updated))
+		if i == m.combatPickerCursor {
+			row = selectedStyle.Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	elements := []string{title, listStyle.Render(strings.Join(rows, "\n"))}
+	if m.combatMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.combatMessage))
+	}
+	elements = append(elements, helpStyle.Render("↑/↓ to select • Enter to resume • D to delete • ESC to close"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
+
+func (m model) updateCombatImport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "i":
+		m.combatShowImport = false
+	case "up", "k":
+		if m.combatImportCursor > 0 {
+			m.combatImportCursor--
+		}
+	case "down", "j":
+		if m.combatImportCursor < len(m.combatRoster)-1 {
+			m.combatImportCursor++
+		}
+	case "enter", " ":
+		if m.combatImportCursor < len(m.combatRoster) {
+			c := m.characterCombatant(m.combatRoster[m.combatImportCursor])
+			c.Name = uniqueCombatantName(m.combatEncounter, c.Name)
+			e, err := addCombatant(m.combatEncounter, c)
+			if err != nil {
+				m.combatMessage = "❌ " + err.Error()
+				break
+			}
+			m = m.storeCombat(e, fmt.Sprintf("✅ Added %s", c.Name))
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewCombatImport() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#DC2626")).
+		Padding(1, 2).
+		MarginBottom(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#DC2626")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#DC2626")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(70)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#DC2626"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render("🧙 Add Saved Characters")
+
+	var rows []string
+	if len(m.combatRoster) == 0 {
+		rows = append(rows, "No saved characters yet.\n\nSave characters with W in the RPG Character Creator.")
+	}
+	for i, character := range m.combatRoster {
+		c := m.characterCombatant(character)
+		row := fmt.Sprintf("%-24s Lv %-2d %-10s HP %-3d AC %-2d Init %s",
+			characterDisplayName(character), max(1, character.Level), character.Class, c.MaxHP, c.AC, formatModifier(c.InitMod))
+		if i == m.combatImportCursor {
+			row = selectedStyle.Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	elements := []string{title, listStyle.Render(strings.Join(rows, "\n"))}
+	if m.combatMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.combatMessage))
+	}
+	elements = append(elements, helpStyle.Render("↑/↓ to select • Enter to add to the encounter • ESC when done"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
+	"os"
 	"strings"
 	"time"
 
@@ -13,11 +13,81 @@ import (
 func (m model) updateDiceRoller(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.diceShowHistory {
+			return m.updateDiceHistory(msg)
+		}
+		if m.diceShowCalc {
+			return m.updateDiceCalculator(msg)
+		}
+		if m.diceShowMacros {
+			return m.updateDiceMacros(msg)
+		}
+		if m.diceShowTray {
+			return m.updateDiceTray(msg)
+		}
+		if m.diceInputMode {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc", "tab":
+				m.diceInputMode = false
+				m.diceMessage = ""
+			case "enter":
+				if strings.TrimSpace(m.diceInput) != "" && !m.diceRolling {
+					return m.rollDice("", m.diceInput)
+				}
+			case "backspace":
+				if len(m.diceInput) > 0 {
+					m.diceInput = m.diceInput[:len(m.diceInput)-1]
+					m.diceMessage = ""
+				}
+			default:
+				if len(msg.String()) == 1 {
+					m.diceInput += msg.String()
+					m.diceMessage = ""
+				}
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "esc":
 			m.state = menuView
+		case "tab":
+			m.diceInputMode = true
+			m.diceMessage = ""
+		case "p":
+			m.diceShowCalc = true
+			m.diceMessage = ""
+			if m.diceCalcInput == "" {
+				m.diceCalcInput = m.diceInput
+			}
+		case "t":
+			m.diceShowTray = true
+			m.diceMessage = ""
+		case "m":
+			m.diceShowMacros = true
+			m.diceMacroSearch = ""
+			m.diceMacroCursor = 0
+			m.diceMessage = ""
+			if macros, err := loadDiceMacros(); err != nil {
+				m.diceMessage = "❌ " + err.Error()
+			} else {
+				m.diceMacros = macros
+			}
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Hotkeys run saved macros in the order the macro list shows them
+			index := int(msg.String()[0] - '1')
+			if index < len(m.diceMacros) && !m.diceRolling {
+				macro := m.diceMacros[index]
+				return m.rollDice(macro.Name, macro.Expression)
+			}
+		case "h":
+			m.diceShowHistory = true
+			m.diceHistoryCursor = 0
+			m.diceMessage = ""
 		case "up", "k":
 			if m.diceCursor > 0 {
 				m.diceCursor--
@@ -27,30 +97,9 @@ func (m model) updateDiceRoller(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.diceCursor++
 			}
 		case "enter", " ":
-			selectedDice := m.diceTypes[m.diceCursor]
-			m.diceType = selectedDice
-			m.diceRolling = true
-			m.diceRollTime = time.Now()
-			
-			// Roll the dice based on type
-			switch selectedDice {
-			case "d4":
-				m.diceResult = rand.Intn(4) + 1
-			case "d6":
-				m.diceResult = rand.Intn(6) + 1
-			case "d8":
-				m.diceResult = rand.Intn(8) + 1
-			case "d10":
-				m.diceResult = rand.Intn(10) + 1
-			case "d12":
-				m.diceResult = rand.Intn(12) + 1
-			case "d20":
-				m.diceResult = rand.Intn(20) + 1
+			if !m.diceRolling {
+				return m.rollDice("", "1"+m.diceTypes[m.diceCursor])
 			}
-			
-			return m, tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
-				return t
-			})
 		}
 	case time.Time:
 		if m.diceRolling && time.Since(m.diceRollTime) > time.Second*2 {
@@ -65,7 +114,228 @@ func (m model) updateDiceRoller(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// rollDice rolls a dice expression and starts the rolling animation. The
+// label names the roll in the result and history, e.g. a macro name.
+func (m model) rollDice(label, expression string) (tea.Model, tea.Cmd) {
+	result, err := rollDiceExpression(expression)
+	if err != nil {
+		m.diceMessage = "❌ " + err.Error()
+		return m, nil
+	}
+
+	m.diceHistory = append(m.diceHistory, DiceHistoryEntry{Time: time.Now(), Label: label, DiceRollResult: result})
+
+	m.diceRoll = result
+	m.diceResult = result.Total
+	m.diceType = result.Expression
+	if label != "" {
+		m.diceType = fmt.Sprintf("%s (%s)", label, result.Expression)
+	}
+	m.diceMessage = ""
+	if m.diceHistoryPersist {
+		if err := saveDiceHistory(m.diceHistory); err != nil {
+			m.diceMessage = "❌ Failed to save history: " + err.Error()
+		}
+	}
+	m.diceRolling = true
+	m.diceRollTime = time.Now()
+
+	return m, tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
+		return t
+	})
+}
+
+func (m model) updateDiceHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "h":
+		m.diceShowHistory = false
+		m.diceMessage = ""
+	case "up", "k":
+		if m.diceHistoryCursor > 0 {
+			m.diceHistoryCursor--
+		}
+	case "down", "j":
+		if m.diceHistoryCursor < len(m.diceHistory)-1 {
+			m.diceHistoryCursor++
+		}
+	case "c":
+		if len(m.diceHistory) > 0 {
+			filename, err := exportDiceHistoryCSV(m.diceHistory)
+			if err != nil {
+				m.diceMessage = "❌ Export failed: " + err.Error()
+			} else {
+				m.diceMessage = "✅ Session log saved to " + filename
+			}
+		}
+	case "m":
+		if len(m.diceHistory) > 0 {
+			filename, err := exportDiceHistoryMarkdown(m.diceHistory)
+			if err != nil {
+				m.diceMessage = "❌ Export failed: " + err.Error()
+			} else {
+				m.diceMessage = "✅ Session log saved to " + filename
+			}
+		}
+	case "p":
+		m.diceHistoryPersist = !m.diceHistoryPersist
+		if m.diceHistoryPersist {
+			if err := saveDiceHistory(m.diceHistory); err != nil {
+				m.diceMessage = "❌ Failed to save history: " + err.Error()
+				m.diceHistoryPersist = false
+			} else {
+				m.diceMessage = "✅ History will be kept between runs"
+			}
+		} else {
+			os.Remove(getDiceHistoryFilePath())
+			m.diceMessage = "History will be forgotten on exit"
+		}
+	case "x":
+		m.diceHistory = []DiceHistoryEntry{}
+		m.diceHistoryCursor = 0
+		m.diceMessage = "History cleared"
+		if m.diceHistoryPersist {
+			if err := saveDiceHistory(m.diceHistory); err != nil {
+				m.diceMessage = "❌ Failed to save history: " + err.Error()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateDiceCalculator(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.diceShowCalc = false
+		m.diceMessage = ""
+	case "enter":
+		if strings.TrimSpace(m.diceCalcInput) == "" {
+			return m, nil
+		}
+		report, err := buildDiceProbabilityReport(m.diceCalcInput)
+		if err != nil {
+			m.diceMessage = "❌ " + err.Error()
+			return m, nil
+		}
+		m.diceCalcReport = report
+		m.diceMessage = ""
+	case "backspace":
+		if len(m.diceCalcInput) > 0 {
+			m.diceCalcInput = m.diceCalcInput[:len(m.diceCalcInput)-1]
+		}
+	default:
+		if len(msg.String()) == 1 {
+			m.diceCalcInput += msg.String()
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewDiceCalculator() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#FF6B6B")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF6B6B")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3498DB")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(70)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#E74C3C")).
+		Bold(true).
+		AlignHorizontal(lipgloss.Center).
+		MarginBottom(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render("📊 Dice Probability Calculator")
+	input := inputStyle.Render("Expression, target or comparison (e.g. 2d6+3 >= 10, 1d20+5 vs 1d20+2, 2d20kh1 | 1d20+2):\n" + fmt.Sprintf("▶ %s█", m.diceCalcInput))
+	
+	elements := []string{title, input}
+	if m.diceMessage != "" {
+		elements = append(elements, errorStyle.Render(m.diceMessage))
+	}
+	if m.diceCalcReport != "" {
+		elements = append(elements, m.diceCalcReport)
+	}
+	elements = append(elements, helpStyle.Render("Enter to calculate • ESC to go back to the roller"))
+	
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
+
+// formatDieValue renders one die, marking dice that were dropped, rerolled or exploded.
+func formatDieValue(d DieRoll, sides int) string {
+	value := fmt.Sprint(d.Value)
+	if sides == 0 {
+		value = map[int]string{-1: "−", 0: "0", 1: "+"}[d.Value]
+	}
+	if d.Exploded {
+		value += "!"
+	}
+
+	ignoredStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8A8A8A")).Strikethrough(true)
+	if d.Rerolled {
+		return ignoredStyle.Render(value) + "↺"
+	}
+	if d.Dropped {
+		return ignoredStyle.Render(value)
+	}
+	if sides > 0 && (d.Value == sides || d.Value == 1) {
+		return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFD700")).Render(value)
+	}
+	return value
+}
+
+// formatDiceRoll lists every group of a roll with its individual dice.
+func formatDiceRoll(result DiceRollResult) string {
+	var lines []string
+	for _, group := range result.Groups {
+		var dice []string
+		for _, d := range group.Dice {
+			dice = append(dice, formatDieValue(d, group.Sides))
+		}
+		lines = append(lines, fmt.Sprintf("%s: [%s] = %d", group.Notation, strings.Join(dice, ", "), group.Total))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m model) viewDiceRoller() string {
+	if m.diceShowHistory {
+		return m.viewDiceHistory()
+	}
+	if m.diceShowCalc {
+		return m.viewDiceCalculator()
+	}
+	if m.diceShowMacros {
+		return m.viewDiceMacros()
+	}
+	if m.diceShowTray {
+		return m.viewDiceTray()
+	}
+	
 	// Define styles
 	containerStyle := lipgloss.NewStyle().
 		Width(m.width).
@@ -126,12 +396,26 @@ func (m model) viewDiceRoller() string {
 	// Build content
 	title := titleStyle.Render("🎲 Dice Roller")
 	
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3498DB")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(50)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#E74C3C")).
+		Bold(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(50).
+		MarginBottom(1)
+
 	// Dice selection menu
 	var diceOptions []string
 	for i, dice := range m.diceTypes {
 		var style lipgloss.Style
 		cursor := "  "
-		if m.diceCursor == i {
+		if m.diceCursor == i && !m.diceInputMode {
 			cursor = "🎯 "
 			style = selectedDiceStyle
 		} else {
@@ -142,6 +426,20 @@ func (m model) viewDiceRoller() string {
 	
 	diceMenu := diceMenuStyle.Render("Choose your dice:\n\n" + strings.Join(diceOptions, "\n"))
 	
+	// Expression input
+	var inputDisplay string
+	if m.diceInputMode {
+		inputText := fmt.Sprintf("▶ %s█", m.diceInput)
+		inputDisplay = inputStyle.Render("Dice expression (e.g. 4d6kh3, 2d20kl1, 1d8+1d6+3, 3d6!, 4d6r1, d%, 4dF):\n" + inputText)
+	} else if m.diceInput != "" {
+		inputDisplay = inputStyle.Render("Last expression: " + m.diceInput)
+	}
+	
+	var errorDisplay string
+	if m.diceMessage != "" {
+		errorDisplay = errorStyle.Render(m.diceMessage)
+	}
+	
 	// Result display with visual flair
 	var resultDisplay string
 	if m.diceRolling {
@@ -149,20 +447,39 @@ func (m model) viewDiceRoller() string {
 		rollingFrames := []string{"⚀", "⚁", "⚂", "⚃", "⚄", "⚅"}
 		frame := rollingFrames[int(time.Since(m.diceRollTime)/time.Millisecond/100)%len(rollingFrames)]
 		resultDisplay = rollingStyle.Render(fmt.Sprintf("🎲 Rolling %s... %s", m.diceType, frame))
-	} else if m.diceResult > 0 {
-		// Show result with visual dice
-		diceVisual := getDiceVisual(m.diceResult)
-		resultDisplay = resultStyle.Render(fmt.Sprintf("🎲 %s Result: %d\n\n%s", m.diceType, m.diceResult, diceVisual))
+	} else if m.diceType != "" {
+		// Show result with every die, plus a visual face for a single small die
+		result := fmt.Sprintf("🎲 %s Result: %d\n\n%s", m.diceType, m.diceResult, formatDiceRoll(m.diceRoll))
+		// The face shows the die itself, so skip it when a modifier changed the total
+		if len(m.diceRoll.Groups) == 1 && len(m.diceRoll.Groups[0].Dice) == 1 && m.diceRoll.Groups[0].Sides > 0 {
+			die := m.diceRoll.Groups[0].Dice[0].Value
+			if die == m.diceRoll.Total {
+				result += "\n\n" + getDieFaceVisual(m.diceRoll.Groups[0].Sides, die)
+			}
+		}
+		resultDisplay = resultStyle.Render(result)
 	}
 	
-	help := helpStyle.Render("Use ↑/↓ or j/k to navigate • Enter to roll • ESC to go back • Ctrl+C to quit")
+	var helpText string
+	if m.diceInputMode {
+		helpText = "Type a dice expression • Enter to roll • Tab/ESC to close input • Ctrl+C to quit"
+	} else {
+		helpText = "Use ↑/↓ or j/k to navigate • Enter to roll • Tab for dice expression • T for dice tray • M for macros • 1-9 to roll a macro • P for probabilities • H for history • ESC to go back"
+	}
+	help := helpStyle.Render(helpText)
 	
-	var content string
+	elements := []string{title, diceMenu}
+	if inputDisplay != "" {
+		elements = append(elements, inputDisplay)
+	}
+	if errorDisplay != "" {
+		elements = append(elements, errorDisplay)
+	}
 	if resultDisplay != "" {
-		content = lipgloss.JoinVertical(lipgloss.Center, title, diceMenu, resultDisplay, help)
-	} else {
-		content = lipgloss.JoinVertical(lipgloss.Center, title, diceMenu, help)
+		elements = append(elements, resultDisplay)
 	}
+	elements = append(elements, help)
+	content := lipgloss.JoinVertical(lipgloss.Center, elements...)
 	
 	return containerStyle.Render(content)
 }
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DiceHistoryEntry is one roll in the session log.
+type DiceHistoryEntry struct {
+	Time  time.Time `json:"time"`
+	Label string    `json:"label,omitempty"`
+	DiceRollResult
+}
+
+type diceHistoryFile struct {
+	Rolls []DiceHistoryEntry `json:"rolls"`
+}
+
+// diceTypeStats summarises every die of one type rolled this session.
+type diceTypeStats struct {
+	Sides int // 0 for Fate dice
+	Count int
+	Sum   int
+	Faces map[int]int
+	Nat20 int
+	Nat1  int
+}
+
+func getDiceHistoryFilePath() string {
+	return filepath.Join(getDataDir(), "dice-history.json")
+}
+
+// loadDiceHistory returns the persisted history, and whether persistence is
+// switched on, which is signalled by the history file existing at all. A
+// damaged file is moved aside so the next save doesn't overwrite it, and
+// the error says where it went.
+func loadDiceHistory() ([]DiceHistoryEntry, bool, error) {
+	path := getDiceHistoryFilePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []DiceHistoryEntry{}, false, nil
+	}
+
+	var file diceHistoryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		detail := describeJSONError(data, 0, err)
+		aside := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+		if renameErr := os.Rename(path, aside); renameErr != nil {
+			return []DiceHistoryEntry{}, false, fmt.Errorf("dice history is damaged (%s) and couldn't be moved aside, so rolls won't be saved: %v", detail, renameErr)
+		}
+		return []DiceHistoryEntry{}, true, fmt.Errorf("dice history was damaged (%s) • moved it to %s and started a new one", detail, aside)
+	}
+	return file.Rolls, true, nil
+}
+
+func saveDiceHistory(history []DiceHistoryEntry) error {
+	data, err := json.MarshalIndent(diceHistoryFile{Rolls: history}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getDiceHistoryFilePath(), data, 0644)
+}
+
+func diceLabel(sides int) string {
+	if sides == 0 {
+		return "dF"
+	}
+	return fmt.Sprintf("d%d", sides)
+}
+
+// computeDiceStats groups every thrown die by type. Face counts and means use
+// all dice that hit the table; nat 20s and nat 1s only count d20s that were kept.
+func computeDiceStats(history []DiceHistoryEntry) []diceTypeStats {
+	bySides := make(map[int]*diceTypeStats)
+	for _, entry := range history {
+		for _, group := range entry.Groups {
+			stats, exists := bySides[group.Sides]
+			if !exists {
+				stats = &diceTypeStats{Sides: group.Sides, Faces: make(map[int]int)}
+				bySides[group.Sides] = stats
+			}
+			for _, d := range group.Dice {
+				stats.Count++
+				stats.Sum += d.Value
+				stats.Faces[d.Value]++
+				if group.Sides == 20 && !d.Dropped && !d.Rerolled {
+					if d.Value == 20 {
+						stats.Nat20++
+					} else if d.Value == 1 {
+						stats.Nat1++
+					}
+				}
+			}
+		}
+	}
+
+	var all []diceTypeStats
+	for _, stats := range bySides {
+		all = append(all, *stats)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		// Fate dice sort last
+		if all[i].Sides == 0 || all[j].Sides == 0 {
+			return all[j].Sides == 0 && all[i].Sides != 0
+		}
+		return all[i].Sides < all[j].Sides
+	})
+	return all
+}
+
+func (s diceTypeStats) mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Sum) / float64(s.Count)
+}
+
+// histogram draws one bar character per face, scaled to the most common face.
+// Dice with more than 20 faces are bucketed so the bar stays readable.
+func (s diceTypeStats) histogram() string {
+	low, high := 1, s.Sides
+	if s.Sides == 0 {
+		low, high = -1, 1
+	}
+	faces := high - low + 1
+	width := min(faces, 20)
+
+	buckets := make([]int, width)
+	for face, count := range s.Faces {
+		if face >= low && face <= high {
+			buckets[(face-low)*width/faces] += count
+		}
+	}
+	peak := 0
+	for _, count := range buckets {
+		peak = max(peak, count)
+	}
+
+	bars := []rune(" ▁▂▃▄▅▆▇█")
+	var out strings.Builder
+	for _, count := range buckets {
+		level := 0
+		if peak > 0 {
+			level = count * (len(bars) - 1) / peak
+		}
+		out.WriteRune(bars[level])
+	}
+	return out.String()
+}
+
+// formatDiceRollPlain is the unstyled form of formatDiceRoll used in exports:
+// dropped dice are wrapped in parentheses, rerolled dice end in "r" and
+// exploded dice end in "!".
+func formatDiceRollPlain(result DiceRollResult) string {
+	var groups []string
+	for _, group := range result.Groups {
+		var dice []string
+		for _, d := range group.Dice {
+			value := fmt.Sprint(d.Value)
+			if d.Exploded {
+				value += "!"
+			}
+			if d.Rerolled {
+				value += "r"
+			} else if d.Dropped {
+				value = "(" + value + ")"
+			}
+			dice = append(dice, value)
+		}
+		groups = append(groups, fmt.Sprintf("%s: %s", group.Notation, strings.Join(dice, " ")))
+	}
+	return strings.Join(groups, "; ")
+}
+
+// diceSessionFilename is where a session log export goes: the export folder
+// that character sheets are saved to.
+func diceSessionFilename(extension string) (string, error) {
+	dir := getExportDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("Dice_Session_%s.%s", time.Now().Format("2006-01-02_15-04-05"), extension)), nil
+}
+
+func exportDiceHistoryCSV(history []DiceHistoryEntry) (string, error) {
+	filename, err := diceSessionFilename("csv")
+	if err != nil {
+		return filename, err
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return filename, err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"time", "label", "expression", "dice", "total"})
+	for _, entry := range history {
+		writer.Write([]string{
+			entry.Time.Format(time.RFC3339),
+			entry.Label,
+			entry.Expression,
+			formatDiceRollPlain(entry.DiceRollResult),
+			fmt.Sprint(entry.Total),
+		})
+	}
+	writer.Flush()
+	return filename, writer.Error()
+}
+
+func exportDiceHistoryMarkdown(history []DiceHistoryEntry) (string, error) {
+	var content strings.Builder
+
+	content.WriteString("# Dice Session Log\n\n")
+	content.WriteString("| Time | Roll | Expression | Dice | Total |\n")
+	content.WriteString("|------|------|------------|------|------:|\n")
+	for _, entry := range history {
+		dice := strings.ReplaceAll(formatDiceRollPlain(entry.DiceRollResult), "|", "\\|")
+		label := strings.ReplaceAll(entry.Label, "|", "\\|")
+		content.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | %d |\n", entry.Time.Format("15:04:05"), label, entry.Expression, dice, entry.Total))
+	}
+
+	content.WriteString("\n## Statistics\n\n")
+	content.WriteString("| Die | Rolled | Mean | Distribution | Nat 20 | Nat 1 |\n")
+	content.WriteString("|-----|-------:|-----:|--------------|-------:|------:|\n")
+	for _, stats := range computeDiceStats(history) {
+		nat20, nat1 := "", ""
+		if stats.Sides == 20 {
+			nat20, nat1 = fmt.Sprint(stats.Nat20), fmt.Sprint(stats.Nat1)
+		}
+		content.WriteString(fmt.Sprintf("| %s | %d | %.2f | `%s` | %s | %s |\n", diceLabel(stats.Sides), stats.Count, stats.mean(), stats.histogram(), nat20, nat1))
+	}
+
+	content.WriteString("\nGenerated by Big Dumb Toolbox Dice Roller\n")
+
+	filename, err := diceSessionFilename("md")
+	if err != nil {
+		return filename, err
+	}
+	err = os.WriteFile(filename, []byte(content.String()), 0644)
+	return filename, err
+}
+
+func (m model) viewDiceHistory() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#FF6B6B")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF6B6B")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF6B6B")).
+		Padding(0, 2).
+		MarginBottom(1).
+		Width(70)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#4ECDC4"))
+
+	messageStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#10B981")).
+		Bold(true).
+		AlignHorizontal(lipgloss.Center).
+		MarginBottom(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	persistence := "this session only"
+	if m.diceHistoryPersist {
+		persistence = "saved between runs"
+	}
+	title := titleStyle.Render(fmt.Sprintf("📜 Roll History (%d rolls, %s)", len(m.diceHistory), persistence))
+
+	// Newest rolls first, windowed around the cursor
+	const visibleRows = 10
+	var rows []string
+	if len(m.diceHistory) == 0 {
+		rows = append(rows, "No rolls yet this session.")
+	} else {
+		start := max(0, min(m.diceHistoryCursor-visibleRows/2, len(m.diceHistory)-visibleRows))
+		end := min(len(m.diceHistory), start+visibleRows)
+		for i := start; i < end; i++ {
+			entry := m.diceHistory[len(m.diceHistory)-1-i]
+			name := entry.Expression
+			if entry.Label != "" {
+				name = entry.Label
+			}
+			row := fmt.Sprintf("%s  %-14s %4d   %s", entry.Time.Format("15:04:05"), name, entry.Total, formatDiceRollPlain(entry.DiceRollResult))
+			if len([]rune(row)) > 64 {
+				row = string([]rune(row)[:63]) + "…"
+			}
+			if i == m.diceHistoryCursor {
+				row = selectedStyle.Render(row)
+			}
+			rows = append(rows, row)
+		}
+	}
+	historyPanel := panelStyle.Render(strings.Join(rows, "\n"))
+
+	var statLines []string
+	statLines = append(statLines, fmt.Sprintf("%-5s %6s %6s  %-22s %s", "Die", "Rolled", "Mean", "Distribution", "Nat20/Nat1"))
+	for _, stats := range computeDiceStats(m.diceHistory) {
+		nat := ""
+		if stats.Sides == 20 {
+			nat = fmt.Sprintf("%d / %d", stats.Nat20, stats.Nat1)
+		}
+		statLines = append(statLines, fmt.Sprintf("%-5s %6d %6.2f  %-22s %s", diceLabel(stats.Sides), stats.Count, stats.mean(), stats.histogram(), nat))
+	}
+	statsPanel := panelStyle.Render(strings.Join(statLines, "\n"))
+
+	elements := []string{title, historyPanel, statsPanel}
+	if m.diceMessage != "" {
+		elements = append(elements, messageStyle.Render(m.diceMessage))
+	}
+	elements = append(elements, helpStyle.Render("↑/↓ to scroll • C to export CSV • M to export Markdown • P to toggle saving between runs • X to clear • H/ESC to close"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
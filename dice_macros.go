@@ -0,0 +1,402 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RollMacro is a named dice expression, grouped under the character it belongs to.
+type RollMacro struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Character  string `json:"character"`
+}
+
+type diceMacroEntry struct {
+	Macro     RollMacro
+	Suggested bool
+}
+
+func getDiceMacrosFilePath() string {
+	return filepath.Join(getDataDir(), "dice-macros.json")
+}
+
+// loadDiceMacros reads the saved macros. A missing file means none are
+// saved, but a file that can't be read is an error, and saveDiceMacros
+// won't write over it.
+func loadDiceMacros() ([]RollMacro, error) {
+	data, err := os.ReadFile(getDiceMacrosFilePath())
+	if os.IsNotExist(err) {
+		return []RollMacro{}, nil
+	}
+	if err != nil {
+		return []RollMacro{}, err
+	}
+
+	var macros []RollMacro
+	if err := json.Unmarshal(data, &macros); err != nil {
+		return []RollMacro{}, fmt.Errorf("%s is damaged (%s) • fix or move it to save macros again", getDiceMacrosFilePath(), describeJSONError(data, 0, err))
+	}
+	sortDiceMacros(macros)
+	return macros, nil
+}
+
+func saveDiceMacros(macros []RollMacro) error {
+	if _, err := loadDiceMacros(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(macros, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getDiceMacrosFilePath(), data, 0644)
+}
+
+// sortDiceMacros groups macros by character, keeping insertion order within a group.
+func sortDiceMacros(macros []RollMacro) {
+	sort.SliceStable(macros, func(i, j int) bool {
+		return strings.ToLower(macros[i].Character) < strings.ToLower(macros[j].Character)
+	})
+}
+
+// parseDiceMacro reads "[Character/]Name = expression", validating the expression.
+func parseDiceMacro(input, defaultCharacter string) (RollMacro, error) {
+	name, expression, found := strings.Cut(input, "=")
+	if !found {
+		return RollMacro{}, fmt.Errorf("use the form: Name = expression")
+	}
+
+	macro := RollMacro{Character: defaultCharacter, Name: strings.TrimSpace(name), Expression: strings.TrimSpace(expression)}
+	if character, rest, ok := strings.Cut(macro.Name, "/"); ok {
+		macro.Character = strings.TrimSpace(character)
+		macro.Name = strings.TrimSpace(rest)
+	}
+	if macro.Name == "" {
+		return RollMacro{}, fmt.Errorf("macro name is empty")
+	}
+	if macro.Character == "" {
+		macro.Character = "General"
+	}
+	if _, err := parseDiceExpression(macro.Expression); err != nil {
+		return RollMacro{}, err
+	}
+	return macro, nil
+}
+
+// fuzzyMatch reports whether every character of pattern appears in text in order.
+func fuzzyMatch(text, pattern string) bool {
+	text, pattern = strings.ToLower(text), strings.ToLower(pattern)
+	for _, c := range pattern {
+		i := strings.IndexRune(text, c)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(c)):]
+	}
+	return true
+}
+
+func formatModifier(mod int) string {
+	return fmt.Sprintf("%+d", mod)
+}
+
+// suggestCharacterMacros builds attack and damage rolls for every weapon the
+// loaded character carries, plus an initiative roll.
+func suggestCharacterMacros(label string, stats DerivedStats) []RollMacro {
+	macros := []RollMacro{{Name: "Initiative", Expression: "1d20" + formatModifier(stats.Initiative), Character: label}}
+	for _, attack := range stats.Attacks {
+		macros = append(macros,
+			RollMacro{Name: attack.Name + " attack", Expression: "1d20" + formatModifier(attack.AttackBonus), Character: label},
+			RollMacro{Name: attack.Name + " damage", Expression: attack.Damage, Character: label},
+		)
+	}
+	return macros
+}
+
+// diceMacroEntries lists saved macros, then suggestions for the loaded
+// character that aren't saved yet, filtered by the fuzzy search.
+func (m model) diceMacroEntries() []diceMacroEntry {
+	var entries []diceMacroEntry
+	saved := make(map[string]bool)
+	for _, macro := range m.diceMacros {
+		saved[macro.Character+"/"+macro.Name] = true
+		if fuzzyMatch(macro.Character+" "+macro.Name+" "+macro.Expression, m.diceMacroSearch) {
+			entries = append(entries, diceMacroEntry{Macro: macro})
+		}
+	}
+	for _, macro := range m.rpgMacroSuggestions() {
+		if !saved[macro.Character+"/"+macro.Name] && fuzzyMatch(macro.Character+" "+macro.Name+" "+macro.Expression, m.diceMacroSearch) {
+			entries = append(entries, diceMacroEntry{Macro: macro, Suggested: true})
+		}
+	}
+	return entries
+}
+
+func (m model) rpgMacroSuggestions() []RollMacro {
+	if len(m.rpgCharacter.Abilities) == 0 || m.rpgCharacter.Class == "" {
+		return nil
+	}
+	return suggestCharacterMacros(m.rpgMacroGroup(), m.rpgPack.deriveStats(m.rpgCharacter))
+}
+
+// rpgMacroGroup is the macro group for the loaded character: its name, so
+// two characters of one class keep their own macros, or the class for an
+// unnamed character.
+func (m model) rpgMacroGroup() string {
+	if m.rpgCharacter.Name != "" {
+		return m.rpgCharacter.Name
+	}
+	return m.rpgCharacter.Class
+}
+
+func (m model) updateDiceMacros(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.diceMacroAdding {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.diceMacroAdding = false
+			m.diceMacroInput = ""
+			m.diceMessage = ""
+		case "enter":
+			defaultCharacter := m.rpgMacroGroup()
+			if len(m.rpgCharacter.Abilities) == 0 {
+				defaultCharacter = "General"
+			}
+			macro, err := parseDiceMacro(m.diceMacroInput, defaultCharacter)
+			if err != nil {
+				m.diceMessage = "❌ " + err.Error()
+				return m, nil
+			}
+			macros := append(append([]RollMacro{}, m.diceMacros...), macro)
+			sortDiceMacros(macros)
+			if err := saveDiceMacros(macros); err != nil {
+				m.diceMessage = "❌ Failed to save macro: " + err.Error()
+				return m, nil // keep what was typed
+			}
+			m.diceMacros = macros
+			m.diceMessage = "✅ Saved macro " + macro.Name
+			m.diceMacroAdding = false
+			m.diceMacroInput = ""
+		case "backspace":
+			if len(m.diceMacroInput) > 0 {
+				m.diceMacroInput = m.diceMacroInput[:len(m.diceMacroInput)-1]
+			}
+		default:
+			if len(msg.String()) == 1 {
+				m.diceMacroInput += msg.String()
+			}
+		}
+		return m, nil
+	}
+
+	entries := m.diceMacroEntries()
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.diceShowMacros = false
+		m.diceMacroSearch = ""
+		m.diceMessage = ""
+	case "up":
+		if m.diceMacroCursor > 0 {
+			m.diceMacroCursor--
+		}
+	case "down":
+		if m.diceMacroCursor < len(entries)-1 {
+			m.diceMacroCursor++
+		}
+	case "enter":
+		if m.diceMacroCursor < len(entries) {
+			macro := entries[m.diceMacroCursor].Macro
+			m.diceShowMacros = false
+			m.diceMacroSearch = ""
+			return m.rollDice(macro.Name, macro.Expression)
+		}
+	case "ctrl+n":
+		m.diceMacroAdding = true
+		m.diceMacroInput = ""
+		m.diceMessage = ""
+	case "ctrl+d":
+		if m.diceMacroCursor < len(entries) && !entries[m.diceMacroCursor].Suggested {
+			target := entries[m.diceMacroCursor].Macro
+			macros := append([]RollMacro{}, m.diceMacros...)
+			for i, macro := range macros {
+				if macro == target {
+					macros = append(macros[:i], macros[i+1:]...)
+					break
+				}
+			}
+			if err := saveDiceMacros(macros); err != nil {
+				m.diceMessage = "❌ Failed to delete macro: " + err.Error()
+			} else {
+				m.diceMacros = macros
+				m.diceMessage = "✅ Deleted macro " + target.Name
+			}
+			if m.diceMacroCursor > 0 && m.diceMacroCursor >= len(m.diceMacroEntries()) {
+				m.diceMacroCursor--
+			}
+		}
+	case "ctrl+s":
+		macros := append([]RollMacro{}, m.diceMacros...)
+		for _, entry := range entries {
+			if entry.Suggested {
+				macros = append(macros, entry.Macro)
+			}
+		}
+		if added := len(macros) - len(m.diceMacros); added > 0 {
+			sortDiceMacros(macros)
+			if err := saveDiceMacros(macros); err != nil {
+				m.diceMessage = "❌ Failed to save macros: " + err.Error()
+			} else {
+				m.diceMacros = macros
+				m.diceMessage = fmt.Sprintf("✅ Saved %d suggested macros", added)
+			}
+		}
+	case "backspace":
+		if len(m.diceMacroSearch) > 0 {
+			m.diceMacroSearch = m.diceMacroSearch[:len(m.diceMacroSearch)-1]
+			m.diceMacroCursor = 0
+		}
+	default:
+		if len(msg.String()) == 1 {
+			m.diceMacroSearch += msg.String()
+			m.diceMacroCursor = 0
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewDiceMacros() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#FF6B6B")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF6B6B")).
+		Width(60).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF6B6B")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(60)
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3498DB")).
+		Padding(0, 2).
+		MarginBottom(1).
+		Width(60)
+
+	groupStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#4ECDC4"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#4ECDC4")).
+		Padding(0, 1)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Padding(0, 1)
+
+	suggestedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8A8A8A")).
+		Italic(true).
+		Padding(0, 1)
+
+	messageStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#10B981")).
+		Bold(true).
+		AlignHorizontal(lipgloss.Center).
+		MarginBottom(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(60)
+
+	title := titleStyle.Render("📖 Roll Macros")
+
+	var input string
+	if m.diceMacroAdding {
+		input = inputStyle.Render("New macro ([Character/]Name = expression):\n" + fmt.Sprintf("▶ %s█", m.diceMacroInput))
+	} else {
+		input = inputStyle.Render(fmt.Sprintf("🔍 %s█", m.diceMacroSearch))
+	}
+
+	entries := m.diceMacroEntries()
+	var lines []string
+	lastGroup := ""
+	for i, entry := range entries {
+		group := entry.Macro.Character
+		if entry.Suggested {
+			group = "Suggested for " + group
+		}
+		if group != lastGroup {
+			if lastGroup != "" {
+				lines = append(lines, "")
+			}
+			lines = append(lines, groupStyle.Render(group))
+			lastGroup = group
+		}
+
+		hotkey := "   "
+		if !entry.Suggested && i < 9 && m.diceMacroSearch == "" {
+			hotkey = fmt.Sprintf("%d. ", i+1)
+		}
+		line := fmt.Sprintf("%s%-28s %s", hotkey, entry.Macro.Name, entry.Macro.Expression)
+		switch {
+		case i == m.diceMacroCursor && !m.diceMacroAdding:
+			lines = append(lines, selectedStyle.Render("▶ "+line))
+		case entry.Suggested:
+			lines = append(lines, suggestedStyle.Render("  "+line))
+		default:
+			lines = append(lines, normalStyle.Render("  "+line))
+		}
+	}
+	if len(entries) == 0 {
+		if m.diceMacroSearch != "" {
+			lines = append(lines, "No macros match your search.")
+		} else {
+			lines = append(lines, "No macros yet!\n\nPress Ctrl+N to add one, or create a character\nin the RPG Character Creator for suggestions.")
+		}
+	}
+	list := listStyle.Render(strings.Join(lines, "\n"))
+
+	elements := []string{title, input, list}
+	if m.diceMessage != "" {
+		elements = append(elements, messageStyle.Render(m.diceMessage))
+	}
+
+	var helpText string
+	if m.diceMacroAdding {
+		helpText = "Type the macro • Enter to save • ESC to cancel"
+	} else {
+		helpText = "Type to search • ↑/↓ to select • Enter to roll • Ctrl+N to add • Ctrl+D to delete • Ctrl+S to save suggestions • ESC to go back"
+	}
+	elements = append(elements, helpStyle.Render(helpText))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
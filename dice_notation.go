@@ -0,0 +1,552 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Limits keep a typo like 1000000d6 from freezing the UI.
+const (
+	maxDiceCount      = 100
+	maxDiceSides      = 1000
+	maxDiceExplosions = 100
+	maxDiceTotal      = 1000000000 // products past this are rejected rather than wrapped
+)
+
+// DieRoll is a single die thrown while evaluating an expression.
+type DieRoll struct {
+	Value    int  `json:"value"`
+	Dropped  bool `json:"dropped,omitempty"`  // discarded by a keep/drop modifier
+	Rerolled bool `json:"rerolled,omitempty"` // replaced by a reroll, never counts
+	Exploded bool `json:"exploded,omitempty"` // extra die added by an explosion
+}
+
+// DiceGroupResult holds every die rolled for one NdM term of an expression.
+type DiceGroupResult struct {
+	Notation string    `json:"notation"`
+	Sides    int       `json:"sides"` // 0 for Fate dice
+	Dice     []DieRoll `json:"dice"`
+	Total    int       `json:"total"`
+}
+
+// DiceRollResult is the outcome of rolling a full dice expression.
+type DiceRollResult struct {
+	Expression string            `json:"expression"`
+	Groups     []DiceGroupResult `json:"groups"`
+	Total      int               `json:"total"`
+}
+
+// DiceParseError points at the offending position in the expression.
+type DiceParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e *DiceParseError) Error() string {
+	return fmt.Sprintf("%s (at position %d)", e.Msg, e.Pos+1)
+}
+
+type diceNode interface {
+	eval(result *DiceRollResult) (int, error)
+	distribution() (diceDistribution, error)
+	String() string
+}
+
+type numberNode struct {
+	Value int
+}
+
+type negateNode struct {
+	X diceNode
+}
+
+type binaryNode struct {
+	Op          byte
+	Left, Right diceNode
+}
+
+type diceGroupNode struct {
+	Count   int
+	Sides   int // 0 for Fate dice
+	Keep    string // "", "kh", "kl", "dh" or "dl"
+	KeepN   int
+	Explode bool
+	Reroll  string // "", "=", "<" or ">"
+	RerollN int
+}
+
+func (n numberNode) eval(*DiceRollResult) (int, error) {
+	return n.Value, nil
+}
+
+func (n numberNode) String() string {
+	return strconv.Itoa(n.Value)
+}
+
+func (n negateNode) eval(result *DiceRollResult) (int, error) {
+	v, err := n.X.eval(result)
+	return -v, err
+}
+
+func (n negateNode) String() string {
+	return "-" + n.X.String()
+}
+
+func (n binaryNode) eval(result *DiceRollResult) (int, error) {
+	left, err := n.Left.eval(result)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.Right.eval(result)
+	if err != nil {
+		return 0, err
+	}
+	switch n.Op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		product, ok := multiplyDiceTotals(left, right)
+		if !ok {
+			return 0, fmt.Errorf("%s goes past %d", n, maxDiceTotal)
+		}
+		return product, nil
+	case '/':
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero in %s", n)
+		}
+		return floorDiv(left, right), nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", n.Op)
+}
+
+func (n binaryNode) String() string {
+	left, right := n.Left.String(), n.Right.String()
+	if b, ok := n.Left.(binaryNode); ok && precedence(b.Op) < precedence(n.Op) {
+		left = "(" + left + ")"
+	}
+	if b, ok := n.Right.(binaryNode); ok && precedence(b.Op) <= precedence(n.Op) {
+		right = "(" + right + ")"
+	}
+	return left + string(n.Op) + right
+}
+
+func precedence(op byte) int {
+	if op == '*' || op == '/' {
+		return 2
+	}
+	return 1
+}
+
+// floorDiv rounds toward negative infinity so 5/2 and -5/2 behave like tabletop "round down".
+// multiplyDiceTotals multiplies two totals, reporting false instead of
+// overflowing when the product is larger than maxDiceTotal either way.
+func multiplyDiceTotals(a, b int) (int, bool) {
+	absA, absB := a, b
+	if absA < 0 {
+		absA = -absA
+	}
+	if absB < 0 {
+		absB = -absB
+	}
+	if absA != 0 && absB > maxDiceTotal/absA {
+		return 0, false
+	}
+	return a * b, true
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+func (n diceGroupNode) String() string {
+	var s strings.Builder
+	s.WriteString(strconv.Itoa(n.Count))
+	switch n.Sides {
+	case 0:
+		s.WriteString("dF")
+	case 100:
+		s.WriteString("d%")
+	default:
+		s.WriteString("d" + strconv.Itoa(n.Sides))
+	}
+	if n.Explode {
+		s.WriteString("!")
+	}
+	switch n.Reroll {
+	case "=":
+		s.WriteString("r" + strconv.Itoa(n.RerollN))
+	case "<", ">":
+		s.WriteString("r" + n.Reroll + strconv.Itoa(n.RerollN))
+	}
+	if n.Keep != "" {
+		s.WriteString(n.Keep + strconv.Itoa(n.KeepN))
+	}
+	return s.String()
+}
+
+// faces returns the lowest and highest value a single die of the group can show.
+func (n diceGroupNode) faces() (int, int) {
+	if n.Sides == 0 {
+		return -1, 1
+	}
+	return 1, n.Sides
+}
+
+func (n diceGroupNode) throw() int {
+	if n.Sides == 0 {
+		return rand.Intn(3) - 1
+	}
+	return rand.Intn(n.Sides) + 1
+}
+
+func (n diceGroupNode) needsReroll(v int) bool {
+	switch n.Reroll {
+	case "=":
+		return v == n.RerollN
+	case "<":
+		return v <= n.RerollN
+	case ">":
+		return v >= n.RerollN
+	}
+	return false
+}
+
+// rollOne throws a single die, rerolling it once if it matches the reroll rule.
+func (n diceGroupNode) rollOne(dice []DieRoll, exploded bool) []DieRoll {
+	v := n.throw()
+	if n.needsReroll(v) {
+		dice = append(dice, DieRoll{Value: v, Rerolled: true, Exploded: exploded})
+		v = n.throw()
+	}
+	return append(dice, DieRoll{Value: v, Exploded: exploded})
+}
+
+func (n diceGroupNode) eval(result *DiceRollResult) (int, error) {
+	var dice []DieRoll
+	_, high := n.faces()
+	for i := 0; i < n.Count; i++ {
+		dice = n.rollOne(dice, false)
+		for explosions := 0; n.Explode && dice[len(dice)-1].Value == high && explosions < maxDiceExplosions; explosions++ {
+			dice = n.rollOne(dice, true)
+		}
+	}
+
+	// Apply keep/drop to the dice that still count
+	var counting []int
+	for i, d := range dice {
+		if !d.Rerolled {
+			counting = append(counting, i)
+		}
+	}
+	sort.SliceStable(counting, func(a, b int) bool {
+		return dice[counting[a]].Value > dice[counting[b]].Value
+	})
+	var drop []int
+	switch n.Keep {
+	case "kh":
+		if n.KeepN < len(counting) {
+			drop = counting[n.KeepN:]
+		}
+	case "kl":
+		if n.KeepN < len(counting) {
+			drop = counting[:len(counting)-n.KeepN]
+		}
+	case "dh":
+		drop = counting[:min(n.KeepN, len(counting))]
+	case "dl":
+		drop = counting[len(counting)-min(n.KeepN, len(counting)):]
+	}
+	for _, i := range drop {
+		dice[i].Dropped = true
+	}
+
+	total := 0
+	for _, d := range dice {
+		if !d.Rerolled && !d.Dropped {
+			total += d.Value
+		}
+	}
+
+	result.Groups = append(result.Groups, DiceGroupResult{
+		Notation: n.String(),
+		Sides:    n.Sides,
+		Dice:     dice,
+		Total:    total,
+	})
+	return total, nil
+}
+
+type diceParser struct {
+	input string
+	pos   int
+}
+
+// parseDiceExpression parses standard dice notation such as "4d6kh3",
+// "2d20kl1", "1d8+1d6+3", "3d6!", "4d6r1", "d%" and "4dF".
+func parseDiceExpression(input string) (diceNode, error) {
+	p := &diceParser{input: strings.ToLower(input)}
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return nil, &DiceParseError{Pos: 0, Msg: "expression is empty"}
+	}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+	return node, nil
+}
+
+// rollDiceExpression parses and rolls an expression in one go.
+func rollDiceExpression(input string) (DiceRollResult, error) {
+	node, err := parseDiceExpression(input)
+	if err != nil {
+		return DiceRollResult{}, err
+	}
+	result := DiceRollResult{Expression: node.String()}
+	total, err := node.eval(&result)
+	if err != nil {
+		return DiceRollResult{}, err
+	}
+	result.Total = total
+	return result, nil
+}
+
+func (p *diceParser) errorf(format string, args ...interface{}) error {
+	return &DiceParseError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *diceParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *diceParser) peek() byte {
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *diceParser) parseExpr() (diceNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{Op: op, Left: left, Right: right}
+	}
+}
+
+func (p *diceParser) parseTerm() (diceNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op == 'x' {
+			op = '*'
+		}
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{Op: op, Left: left, Right: right}
+	}
+}
+
+func (p *diceParser) parseFactor() (diceNode, error) {
+	switch c := p.peek(); {
+	case c == 0:
+		return nil, p.errorf("expression ends too early")
+	case c == '-':
+		p.pos++
+		x, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{X: x}, nil
+	case c == '+':
+		p.pos++
+		return p.parseFactor()
+	case c == '(':
+		p.pos++
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return node, nil
+	case c == 'd' || (c >= '0' && c <= '9'):
+		return p.parseDiceOrNumber()
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *diceParser) readNumber() (int, bool, error) {
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, false, nil
+	}
+	n, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil || n > 1000000 {
+		text := p.input[start:p.pos]
+		p.pos = start
+		return 0, false, p.errorf("number %s is too large", text)
+	}
+	return n, true, nil
+}
+
+func (p *diceParser) parseDiceOrNumber() (diceNode, error) {
+	start := p.pos
+	count, hasCount, err := p.readNumber()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.input) || p.input[p.pos] != 'd' {
+		return numberNode{Value: count}, nil
+	}
+	if !hasCount {
+		count = 1
+	}
+	if count < 1 || count > maxDiceCount {
+		p.pos = start
+		return nil, p.errorf("dice count must be between 1 and %d", maxDiceCount)
+	}
+	p.pos++ // the 'd'
+
+	group := diceGroupNode{Count: count}
+	switch {
+	case p.pos < len(p.input) && p.input[p.pos] == '%':
+		p.pos++
+		group.Sides = 100
+	case p.pos < len(p.input) && p.input[p.pos] == 'f':
+		p.pos++
+		group.Sides = 0
+	default:
+		sidesPos := p.pos
+		sides, ok, err := p.readNumber()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, p.errorf("expected number of sides, %% or F after 'd'")
+		}
+		if sides < 2 || sides > maxDiceSides {
+			p.pos = sidesPos
+			return nil, p.errorf("dice must have between 2 and %d sides", maxDiceSides)
+		}
+		group.Sides = sides
+	}
+
+	if err := p.parseModifiers(&group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (p *diceParser) parseModifiers(group *diceGroupNode) error {
+	low, high := group.faces()
+	for p.pos < len(p.input) {
+		modPos := p.pos
+		rest := p.input[p.pos:]
+		switch {
+		case strings.HasPrefix(rest, "!"):
+			p.pos++
+			if group.Explode {
+				return p.errorf("dice can only explode once per group")
+			}
+			if group.Sides == 0 {
+				p.pos = modPos
+				return p.errorf("Fate dice cannot explode")
+			}
+			group.Explode = true
+		case strings.HasPrefix(rest, "kh"), strings.HasPrefix(rest, "kl"), strings.HasPrefix(rest, "dh"), strings.HasPrefix(rest, "dl"), strings.HasPrefix(rest, "k"):
+			if group.Keep != "" {
+				return p.errorf("only one keep/drop modifier is allowed per group")
+			}
+			if strings.HasPrefix(rest, "k") && !strings.HasPrefix(rest, "kh") && !strings.HasPrefix(rest, "kl") {
+				group.Keep = "kh" // plain "k" means keep highest
+				p.pos++
+			} else {
+				group.Keep = rest[:2]
+				p.pos += 2
+			}
+			n, ok, err := p.readNumber()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				n = 1
+			}
+			if n < 1 || n > group.Count {
+				p.pos = modPos
+				return p.errorf("%s needs a number between 1 and %d", group.Keep, group.Count)
+			}
+			group.KeepN = n
+		case strings.HasPrefix(rest, "r"):
+			if group.Reroll != "" {
+				return p.errorf("only one reroll modifier is allowed per group")
+			}
+			p.pos++
+			group.Reroll = "="
+			if p.pos < len(p.input) && (p.input[p.pos] == '<' || p.input[p.pos] == '>') {
+				group.Reroll = string(p.input[p.pos])
+				p.pos++
+			}
+			n, ok, err := p.readNumber()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				if group.Sides == 0 {
+					return p.errorf("expected a number after 'r'")
+				}
+				n = low // bare "r" rerolls the lowest face
+			}
+			if n < low || n > high {
+				p.pos = modPos
+				return p.errorf("reroll value must be between %d and %d", low, high)
+			}
+			group.RerollN = n
+		default:
+			return nil
+		}
+	}
+	return nil
+}
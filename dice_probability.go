@@ -0,0 +1,512 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Exact distributions are computed by enumeration, so cap the work per query.
+const (
+	maxDistributionCombinations = 2000000
+	maxDistributionPairs        = 4000000
+	distributionEpsilon         = 1e-12
+)
+
+// diceDistribution holds the exact probability of every total an expression can produce.
+type diceDistribution struct {
+	Min int
+	P   []float64 // P[i] is the probability of rolling Min+i
+}
+
+func pointDistribution(v int) diceDistribution {
+	return diceDistribution{Min: v, P: []float64{1}}
+}
+
+func (d diceDistribution) Max() int {
+	return d.Min + len(d.P) - 1
+}
+
+func (d diceDistribution) mean() float64 {
+	total := 0.0
+	for i, p := range d.P {
+		total += float64(d.Min+i) * p
+	}
+	return total
+}
+
+func (d diceDistribution) stdDev() float64 {
+	mean := d.mean()
+	variance := 0.0
+	for i, p := range d.P {
+		diff := float64(d.Min+i) - mean
+		variance += diff * diff * p
+	}
+	return math.Sqrt(variance)
+}
+
+// percentile returns the smallest total whose cumulative probability reaches q.
+func (d diceDistribution) percentile(q float64) int {
+	cumulative := 0.0
+	for i, p := range d.P {
+		cumulative += p
+		if cumulative >= q-distributionEpsilon {
+			return d.Min + i
+		}
+	}
+	return d.Max()
+}
+
+// chance returns the probability that a roll satisfies "total <op> target".
+func (d diceDistribution) chance(op string, target int) float64 {
+	total := 0.0
+	for i, p := range d.P {
+		v := d.Min + i
+		if compareTotals(v, op, target) {
+			total += p
+		}
+	}
+	return total
+}
+
+func compareTotals(v int, op string, target int) bool {
+	switch op {
+	case ">=":
+		return v >= target
+	case ">":
+		return v > target
+	case "<=":
+		return v <= target
+	case "<":
+		return v < target
+	case "=", "==":
+		return v == target
+	}
+	return false
+}
+
+// trim drops impossible totals from both ends.
+func (d diceDistribution) trim() diceDistribution {
+	start, end := 0, len(d.P)
+	for start < end-1 && d.P[start] < distributionEpsilon {
+		start++
+	}
+	for end > start+1 && d.P[end-1] < distributionEpsilon {
+		end--
+	}
+	return diceDistribution{Min: d.Min + start, P: d.P[start:end]}
+}
+
+func fromProbabilityMap(probs map[int]float64) diceDistribution {
+	low, high := math.MaxInt, math.MinInt
+	for v := range probs {
+		low, high = min(low, v), max(high, v)
+	}
+	d := diceDistribution{Min: low, P: make([]float64, high-low+1)}
+	for v, p := range probs {
+		d.P[v-low] += p
+	}
+	return d.trim()
+}
+
+func convolve(a, b diceDistribution) diceDistribution {
+	out := diceDistribution{Min: a.Min + b.Min, P: make([]float64, len(a.P)+len(b.P)-1)}
+	for i, pa := range a.P {
+		if pa == 0 {
+			continue
+		}
+		for j, pb := range b.P {
+			out.P[i+j] += pa * pb
+		}
+	}
+	return out
+}
+
+func negateDistribution(d diceDistribution) diceDistribution {
+	out := diceDistribution{Min: -d.Max(), P: make([]float64, len(d.P))}
+	for i, p := range d.P {
+		out.P[len(d.P)-1-i] = p
+	}
+	return out
+}
+
+func (n numberNode) distribution() (diceDistribution, error) {
+	return pointDistribution(n.Value), nil
+}
+
+func (n negateNode) distribution() (diceDistribution, error) {
+	d, err := n.X.distribution()
+	if err != nil {
+		return d, err
+	}
+	return negateDistribution(d), nil
+}
+
+func (n binaryNode) distribution() (diceDistribution, error) {
+	left, err := n.Left.distribution()
+	if err != nil {
+		return left, err
+	}
+	right, err := n.Right.distribution()
+	if err != nil {
+		return right, err
+	}
+
+	switch n.Op {
+	case '+':
+		return convolve(left, right), nil
+	case '-':
+		return convolve(left, negateDistribution(right)), nil
+	}
+
+	// The largest products come from the ends of each range
+	if n.Op == '*' {
+		for _, a := range []int{left.Min, left.Max()} {
+			for _, b := range []int{right.Min, right.Max()} {
+				if _, ok := multiplyDiceTotals(a, b); !ok {
+					return diceDistribution{}, fmt.Errorf("%s can go past %d", n, maxDiceTotal)
+				}
+			}
+		}
+	}
+
+	// Multiplication and division need every pair of totals
+	if len(left.P)*len(right.P) > maxDistributionPairs {
+		return diceDistribution{}, fmt.Errorf("%s has too many outcomes to compute exactly", n)
+	}
+	probs := make(map[int]float64)
+	for i, pl := range left.P {
+		for j, pr := range right.P {
+			a, b := left.Min+i, right.Min+j
+			if n.Op == '/' {
+				if b == 0 {
+					if pr > 0 && pl > 0 {
+						return diceDistribution{}, fmt.Errorf("%s can divide by zero", n)
+					}
+					continue
+				}
+				probs[floorDiv(a, b)] += pl * pr
+			} else {
+				probs[a*b] += pl * pr
+			}
+		}
+	}
+	return fromProbabilityMap(probs), nil
+}
+
+// singleDieDistribution is the distribution of one die of the group after
+// its reroll and explosion rules are applied.
+func (n diceGroupNode) singleDieDistribution() diceDistribution {
+	low, high := n.faces()
+	faces := high - low + 1
+
+	rerolled := 0
+	for v := low; v <= high; v++ {
+		if n.needsReroll(v) {
+			rerolled++
+		}
+	}
+
+	// A rerolled die is thrown once more and the second result stands
+	single := diceDistribution{Min: low, P: make([]float64, faces)}
+	for v := low; v <= high; v++ {
+		p := float64(rerolled) / float64(faces) / float64(faces)
+		if !n.needsReroll(v) {
+			p += 1 / float64(faces)
+		}
+		single.P[v-low] = p
+	}
+	if !n.Explode {
+		return single
+	}
+
+	// Each maximum face adds another throw; unroll until the remaining mass is negligible
+	maxChance := single.P[len(single.P)-1]
+	stop := diceDistribution{Min: low, P: append([]float64(nil), single.P[:len(single.P)-1]...)}
+	result := map[int]float64{}
+	weight, offset := 1.0, 0
+	for depth := 0; depth <= maxDiceExplosions && weight > distributionEpsilon; depth++ {
+		for i, p := range stop.P {
+			result[stop.Min+i+offset] += weight * p
+		}
+		weight *= maxChance
+		offset += high
+	}
+	return fromProbabilityMap(result)
+}
+
+func (n diceGroupNode) distribution() (diceDistribution, error) {
+	single := n.singleDieDistribution()
+
+	if n.Keep == "" {
+		total := pointDistribution(0)
+		for i := 0; i < n.Count; i++ {
+			total = convolve(total, single)
+		}
+		return total, nil
+	}
+
+	if n.Explode {
+		return diceDistribution{}, fmt.Errorf("%s: exploding dice combined with keep/drop can't be computed exactly", n)
+	}
+
+	// Enumerate every multiset of faces, weighted by its multinomial probability
+	combinations := 1.0
+	for i := 1; i <= n.Count; i++ {
+		combinations = combinations * float64(len(single.P)+i-1) / float64(i)
+	}
+	if combinations > maxDistributionCombinations {
+		return diceDistribution{}, fmt.Errorf("%s has too many combinations to compute exactly", n)
+	}
+
+	keepFrom, keepTo := 0, n.Count // kept slice of the dice sorted high to low
+	switch n.Keep {
+	case "kh":
+		keepTo = n.KeepN
+	case "kl":
+		keepFrom = n.Count - n.KeepN
+	case "dh":
+		keepFrom = n.KeepN
+	case "dl":
+		keepTo = n.Count - n.KeepN
+	}
+
+	logFactorial := func(k int) float64 {
+		v, _ := math.Lgamma(float64(k + 1))
+		return v
+	}
+
+	probs := make(map[int]float64)
+	counts := make([]int, len(single.P))
+	var enumerate func(face, remaining int)
+	enumerate = func(face, remaining int) {
+		if face < 0 {
+			if remaining > 0 {
+				return
+			}
+			logP := logFactorial(n.Count)
+			for i, c := range counts {
+				if c > 0 {
+					logP += float64(c)*math.Log(single.P[i]) - logFactorial(c)
+				}
+			}
+			// Walk the dice from the highest face down, summing the kept positions
+			sum, position := 0, 0
+			for i := len(counts) - 1; i >= 0; i-- {
+				for c := 0; c < counts[i]; c++ {
+					if position >= keepFrom && position < keepTo {
+						sum += single.Min + i
+					}
+					position++
+				}
+			}
+			probs[sum] += math.Exp(logP)
+			return
+		}
+		for c := remaining; c >= 0; c-- {
+			if c > 0 && single.P[face] == 0 {
+				continue
+			}
+			counts[face] = c
+			enumerate(face-1, remaining-c)
+		}
+		counts[face] = 0
+	}
+	enumerate(len(single.P)-1, n.Count)
+
+	return fromProbabilityMap(probs), nil
+}
+
+// diceProbabilityQuery is a parsed calculator query such as "2d6+3 >= 10",
+// "1d20+5 vs 1d20+2" or "2d20kh1 | 1d20+2".
+type diceProbabilityQuery struct {
+	Expressions []string
+	Versus      bool
+	Op          string
+	Target      int
+	HasTarget   bool
+}
+
+var diceTargetPattern = regexp.MustCompile(`(>=|<=|==|>|<|=)\s*(-?\d+)\s*$`)
+
+func parseDiceProbabilityQuery(input string) (diceProbabilityQuery, error) {
+	var query diceProbabilityQuery
+	text := strings.TrimSpace(input)
+
+	// A comparison straight after "r" belongs to a reroll, as in "4d6r<2"
+	match := diceTargetPattern.FindStringSubmatchIndex(text)
+	if match != nil && (match[0] == 0 || !strings.ContainsRune("rR", rune(text[match[0]-1]))) {
+		query.Op = text[match[2]:match[3]]
+		query.Target, _ = strconv.Atoi(text[match[4]:match[5]])
+		query.HasTarget = true
+		text = strings.TrimSpace(text[:match[0]])
+		if text != "" && strings.ContainsRune("rkhlRKHL", rune(text[len(text)-1])) {
+			return query, fmt.Errorf("%q ends in a modifier; give it a number before the comparison", text)
+		}
+	}
+
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, " vs "):
+		parts := strings.SplitN(lower, " vs ", 2)
+		query.Expressions = []string{parts[0], parts[1]}
+		query.Versus = true
+	case strings.Contains(text, "|"):
+		query.Expressions = strings.SplitN(text, "|", 2)
+	default:
+		query.Expressions = []string{text}
+	}
+
+	for i, expr := range query.Expressions {
+		query.Expressions[i] = strings.TrimSpace(expr)
+		if query.Expressions[i] == "" {
+			return query, fmt.Errorf("expression %d is empty", i+1)
+		}
+	}
+	if query.Versus && query.HasTarget {
+		return query, fmt.Errorf("use either \"vs\" or a target, not both")
+	}
+	return query, nil
+}
+
+// computeDiceDistribution parses an expression with the roller's parser and
+// returns its canonical form along with its exact distribution.
+func computeDiceDistribution(expression string) (string, diceDistribution, error) {
+	node, err := parseDiceExpression(expression)
+	if err != nil {
+		return "", diceDistribution{}, err
+	}
+	d, err := node.distribution()
+	if err != nil {
+		return "", diceDistribution{}, err
+	}
+	return node.String(), d.trim(), nil
+}
+
+// renderDistributionChart draws a horizontal ASCII bar per total, bucketing
+// wide ranges so the chart fits on screen.
+func renderDistributionChart(d diceDistribution, maxRows, barWidth int) string {
+	bucket := (len(d.P) + maxRows - 1) / maxRows
+
+	type row struct {
+		label string
+		p     float64
+	}
+	var rows []row
+	peak := 0.0
+	for start := 0; start < len(d.P); start += bucket {
+		end := min(start+bucket, len(d.P))
+		p := 0.0
+		for _, v := range d.P[start:end] {
+			p += v
+		}
+		label := fmt.Sprint(d.Min + start)
+		if end-start > 1 {
+			label = fmt.Sprintf("%d-%d", d.Min+start, d.Min+end-1)
+		}
+		rows = append(rows, row{label: label, p: p})
+		peak = math.Max(peak, p)
+	}
+
+	labelWidth := 0
+	for _, r := range rows {
+		labelWidth = max(labelWidth, len(r.label))
+	}
+
+	var lines []string
+	for _, r := range rows {
+		length := 0
+		if peak > 0 {
+			length = int(math.Round(r.p / peak * float64(barWidth)))
+		}
+		lines = append(lines, fmt.Sprintf("%*s │%-*s %5.1f%%", labelWidth, r.label, barWidth, strings.Repeat("█", length), r.p*100))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatDistributionStats(d diceDistribution) string {
+	return fmt.Sprintf("Range %d–%d\nMean %.2f • SD %.2f\nP10/25/50/75/90: %d/%d/%d/%d/%d",
+		d.Min, d.Max(), d.mean(), d.stdDev(),
+		d.percentile(0.10), d.percentile(0.25), d.percentile(0.50), d.percentile(0.75), d.percentile(0.90))
+}
+
+// opposedChances returns the chances that a beats b, ties, or loses.
+func opposedChances(a, b diceDistribution) (win, tie, lose float64) {
+	for i, pa := range a.P {
+		va := a.Min + i
+		for j, pb := range b.P {
+			vb := b.Min + j
+			switch {
+			case va > vb:
+				win += pa * pb
+			case va == vb:
+				tie += pa * pb
+			default:
+				lose += pa * pb
+			}
+		}
+	}
+	return win, tie, lose
+}
+
+// buildDiceProbabilityReport runs a calculator query and renders its charts and statistics.
+func buildDiceProbabilityReport(input string) (string, error) {
+	query, err := parseDiceProbabilityQuery(input)
+	if err != nil {
+		return "", err
+	}
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF6B6B")).
+		Padding(0, 1)
+
+	headingStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#4ECDC4"))
+
+	chanceStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FFD700"))
+
+	maxRows, barWidth := 20, 30
+	if len(query.Expressions) > 1 {
+		maxRows, barWidth = 16, 14
+	}
+
+	var names []string
+	var dists []diceDistribution
+	var panels []string
+	for _, expr := range query.Expressions {
+		name, d, err := computeDiceDistribution(expr)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", expr, err)
+		}
+		names = append(names, name)
+		dists = append(dists, d)
+
+		var panel strings.Builder
+		panel.WriteString(headingStyle.Render(name) + "\n\n")
+		panel.WriteString(renderDistributionChart(d, maxRows, barWidth) + "\n\n")
+		panel.WriteString(formatDistributionStats(d))
+		if query.HasTarget {
+			panel.WriteString("\n" + chanceStyle.Render(fmt.Sprintf("P(%s %s %d) = %.2f%%", name, query.Op, query.Target, d.chance(query.Op, query.Target)*100)))
+		}
+		panels = append(panels, panelStyle.Render(panel.String()))
+	}
+
+	report := lipgloss.JoinHorizontal(lipgloss.Top, panels...)
+	if len(dists) == 2 {
+		win, tie, lose := opposedChances(dists[0], dists[1])
+		summary := fmt.Sprintf("%s beats %s: %.2f%% • tie: %.2f%% • %s wins: %.2f%%", names[0], names[1], win*100, tie*100, names[1], lose*100)
+		if !query.Versus {
+			summary = fmt.Sprintf("Mean difference: %+.2f • ", dists[0].mean()-dists[1].mean()) + summary
+		}
+		report = lipgloss.JoinVertical(lipgloss.Center, report, chanceStyle.Render(summary))
+	}
+	return report, nil
+}
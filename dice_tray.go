@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Die types that can be added to the tray.
+var trayDieSides = []int{4, 6, 8, 10, 12, 20, 100}
+
+type trayDie struct {
+	Sides  int
+	Value  int
+	Locked bool
+}
+
+// getDieFaceVisual draws a die with a shape for its type: a triangle for d4,
+// pips for d6, a diamond for d8, a kite for d10, a pentagon for d12, an
+// icosahedron for d20 and a ball for percentile dice. Every shape is five
+// lines of twelve columns so a tray of dice lines up side by side. A value
+// of 0 draws the die before it has been rolled.
+func getDieFaceVisual(sides, value int) string {
+	n := fmt.Sprintf("%2d", value)
+	if value <= 0 {
+		n = " ?"
+	}
+	switch sides {
+	case 4:
+		return "     /\\     \n    /  \\    \n   / " + n + " \\   \n  /      \\  \n /________\\ "
+	case 6:
+		if value >= 1 && value <= 6 {
+			return strings.ReplaceAll(getDiceVisual(value), "\n", " \n") + " "
+		}
+		return "┌─────────┐ \n│         │ \n│   " + n + "    │ \n│         │ \n└─────────┘ "
+	case 8:
+		return "     /\\     \n   /    \\   \n  <  " + n + "  >  \n   \\    /   \n     \\/     "
+	case 10:
+		return "     __     \n   /    \\   \n  /  " + n + "  \\  \n  \\      /  \n    \\__/    "
+	case 12:
+		return "    ____    \n  /      \\  \n |   " + n + "   | \n  \\      /  \n   \\____/   "
+	case 20:
+		return "   ______   \n  /\\    /\\  \n /  \\" + n + "/  \\ \n \\  /  \\  / \n  \\/____\\/  "
+	default:
+		return "  .-~~~~-.  \n /        \\ \n|   " + fmt.Sprintf("%3s", strings.TrimSpace(n)) + "    |\n \\        / \n  `-....-'  "
+	}
+}
+
+// rollTray rerolls every unlocked die through the dice engine and logs the throw.
+func (m model) rollTray() (tea.Model, tea.Cmd) {
+	result := DiceRollResult{}
+	var terms []string
+	for i := range m.diceTray {
+		if m.diceTray[i].Locked {
+			continue
+		}
+		group := diceGroupNode{Count: 1, Sides: m.diceTray[i].Sides}
+		value, _ := group.eval(&result)
+		m.diceTray[i].Value = value
+		result.Total += value
+		terms = append(terms, group.String())
+	}
+	if len(terms) == 0 {
+		m.diceMessage = "All dice are locked"
+		return m, nil
+	}
+	result.Expression = strings.Join(terms, "+")
+
+	m.diceHistory = append(m.diceHistory, DiceHistoryEntry{Time: time.Now(), Label: "Dice tray", DiceRollResult: result})
+
+	m.diceMessage = ""
+	if m.diceHistoryPersist {
+		if err := saveDiceHistory(m.diceHistory); err != nil {
+			m.diceMessage = "❌ Failed to save history: " + err.Error()
+		}
+	}
+	m.diceRolling = true
+	m.diceRollTime = time.Now()
+	return m, tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
+		return t
+	})
+}
+
+func (m model) updateDiceTray(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.diceShowTray = false
+		m.diceMessage = ""
+	case "up", "k":
+		if m.diceTrayPicker > 0 {
+			m.diceTrayPicker--
+		}
+	case "down", "j":
+		if m.diceTrayPicker < len(trayDieSides)-1 {
+			m.diceTrayPicker++
+		}
+	case "left", "h":
+		if m.diceTrayCursor > 0 {
+			m.diceTrayCursor--
+		}
+	case "right", "l":
+		if m.diceTrayCursor < len(m.diceTray)-1 {
+			m.diceTrayCursor++
+		}
+	case "a", "+":
+		if len(m.diceTray) >= 12 {
+			m.diceMessage = "The tray holds at most 12 dice"
+		} else {
+			m.diceTray = append(m.diceTray, trayDie{Sides: trayDieSides[m.diceTrayPicker]})
+			m.diceTrayCursor = len(m.diceTray) - 1
+			m.diceMessage = ""
+		}
+	case "x", "delete", "-":
+		if m.diceTrayCursor < len(m.diceTray) {
+			m.diceTray = append(m.diceTray[:m.diceTrayCursor], m.diceTray[m.diceTrayCursor+1:]...)
+			if m.diceTrayCursor > 0 && m.diceTrayCursor >= len(m.diceTray) {
+				m.diceTrayCursor--
+			}
+		}
+	case " ":
+		if m.diceTrayCursor < len(m.diceTray) && m.diceTray[m.diceTrayCursor].Value > 0 {
+			m.diceTray[m.diceTrayCursor].Locked = !m.diceTray[m.diceTrayCursor].Locked
+		}
+	case "u":
+		for i := range m.diceTray {
+			m.diceTray[i].Locked = false
+		}
+	case "c":
+		m.diceTray = nil
+		m.diceTrayCursor = 0
+		m.diceMessage = ""
+	case "enter", "r":
+		if len(m.diceTray) > 0 && !m.diceRolling {
+			return m.rollTray()
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewDiceTray() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#FF6B6B")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF6B6B")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	pickerStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FF6B6B")).
+		Padding(0, 2).
+		MarginBottom(1).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	trayStyle := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("#45B7D1")).
+		Padding(1, 1).
+		MarginBottom(1)
+
+	dieStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA"))
+	highStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFD700"))
+	lowStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#E74C3C"))
+	lockedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8A8A8A"))
+	cursorStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#4ECDC4"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#4ECDC4")).
+		Padding(0, 1)
+
+	totalStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#45B7D1")).
+		Padding(0, 2).
+		MarginBottom(1)
+
+	messageStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#E74C3C")).
+		Bold(true).
+		MarginBottom(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render("🧺 Dice Tray")
+
+	var picks []string
+	for i, sides := range trayDieSides {
+		label := diceLabel(sides)
+		if i == m.diceTrayPicker {
+			picks = append(picks, selectedStyle.Render(label))
+		} else {
+			picks = append(picks, " "+label+" ")
+		}
+	}
+	picker := pickerStyle.Render("Add die: " + strings.Join(picks, " "))
+
+	elements := []string{title, picker}
+
+	if len(m.diceTray) == 0 {
+		elements = append(elements, trayStyle.Render("The tray is empty.\n\nPick a die type with ↑/↓ and press A to add it."))
+	} else {
+		rolled := !m.diceRolling
+		for _, d := range m.diceTray {
+			if d.Value == 0 {
+				rolled = false
+			}
+		}
+		high, low, total := 0, 0, 0
+		for i, d := range m.diceTray {
+			total += d.Value
+			if d.Value > m.diceTray[high].Value {
+				high = i
+			}
+			if d.Value < m.diceTray[low].Value {
+				low = i
+			}
+		}
+
+		frame := int(time.Since(m.diceRollTime) / time.Millisecond / 100)
+		var dice []string
+		for i, d := range m.diceTray {
+			value := d.Value
+			if m.diceRolling && !d.Locked {
+				value = (frame*7+i*3)%d.Sides + 1
+			}
+
+			style := dieStyle
+			switch {
+			case d.Locked:
+				style = lockedStyle
+			case rolled && i == high:
+				style = highStyle
+			case rolled && i == low && len(m.diceTray) > 1:
+				style = lowStyle
+			}
+			face := getDieFaceVisual(d.Sides, value)
+
+			label := diceLabel(d.Sides)
+			if d.Locked {
+				label += " 🔒"
+			}
+			if i == m.diceTrayCursor {
+				label = cursorStyle.Render("▲ " + label)
+			}
+			dice = append(dice, lipgloss.JoinVertical(lipgloss.Center, style.Render(face), label, " "))
+		}
+
+		// Wrap the dice into rows of six
+		var rows []string
+		for start := 0; start < len(dice); start += 6 {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, dice[start:min(start+6, len(dice))]...))
+		}
+		elements = append(elements, trayStyle.Render(lipgloss.JoinVertical(lipgloss.Left, rows...)))
+
+		if rolled {
+			summary := fmt.Sprintf("Total: %d • Highest: %d (%s) • Lowest: %d (%s)",
+				total,
+				m.diceTray[high].Value, diceLabel(m.diceTray[high].Sides),
+				m.diceTray[low].Value, diceLabel(m.diceTray[low].Sides))
+			elements = append(elements, totalStyle.Render(summary))
+		}
+	}
+
+	if m.diceMessage != "" {
+		elements = append(elements, messageStyle.Render(m.diceMessage))
+	}
+
+	elements = append(elements, helpStyle.Render("↑/↓ pick type • A to add • ←/→ select die • Space to lock • X to remove • Enter to roll unlocked • U to unlock all • C to clear • ESC to go back"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
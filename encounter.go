@@ -0,0 +1,834 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Challenge ratings in order, with the XP a monster of each is worth.
+var challengeRatings = []string{
+	"0", "1/8", "1/4", "1/2", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12",
+	"13", "14", "15", "16", "17", "18", "19", "20", "21", "22", "23", "24", "25", "26", "27", "28", "29", "30",
+}
+
+var challengeXP = map[string]int{
+	"0": 10, "1/8": 25, "1/4": 50, "1/2": 100, "1": 200, "2": 450, "3": 700, "4": 1100,
+	"5": 1800, "6": 2300, "7": 2900, "8": 3900, "9": 5000, "10": 5900, "11": 7200, "12": 8400,
+	"13": 10000, "14": 11500, "15": 13000, "16": 15000, "17": 18000, "18": 20000, "19": 22000, "20": 25000,
+	"21": 33000, "22": 41000, "23": 50000, "24": 62000, "25": 75000, "26": 90000, "27": 105000, "28": 120000,
+	"29": 135000, "30": 155000,
+}
+
+var encounterDifficulties = []string{"Easy", "Medium", "Hard", "Deadly"}
+
+// encounterThresholds are each character level's XP thresholds for an
+// easy, medium, hard and deadly encounter.
+var encounterThresholds = [20][4]int{
+	{25, 50, 75, 100},
+	{50, 100, 150, 200},
+	{75, 150, 225, 400},
+	{125, 250, 375, 500},
+	{250, 500, 750, 1100},
+	{300, 600, 900, 1400},
+	{350, 750, 1100, 1700},
+	{450, 900, 1400, 2100},
+	{550, 1100, 1600, 2400},
+	{600, 1200, 1900, 2800},
+	{800, 1600, 2400, 3600},
+	{1000, 2000, 3000, 4500},
+	{1100, 2200, 3400, 5100},
+	{1250, 2500, 3800, 5700},
+	{1400, 2800, 4300, 6400},
+	{1600, 3200, 4800, 7200},
+	{2000, 3900, 5900, 8800},
+	{2100, 4200, 6300, 9500},
+	{2400, 4900, 7300, 10900},
+	{2800, 5700, 8500, 12700},
+}
+
+// Encounter XP multipliers, from a lone monster facing a large party up to a
+// horde facing a small one.
+var encounterMultipliers = []float64{0.5, 1, 1.5, 2, 2.5, 3, 4, 5}
+
+// PartyMember is one adventurer an encounter is built for.
+type PartyMember struct {
+	Name      string `json:"name,omitempty"`
+	Level     int    `json:"level"`
+	Character string `json:"character,omitempty"` // roster ID, when taken from a saved character
+}
+
+// EncounterGroup is a number of one kind of monster in the encounter being
+// built.
+type EncounterGroup struct {
+	Monster string `json:"monster"`
+	Count   int    `json:"count"`
+}
+
+// encounterRating is how an encounter measures up against a party.
+type encounterRating struct {
+	Thresholds [4]int
+	Monsters   int
+	BaseXP     int
+	Multiplier float64
+	AdjustedXP int
+	Difficulty int // index into encounterDifficulties, -1 below easy
+}
+
+func (pack ContentPack) getMonster(name string) (PackMonster, bool) {
+	for _, monster := range pack.Monsters {
+		if strings.EqualFold(monster.Name, name) {
+			return monster, true
+		}
+	}
+	return PackMonster{}, false
+}
+
+// findMonster looks a monster up by name, also trying the name as a
+// singular, so "Goblins" and "Wolves" find the goblin and the wolf.
+func (pack ContentPack) findMonster(name string) (PackMonster, bool) {
+	name = strings.TrimSpace(name)
+	candidates := []string{name}
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ves"):
+		candidates = append(candidates, name[:len(name)-3]+"f")
+	case strings.HasSuffix(lower, "es"):
+		candidates = append(candidates, name[:len(name)-2], name[:len(name)-1])
+	case strings.HasSuffix(lower, "s"):
+		candidates = append(candidates, name[:len(name)-1])
+	}
+	for _, candidate := range candidates {
+		if monster, ok := pack.getMonster(candidate); ok {
+			return monster, true
+		}
+	}
+	return PackMonster{}, false
+}
+
+// encounterMultiplier scales encounter XP for the number of monsters,
+// stepping up for a party of fewer than three and down for six or more.
+func encounterMultiplier(monsters, partySize int) float64 {
+	if monsters == 0 {
+		return 0
+	}
+	step := 6
+	switch {
+	case monsters == 1:
+		step = 1
+	case monsters == 2:
+		step = 2
+	case monsters <= 6:
+		step = 3
+	case monsters <= 10:
+		step = 4
+	case monsters <= 14:
+		step = 5
+	}
+	if partySize < 3 {
+		step++
+	} else if partySize >= 6 {
+		step--
+	}
+	return encounterMultipliers[step]
+}
+
+// rateEncounter adds up the party's thresholds and the monsters' XP and
+// works out the encounter's difficulty.
+func (pack ContentPack) rateEncounter(party []PartyMember, groups []EncounterGroup) encounterRating {
+	var rating encounterRating
+	for _, member := range party {
+		level := min(max(member.Level, 1), maxCharacterLevel)
+		for i, xp := range encounterThresholds[level-1] {
+			rating.Thresholds[i] += xp
+		}
+	}
+	for _, group := range groups {
+		if monster, ok := pack.getMonster(group.Monster); ok {
+			rating.Monsters += group.Count
+			rating.BaseXP += challengeXP[monster.CR] * group.Count
+		}
+	}
+	rating.Multiplier = encounterMultiplier(rating.Monsters, len(party))
+	rating.AdjustedXP = int(float64(rating.BaseXP) * rating.Multiplier)
+	rating.Difficulty = -1
+	for i, threshold := range rating.Thresholds {
+		if len(party) > 0 && rating.AdjustedXP >= threshold {
+			rating.Difficulty = i
+		}
+	}
+	return rating
+}
+
+// addToEncounter adds count monsters, joining a group of the same monster
+// if there is one.
+func addToEncounter(groups []EncounterGroup, monster string, count int) []EncounterGroup {
+	groups = append([]EncounterGroup{}, groups...)
+	for i := range groups {
+		if groups[i].Monster == monster {
+			groups[i].Count += count
+			return groups
+		}
+	}
+	return append(groups, EncounterGroup{Monster: monster, Count: count})
+}
+
+// monsterTypes lists the kinds of monster in the pack, for the type filter.
+func (pack ContentPack) monsterTypes() []string {
+	var types []string
+	for _, monster := range pack.Monsters {
+		if indexOf(types, monster.Type) < 0 {
+			types = append(types, monster.Type)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// encounterMonsterList is the monster browser's list: the pack's monsters
+// by challenge rating then name, narrowed by the filters and search text.
+func (m model) encounterMonsterList() []PackMonster {
+	search := strings.ToLower(strings.TrimSpace(m.encounterSearch))
+	var monsters []PackMonster
+	for _, monster := range m.rpgPack.Monsters {
+		if m.encounterCRFilter > 0 && monster.CR != challengeRatings[m.encounterCRFilter-1] {
+			continue
+		}
+		if m.encounterTypeFilter != "" && monster.Type != m.encounterTypeFilter {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(monster.Name+" "+monster.Type), search) {
+			continue
+		}
+		monsters = append(monsters, monster)
+	}
+	sort.SliceStable(monsters, func(i, j int) bool {
+		a, b := indexOf(challengeRatings, monsters[i].CR), indexOf(challengeRatings, monsters[j].CR)
+		if a != b {
+			return a < b
+		}
+		return monsters[i].Name < monsters[j].Name
+	})
+	return monsters
+}
+
+// partyMemberName is how a party member is listed.
+func partyMemberName(member PartyMember, i int) string {
+	if member.Name != "" {
+		return member.Name
+	}
+	return fmt.Sprintf("Adventurer %d", i+1)
+}
+
+// encounterCombatants turns the encounter into combatants for the
+// initiative tracker, numbering monsters of the same kind.
+func (pack ContentPack) encounterCombatants(groups []EncounterGroup) []Combatant {
+	var combatants []Combatant
+	for _, group := range groups {
+		monster, ok := pack.getMonster(group.Monster)
+		if !ok {
+			continue
+		}
+		for i := 0; i < group.Count; i++ {
+			c := Combatant{Name: monster.Name, HP: monster.HP, MaxHP: monster.HP, AC: monster.AC, InitMod: monster.Initiative}
+			if group.Count > 1 {
+				c.Name = fmt.Sprintf("%s %d", monster.Name, i+1)
+			}
+			combatants = append(combatants, c)
+		}
+	}
+	return combatants
+}
+
+func (m model) openEncounterBuilder() model {
+	builder, err := loadEncounterBuilder()
+	m.encounterParty = builder.Party
+	m.encounterGroups = builder.Groups
+	m.encounterTables = builder.Tables
+	if len(m.encounterParty) == 0 {
+		m.encounterParty = []PartyMember{{Level: 1}, {Level: 1}, {Level: 1}, {Level: 1}}
+	}
+	m.encounterPartyCursor = 0
+	m.encounterMonsterCursor = 0
+	m.encounterGroupCursor = 0
+	m.encounterSearching = false
+	m.encounterShowImport = false
+	m.encounterShowTables = false
+	m.encounterPrompt = ""
+	m.encounterMessage = ""
+	if err != nil {
+		m.encounterMessage = "❌ " + err.Error()
+	}
+	m.state = encounterView
+	return m
+}
+
+// storeEncounterBuilder saves the party, encounter and tables, reporting a
+// failure in the message line.
+func (m model) storeEncounterBuilder() model {
+	err := saveEncounterBuilder(encounterBuilderFile{Party: m.encounterParty, Groups: m.encounterGroups, Tables: m.encounterTables})
+	if err != nil {
+		m.encounterMessage = fmt.Sprintf("❌ Save failed: %v", err)
+	}
+	return m
+}
+
+// fightEncounter sends the encounter to the initiative tracker, along with
+// any party members taken from saved characters who aren't already in the
+// fight.
+func (m model) fightEncounter() model {
+	e := m.combatEncounter
+	added := 0
+	roster, _ := loadCharacters() // members missing from it are skipped below
+	for _, member := range m.encounterParty {
+		i := findCharacter(roster, member.Character)
+		if member.Character == "" || i < 0 {
+			continue
+		}
+		inFight := false
+		for _, c := range e.Combatants {
+			inFight = inFight || c.Character == member.Character
+		}
+		if !inFight {
+			c := m.characterCombatant(roster[i])
+			c.Name = uniqueCombatantName(e, c.Name)
+			e, _ = addCombatant(e, c)
+			added++
+		}
+	}
+	for _, c := range m.rpgPack.encounterCombatants(m.encounterGroups) {
+		c.Name = uniqueCombatantName(e, c.Name)
+		var err error
+		if e, err = addCombatant(e, c); err != nil {
+			m.encounterMessage = "❌ " + err.Error()
+			return m
+		}
+		added++
+	}
+
+	m.combatPrompt = ""
+	m.combatShowPicker = false
+	m.combatShowImport = false
+	m.combatCursor = 0
+	m = m.storeCombat(e, fmt.Sprintf("✅ Added %d from the encounter builder • R to roll initiative", added))
+	m.state = combatView
+	return m
+}
+
+func (m model) updateEncounter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.encounterShowTables {
+		return m.updateEncounterTables(keyMsg)
+	}
+	if m.encounterShowImport {
+		return m.updateEncounterImport(keyMsg)
+	}
+	key := keyMsg.String()
+	if m.encounterSearching {
+		switch key {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.encounterSearch = ""
+			m.encounterSearching = false
+		case "enter":
+			m.encounterSearching = false
+		case "backspace":
+			if len(m.encounterSearch) > 0 {
+				m.encounterSearch = m.encounterSearch[:len(m.encounterSearch)-1]
+			}
+		default:
+			if len(key) == 1 {
+				m.encounterSearch += key
+			}
+		}
+		m.encounterMonsterCursor = 0
+		return m, nil
+	}
+
+	m.encounterMessage = ""
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = menuView
+		return m, nil
+	case "tab":
+		m.encounterFocus = (m.encounterFocus + 1) % 3
+		return m, nil
+	case "shift+tab":
+		m.encounterFocus = (m.encounterFocus + 2) % 3
+		return m, nil
+	case "/":
+		m.encounterFocus = 1
+		m.encounterSearching = true
+		return m, nil
+	case "i":
+		var err error
+		if m.encounterRoster, err = loadCharacters(); err != nil {
+			m.encounterMessage = "❌ " + err.Error()
+		}
+		m.encounterImportCursor = 0
+		m.encounterShowImport = true
+		return m, nil
+	case "r":
+		m.encounterTableCursor = min(m.encounterTableCursor, max(len(m.encounterTables)-1, 0))
+		m.encounterShowTables = true
+		return m, nil
+	case "f":
+		if len(m.encounterGroups) == 0 {
+			m.encounterMessage = "❌ Add some monsters first"
+			return m, nil
+		}
+		return m.fightEncounter(), nil
+	case "X":
+		m.encounterGroups = nil
+		m.encounterGroupCursor = 0
+		m.encounterMessage = "✅ Encounter cleared"
+		return m.storeEncounterBuilder(), nil
+	}
+
+	switch m.encounterFocus {
+	case 0:
+		m = m.updateEncounterParty(key)
+	case 1:
+		m = m.updateEncounterMonsters(key)
+	case 2:
+		m = m.updateEncounterGroups(key)
+	}
+	return m, nil
+}
+
+func (m model) updateEncounterParty(key string) model {
+	party := append([]PartyMember{}, m.encounterParty...)
+	cursor := m.encounterPartyCursor
+	switch key {
+	case "up", "k":
+		if cursor > 0 {
+			m.encounterPartyCursor--
+		}
+		return m
+	case "down", "j":
+		if cursor < len(party)-1 {
+			m.encounterPartyCursor++
+		}
+		return m
+	case "left", "right":
+		if cursor >= len(party) {
+			return m
+		}
+		step := 1
+		if key == "left" {
+			step = -1
+		}
+		party[cursor].Level = min(max(party[cursor].Level+step, 1), maxCharacterLevel)
+	case "+", "=", "a":
+		level := 1
+		if cursor < len(party) {
+			level = party[cursor].Level
+		}
+		party = append(party, PartyMember{Level: level})
+		m.encounterPartyCursor = len(party) - 1
+	case "-", "d", "delete":
+		if cursor >= len(party) {
+			return m
+		}
+		party = append(party[:cursor], party[cursor+1:]...)
+		m.encounterPartyCursor = min(cursor, max(len(party)-1, 0))
+	default:
+		return m
+	}
+	m.encounterParty = party
+	return m.storeEncounterBuilder()
+}
+
+func (m model) updateEncounterMonsters(key string) model {
+	monsters := m.encounterMonsterList()
+	switch key {
+	case "up", "k":
+		if m.encounterMonsterCursor > 0 {
+			m.encounterMonsterCursor--
+		}
+	case "down", "j":
+		if m.encounterMonsterCursor < len(monsters)-1 {
+			m.encounterMonsterCursor++
+		}
+	case "left", "right":
+		step := 1
+		if key == "left" {
+			step = len(challengeRatings)
+		}
+		m.encounterCRFilter = (m.encounterCRFilter + step) % (len(challengeRatings) + 1)
+		m.encounterMonsterCursor = 0
+	case "t":
+		types := append([]string{""}, m.rpgPack.monsterTypes()...)
+		m.encounterTypeFilter = types[(indexOf(types, m.encounterTypeFilter)+1)%len(types)]
+		m.encounterMonsterCursor = 0
+	case "enter", " ", "+", "=", "a":
+		if m.encounterMonsterCursor >= len(monsters) {
+			break
+		}
+		monster := monsters[m.encounterMonsterCursor]
+		m.encounterGroups = addToEncounter(m.encounterGroups, monster.Name, 1)
+		m.encounterMessage = fmt.Sprintf("✅ Added a %s", monster.Name)
+		return m.storeEncounterBuilder()
+	}
+	return m
+}
+
+func (m model) updateEncounterGroups(key string) model {
+	groups := append([]EncounterGroup{}, m.encounterGroups...)
+	cursor := m.encounterGroupCursor
+	switch key {
+	case "up", "k":
+		if cursor > 0 {
+			m.encounterGroupCursor--
+		}
+		return m
+	case "down", "j":
+		if cursor < len(groups)-1 {
+			m.encounterGroupCursor++
+		}
+		return m
+	case "+", "=", "right":
+		if cursor >= len(groups) {
+			return m
+		}
+		groups[cursor].Count++
+	case "-", "left":
+		if cursor >= len(groups) {
+			return m
+		}
+		groups[cursor].Count--
+		if groups[cursor].Count == 0 {
+			groups = append(groups[:cursor], groups[cursor+1:]...)
+		}
+	case "d", "delete":
+		if cursor >= len(groups) {
+			return m
+		}
+		groups = append(groups[:cursor], groups[cursor+1:]...)
+	default:
+		return m
+	}
+	m.encounterGroups = groups
+	m.encounterGroupCursor = min(m.encounterGroupCursor, max(len(groups)-1, 0))
+	return m.storeEncounterBuilder()
+}
+
+// difficultyColor shades a difficulty from green for easy to red for
+// deadly, and gray below easy.
+func difficultyColor(difficulty int) lipgloss.Color {
+	switch difficulty {
+	case 0:
+		return lipgloss.Color("#10B981")
+	case 1:
+		return lipgloss.Color("#FBBF24")
+	case 2:
+		return lipgloss.Color("#F97316")
+	case 3:
+		return lipgloss.Color("#EF4444")
+	}
+	return lipgloss.Color("#8A8A8A")
+}
+
+func (m model) viewEncounter() string {
+	if m.encounterShowTables {
+		return m.viewEncounterTables()
+	}
+	if m.encounterShowImport {
+		return m.viewEncounterImport()
+	}
+
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#B45309")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#B45309")).
+		Width(90).
+		AlignHorizontal(lipgloss.Center)
+
+	panelStyle := func(focused bool) lipgloss.Style {
+		color := lipgloss.Color("#626262")
+		if focused {
+			color = lipgloss.Color("#B45309")
+		}
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Padding(0, 1).
+			Width(43)
+	}
+
+	headingStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#B45309"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#B45309"))
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(90)
+
+	row := func(text string, selected, focused bool) string {
+		if selected && focused {
+			return selectedStyle.Render("▶ " + text)
+		}
+		if selected {
+			return "▶ " + text
+		}
+		return "  " + text
+	}
+
+	title := titleStyle.Render("👹 Encounter Builder")
+	rating := m.rateEncounterBuilder()
+
+	// Party
+	party := []string{headingStyle.Render(fmt.Sprintf("Party of %d", len(m.encounterParty)))}
+	for i, member := range m.encounterParty {
+		party = append(party, row(fmt.Sprintf("%-26s Level %2d", truncateText(partyMemberName(member, i), 26), member.Level), i == m.encounterPartyCursor, m.encounterFocus == 0))
+	}
+	if len(m.encounterParty) == 0 {
+		party = append(party, dimStyle.Render("No one yet • + to add, I for saved characters"))
+	}
+
+	// Budget and difficulty
+	budget := []string{headingStyle.Render("XP Budget")}
+	for i, name := range encounterDifficulties {
+		line := fmt.Sprintf("%-8s %6d XP", name, rating.Thresholds[i])
+		if i == rating.Difficulty {
+			line = lipgloss.NewStyle().Bold(true).Foreground(difficultyColor(i)).Render(line + " ◀")
+		}
+		budget = append(budget, line)
+	}
+	budget = append(budget, "")
+	verdict := "Trivial"
+	if rating.Difficulty >= 0 {
+		verdict = encounterDifficulties[rating.Difficulty]
+	}
+	if rating.Monsters == 0 {
+		budget = append(budget, dimStyle.Render("Add monsters to rate the encounter"))
+	} else {
+		budget = append(budget, fmt.Sprintf("%d XP × %s for %d monsters", rating.BaseXP, strconv.FormatFloat(rating.Multiplier, 'f', -1, 64), rating.Monsters))
+		budget = append(budget, lipgloss.NewStyle().Bold(true).Foreground(difficultyColor(rating.Difficulty)).Render(fmt.Sprintf("%d adjusted XP • %s", rating.AdjustedXP, verdict)))
+		if next := rating.Difficulty + 1; next < len(encounterDifficulties) {
+			budget = append(budget, dimStyle.Render(fmt.Sprintf("%d more adjusted XP for %s", rating.Thresholds[next]-rating.AdjustedXP, encounterDifficulties[next])))
+		}
+		if len(m.encounterParty) > 0 {
+			budget = append(budget, dimStyle.Render(fmt.Sprintf("%d XP each when they win", rating.BaseXP/len(m.encounterParty))))
+		}
+	}
+
+	// Monster browser, scrolled to keep the cursor in view
+	monsters := m.encounterMonsterList()
+	filter := "All CRs"
+	if m.encounterCRFilter > 0 {
+		filter = "CR " + challengeRatings[m.encounterCRFilter-1]
+	}
+	if m.encounterTypeFilter != "" {
+		filter += " • " + m.encounterTypeFilter
+	}
+	if m.encounterSearching || m.encounterSearch != "" {
+		cursor := ""
+		if m.encounterSearching {
+			cursor = "█"
+		}
+		filter += fmt.Sprintf(" • 🔍 %s%s", m.encounterSearch, cursor)
+	}
+	browser := []string{headingStyle.Render("Monsters") + dimStyle.Render(" • "+filter)}
+	const visible = 10
+	start := 0
+	if m.encounterMonsterCursor >= visible {
+		start = m.encounterMonsterCursor - visible + 1
+	}
+	for i := start; i < len(monsters) && i < start+visible; i++ {
+		monster := monsters[i]
+		browser = append(browser, row(fmt.Sprintf("%-20s CR %-4s %6d XP", truncateText(monster.Name, 20), monster.CR, challengeXP[monster.CR]), i == m.encounterMonsterCursor, m.encounterFocus == 1))
+	}
+	if len(monsters) == 0 {
+		browser = append(browser, dimStyle.Render("No monsters match"))
+	} else if m.encounterMonsterCursor < len(monsters) {
+		monster := monsters[m.encounterMonsterCursor]
+		browser = append(browser, dimStyle.Render(fmt.Sprintf("%s %s • AC %d • HP %d • Init %s", monster.Size, monster.Type, monster.AC, monster.HP, formatModifier(monster.Initiative))))
+	}
+
+	// The encounter
+	encounter := []string{headingStyle.Render("Encounter")}
+	for i, group := range m.encounterGroups {
+		monster, _ := m.rpgPack.getMonster(group.Monster)
+		encounter = append(encounter, row(fmt.Sprintf("%2d × %-16s CR %-4s %6d XP", group.Count, truncateText(group.Monster, 16), monster.CR, challengeXP[monster.CR]*group.Count), i == m.encounterGroupCursor, m.encounterFocus == 2))
+	}
+	if len(m.encounterGroups) == 0 {
+		encounter = append(encounter, dimStyle.Render("Empty • pick monsters from the list, or R to roll on a random table"))
+	}
+
+	top := lipgloss.JoinHorizontal(lipgloss.Top, panelStyle(m.encounterFocus == 0).Render(strings.Join(party, "\n")), "  ", panelStyle(false).Render(strings.Join(budget, "\n")))
+	bottom := lipgloss.JoinHorizontal(lipgloss.Top, panelStyle(m.encounterFocus == 1).Render(strings.Join(browser, "\n")), "  ", panelStyle(m.encounterFocus == 2).Render(strings.Join(encounter, "\n")))
+	elements := []string{title, top, bottom}
+	if m.encounterMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginTop(1).Render(m.encounterMessage))
+	}
+
+	var help string
+	switch m.encounterFocus {
+	case 0:
+		help = "↑/↓ to select • ←/→ level • + to add an adventurer • - to remove • I for saved characters"
+	case 1:
+		help = "↑/↓ to select • Enter to add • ←/→ CR • T type • / to search"
+	case 2:
+		help = "↑/↓ to select • +/- count • D to remove • Shift+X to clear"
+	}
+	help += "\nTab to switch panels • R for random tables • F to fight in the tracker • ESC to go back"
+	elements = append(elements, lipgloss.NewStyle().MarginTop(1).Render(helpStyle.Render(help)))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
+
+func (m model) rateEncounterBuilder() encounterRating {
+	return m.rpgPack.rateEncounter(m.encounterParty, m.encounterGroups)
+}
+
+func (m model) updateEncounterImport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "i":
+		m.encounterShowImport = false
+	case "up", "k":
+		if m.encounterImportCursor > 0 {
+			m.encounterImportCursor--
+		}
+	case "down", "j":
+		if m.encounterImportCursor < len(m.encounterRoster)-1 {
+			m.encounterImportCursor++
+		}
+	case "enter", " ":
+		if m.encounterImportCursor >= len(m.encounterRoster) {
+			break
+		}
+		c := m.encounterRoster[m.encounterImportCursor]
+		for _, member := range m.encounterParty {
+			if member.Character == c.ID {
+				m.encounterMessage = fmt.Sprintf("❌ %s is already in the party", characterDisplayName(c))
+				return m, nil
+			}
+		}
+		// A saved character takes the place of the first unnamed adventurer
+		member := PartyMember{Name: characterDisplayName(c), Level: max(1, c.Level), Character: c.ID}
+		party := append([]PartyMember{}, m.encounterParty...)
+		replaced := false
+		for i := range party {
+			if party[i].Name == "" && party[i].Character == "" {
+				party[i], replaced = member, true
+				break
+			}
+		}
+		if !replaced {
+			party = append(party, member)
+		}
+		m.encounterParty = party
+		m.encounterMessage = fmt.Sprintf("✅ %s joins the party", member.Name)
+		m = m.storeEncounterBuilder()
+	}
+	return m, nil
+}
+
+func (m model) viewEncounterImport() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#B45309")).
+		Padding(1, 2).
+		MarginBottom(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#B45309")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#B45309")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(70)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#B45309"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render("🧙 Party from Saved Characters")
+
+	var rows []string
+	if len(m.encounterRoster) == 0 {
+		rows = append(rows, "No saved characters yet.\n\nSave characters with W in the RPG Character Creator.")
+	}
+	for i, c := range m.encounterRoster {
+		marker := " "
+		for _, member := range m.encounterParty {
+			if member.Character == c.ID {
+				marker = "✓"
+			}
+		}
+		row := fmt.Sprintf("%s %-28s Level %-2d %s %s", marker, truncateText(characterDisplayName(c), 28), max(1, c.Level), c.Race, c.Class)
+		if i == m.encounterImportCursor {
+			row = selectedStyle.Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	elements := []string{title, listStyle.Render(strings.Join(rows, "\n"))}
+	if m.encounterMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.encounterMessage))
+	}
+	elements = append(elements, helpStyle.Render("↑/↓ to select • Enter to add to the party • ESC when done"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
+
+// truncateText shortens text to a width, ending it with an ellipsis.
+func truncateText(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EncounterTable is a user-defined random encounter table: roll its dice and
+// read off the entry whose range holds the total.
+type EncounterTable struct {
+	Name    string                `json:"name"`
+	Dice    string                `json:"dice"`
+	Entries []EncounterTableEntry `json:"entries"`
+}
+
+// EncounterTableEntry is one row of a random table. A result that starts
+// with a number or dice and names a monster in the pack, such as
+// "2d4 Wolves", adds those monsters to the encounter; anything else is
+// just read out.
+type EncounterTableEntry struct {
+	Min    int    `json:"min"`
+	Max    int    `json:"max"`
+	Result string `json:"result"`
+}
+
+type encounterBuilderFile struct {
+	Party  []PartyMember    `json:"party"`
+	Groups []EncounterGroup `json:"encounter"`
+	Tables []EncounterTable `json:"tables"`
+}
+
+// defaultEncounterTables start a new builder off with something to roll on.
+var defaultEncounterTables = []EncounterTable{
+	{Name: "Forest Road", Dice: "1d8", Entries: []EncounterTableEntry{
+		{1, 2, "2d4 Wolves"},
+		{3, 3, "1d6+2 Goblins"},
+		{4, 4, "1d4 Bandits"},
+		{5, 5, "1 Brown Bear"},
+		{6, 6, "A merchant's cart with a broken wheel"},
+		{7, 7, "1d3 Dire Wolves"},
+		{8, 8, "1 Owlbear"},
+	}},
+	{Name: "Crypt", Dice: "1d6", Entries: []EncounterTableEntry{
+		{1, 2, "2d4 Skeletons"},
+		{3, 3, "1d6 Zombies"},
+		{4, 4, "1d3 Ghouls"},
+		{5, 5, "1d4 Shadows"},
+		{6, 6, "A sealed door whispering a name"},
+	}},
+}
+
+func getEncounterBuilderFilePath() string {
+	return filepath.Join(getDataDir(), "encounter_builder.json")
+}
+
+// loadEncounterBuilder reads the party, encounter and random tables the
+// builder was left with, starting with the default tables the first time.
+// A file that can't be read is an error, and saveEncounterBuilder won't
+// write over it.
+func loadEncounterBuilder() (encounterBuilderFile, error) {
+	file := encounterBuilderFile{Tables: defaultEncounterTables}
+	data, err := os.ReadFile(getEncounterBuilderFilePath())
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return file, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return encounterBuilderFile{Tables: defaultEncounterTables}, fmt.Errorf("%s is damaged (%s) • fix or move it to save the builder again", getEncounterBuilderFilePath(), describeJSONError(data, 0, err))
+	}
+	return file, nil
+}
+
+func saveEncounterBuilder(file encounterBuilderFile) error {
+	if _, err := loadEncounterBuilder(); err != nil {
+		return err
+	}
+	if file.Tables == nil {
+		file.Tables = []EncounterTable{}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getEncounterBuilderFilePath(), data, 0644)
+}
+
+// parseEncounterTable reads a new table as "name dice", e.g. "Swamp 1d12".
+func parseEncounterTable(input string) (EncounterTable, error) {
+	fields := strings.Fields(input)
+	if len(fields) < 2 {
+		return EncounterTable{}, fmt.Errorf("enter a name and the dice to roll, e.g. Swamp 1d12")
+	}
+	dice := fields[len(fields)-1]
+	if _, err := parseDiceExpression(dice); err != nil {
+		return EncounterTable{}, fmt.Errorf("dice: %v", err)
+	}
+	return EncounterTable{Name: strings.Join(fields[:len(fields)-1], " "), Dice: dice}, nil
+}
+
+// parseEncounterTableEntry reads a table row as "range result", where the
+// range is a single roll or "low-high", e.g. "3-4 1d6 Giant Rats".
+func parseEncounterTableEntry(input string) (EncounterTableEntry, error) {
+	usage := fmt.Errorf("enter the roll or range, then the result, e.g. 3-4 1d6 Giant Rats")
+	fields := strings.Fields(input)
+	if len(fields) < 2 {
+		return EncounterTableEntry{}, usage
+	}
+	low, high, found := strings.Cut(fields[0], "-")
+	if !found {
+		high = low
+	}
+	entry := EncounterTableEntry{Result: strings.Join(fields[1:], " ")}
+	var err error
+	if entry.Min, err = strconv.Atoi(low); err != nil {
+		return EncounterTableEntry{}, usage
+	}
+	if entry.Max, err = strconv.Atoi(high); err != nil {
+		return EncounterTableEntry{}, usage
+	}
+	if entry.Max < entry.Min {
+		return EncounterTableEntry{}, fmt.Errorf("the range %d-%d runs backwards", entry.Min, entry.Max)
+	}
+	return entry, nil
+}
+
+// addTableEntry adds an entry to a table, keeping the entries in order and
+// refusing one that overlaps an existing range.
+func addTableEntry(table EncounterTable, entry EncounterTableEntry) (EncounterTable, error) {
+	for _, existing := range table.Entries {
+		if entry.Min <= existing.Max && existing.Min <= entry.Max {
+			return table, fmt.Errorf("%s overlaps %s %s", formatTableRange(entry), formatTableRange(existing), existing.Result)
+		}
+	}
+	entries := append([]EncounterTableEntry{}, table.Entries...)
+	i := 0
+	for i < len(entries) && entries[i].Min < entry.Min {
+		i++
+	}
+	entries = append(entries[:i], append([]EncounterTableEntry{entry}, entries[i:]...)...)
+	table.Entries = entries
+	return table, nil
+}
+
+func formatTableRange(entry EncounterTableEntry) string {
+	if entry.Min == entry.Max {
+		return strconv.Itoa(entry.Min)
+	}
+	return fmt.Sprintf("%d-%d", entry.Min, entry.Max)
+}
+
+// tableRoll is the outcome of rolling on a random table.
+type tableRoll struct {
+	Roll    DiceRollResult
+	Entry   *EncounterTableEntry // nil when no entry covers the roll
+	Monster string               // the monster the entry adds, if any
+	Count   int
+	Detail  string // how the count was rolled, e.g. "2d4: 5"
+}
+
+// rollEncounterTable rolls the table's dice through the dice engine and,
+// when the matching entry names monsters, rolls how many turn up.
+func (pack ContentPack) rollEncounterTable(table EncounterTable) (tableRoll, error) {
+	roll, err := rollDiceExpression(table.Dice)
+	if err != nil {
+		return tableRoll{}, err
+	}
+	result := tableRoll{Roll: roll}
+	for i := range table.Entries {
+		if entry := table.Entries[i]; roll.Total >= entry.Min && roll.Total <= entry.Max {
+			result.Entry = &table.Entries[i]
+			break
+		}
+	}
+	if result.Entry == nil {
+		return result, nil
+	}
+
+	monster, dice, ok := pack.entryMonster(*result.Entry)
+	if !ok {
+		return result, nil
+	}
+	count, err := rollDiceExpression(dice)
+	if err != nil {
+		return result, nil
+	}
+	result.Monster = monster.Name
+	result.Count = max(count.Total, 1)
+	if _, err := strconv.Atoi(dice); err != nil {
+		result.Detail = fmt.Sprintf("%s: %d", count.Expression, count.Total)
+	}
+	return result, nil
+}
+
+// entryMonster reads the monster and how many of them, as a number or dice,
+// from an entry such as "2d4 Wolves".
+func (pack ContentPack) entryMonster(entry EncounterTableEntry) (PackMonster, string, bool) {
+	fields := strings.Fields(entry.Result)
+	if len(fields) < 2 {
+		return PackMonster{}, "", false
+	}
+	if _, err := parseDiceExpression(fields[0]); err != nil {
+		return PackMonster{}, "", false
+	}
+	monster, ok := pack.findMonster(strings.Join(fields[1:], " "))
+	return monster, fields[0], ok
+}
+
+func (m model) updateEncounterTables(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.encounterPrompt != "" {
+		return m.updateEncounterPrompt(msg)
+	}
+
+	selected := m.encounterTableCursor < len(m.encounterTables)
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "r":
+		m.encounterShowTables = false
+		m.encounterMessage = ""
+	case "up", "k":
+		if m.encounterTableCursor > 0 {
+			m.encounterTableCursor--
+		}
+		m.encounterMessage = ""
+	case "down", "j":
+		if m.encounterTableCursor < len(m.encounterTables)-1 {
+			m.encounterTableCursor++
+		}
+		m.encounterMessage = ""
+	case "enter", " ":
+		if !selected {
+			break
+		}
+		table := m.encounterTables[m.encounterTableCursor]
+		roll, err := m.rpgPack.rollEncounterTable(table)
+		if err != nil {
+			m.encounterMessage = "❌ " + err.Error()
+			break
+		}
+		switch {
+		case roll.Entry == nil:
+			m.encounterMessage = fmt.Sprintf("🎲 %s rolled %d • nothing on the table for that roll", table.Dice, roll.Roll.Total)
+		case roll.Monster == "":
+			m.encounterMessage = fmt.Sprintf("🎲 %s rolled %d • %s", table.Dice, roll.Roll.Total, roll.Entry.Result)
+		default:
+			detail := ""
+			if roll.Detail != "" {
+				detail = fmt.Sprintf(" (%s)", roll.Detail)
+			}
+			m.encounterGroups = addToEncounter(m.encounterGroups, roll.Monster, roll.Count)
+			m.encounterMessage = fmt.Sprintf("🎲 %s rolled %d • %d × %s%s added to the encounter", table.Dice, roll.Roll.Total, roll.Count, roll.Monster, detail)
+			m = m.storeEncounterBuilder()
+		}
+	case "n":
+		m.encounterPrompt = "table"
+		m.encounterInput = ""
+		m.encounterMessage = ""
+	case "a":
+		if selected {
+			m.encounterPrompt = "entry"
+			m.encounterInput = ""
+			m.encounterMessage = ""
+		}
+	case "d":
+		if selected && len(m.encounterTables[m.encounterTableCursor].Entries) > 0 {
+			tables := append([]EncounterTable{}, m.encounterTables...)
+			table := tables[m.encounterTableCursor]
+			table.Entries = table.Entries[:len(table.Entries)-1]
+			tables[m.encounterTableCursor] = table
+			m.encounterTables = tables
+			m.encounterMessage = "✅ Removed the last entry"
+			m = m.storeEncounterBuilder()
+		}
+	case "x":
+		if selected {
+			name := m.encounterTables[m.encounterTableCursor].Name
+			tables := append([]EncounterTable{}, m.encounterTables...)
+			m.encounterTables = append(tables[:m.encounterTableCursor], tables[m.encounterTableCursor+1:]...)
+			m.encounterTableCursor = min(m.encounterTableCursor, max(len(m.encounterTables)-1, 0))
+			m.encounterMessage = fmt.Sprintf("✅ Deleted %q", name)
+			m = m.storeEncounterBuilder()
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateEncounterPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.encounterPrompt = ""
+		m.encounterInput = ""
+	case "backspace":
+		if len(m.encounterInput) > 0 {
+			m.encounterInput = m.encounterInput[:len(m.encounterInput)-1]
+		}
+	case "enter":
+		input := strings.TrimSpace(m.encounterInput)
+		if input == "" {
+			return m, nil
+		}
+		tables := append([]EncounterTable{}, m.encounterTables...)
+		if m.encounterPrompt == "table" {
+			table, err := parseEncounterTable(input)
+			if err != nil {
+				m.encounterMessage = "❌ " + err.Error()
+				return m, nil
+			}
+			tables = append(tables, table)
+			m.encounterTableCursor = len(tables) - 1
+			m.encounterMessage = fmt.Sprintf("✅ Added %q • A to add entries", table.Name)
+		} else {
+			entry, err := parseEncounterTableEntry(input)
+			if err != nil {
+				m.encounterMessage = "❌ " + err.Error()
+				return m, nil
+			}
+			table, err := addTableEntry(tables[m.encounterTableCursor], entry)
+			if err != nil {
+				m.encounterMessage = "❌ " + err.Error()
+				return m, nil
+			}
+			tables[m.encounterTableCursor] = table
+			m.encounterMessage = fmt.Sprintf("✅ Added %s %s", formatTableRange(entry), entry.Result)
+		}
+		m.encounterTables = tables
+		m.encounterPrompt = ""
+		m.encounterInput = ""
+		m = m.storeEncounterBuilder()
+	default:
+		if len(msg.String()) == 1 {
+			m.encounterInput += msg.String()
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewEncounterTables() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#B45309")).
+		Padding(1, 2).
+		MarginBottom(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#B45309")).
+		Width(80).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#B45309")).
+		Padding(1, 2).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#B45309"))
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(80)
+
+	title := titleStyle.Render("🎲 Random Encounter Tables")
+
+	var tables []string
+	if len(m.encounterTables) == 0 {
+		tables = append(tables, "No tables yet.\n\nN to add one.")
+	}
+	for i, table := range m.encounterTables {
+		row := fmt.Sprintf("%-20s %s", truncateText(table.Name, 20), table.Dice)
+		if i == m.encounterTableCursor {
+			row = selectedStyle.Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		tables = append(tables, row)
+	}
+
+	var entries []string
+	if m.encounterTableCursor < len(m.encounterTables) {
+		table := m.encounterTables[m.encounterTableCursor]
+		for _, entry := range table.Entries {
+			result := entry.Result
+			if monster, _, ok := m.rpgPack.entryMonster(entry); ok {
+				result += dimStyle.Render(" • CR " + monster.CR)
+			}
+			entries = append(entries, fmt.Sprintf("%5s  %s", formatTableRange(entry), result))
+		}
+		if len(table.Entries) == 0 {
+			entries = append(entries, dimStyle.Render("No entries • A to add one"))
+		}
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top,
+		listStyle.Width(30).Render(strings.Join(tables, "\n")),
+		"  ",
+		listStyle.Width(48).Render(strings.Join(entries, "\n")))
+	elements := []string{title, body}
+
+	if m.encounterPrompt != "" {
+		label := "New table (name and dice, e.g. Swamp 1d12):"
+		if m.encounterPrompt == "entry" {
+			label = "New entry (roll or range, then result, e.g. 3-4 1d6 Giant Rats):"
+		}
+		elements = append(elements, lipgloss.NewStyle().MarginBottom(1).Render(label+" "+m.encounterInput+"█"))
+	}
+	if m.encounterMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.encounterMessage))
+	}
+
+	help := "↑/↓ to select • Enter to roll • N new table • A add entry • D remove last entry • X delete table • ESC to go back"
+	if m.encounterPrompt != "" {
+		help = "Enter to add • ESC to cancel"
+	}
+	elements = append(elements, helpStyle.Render(help))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
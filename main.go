@@ -16,17 +16,16 @@ import (
 
 func initialModel() model {
 	rand.Seed(time.Now().UnixNano())
-	choices := []string{"QR Code Generator", "Dice Roller", "Wheel Spinner", "RPG Character Creator", "Todo List", "Pomodoro Timer", "Base64 Encoder/Decoder", "Unit Converter", "System Info", "Network Info", "Quit"}
+	choices := []string{"QR Code Generator", "Dice Roller", "Wheel Spinner", "RPG Character Creator", "Todo List", "Pomodoro Timer", "Base64 Encoder/Decoder", "Unit Converter", "System Info", "Network Info", "File Share", "Initiative Tracker", "Encounter Builder", "Quit"}
 	m := model{
 		state:           menuView,
 		choices:         choices,
 		selected:        make(map[int]struct{}),
 		filteredChoices: make([]int, len(choices)), // Initialize with all choices
 		diceTypes:       []string{"d4", "d6", "d8", "d10", "d12", "d20"},
-		wheelItems:      []string{}, // Start empty
-		rpgCharacter:    make(map[string]int),
-		rpgClasses:      []string{"Barbarian", "Rogue", "Wizard", "Paladin", "Warlock", "Cleric", "Monk", "Ranger"},
-		todoItems:       loadTodos(),
+		wheelItems:      []WheelItem{}, // Start empty
+		wheelModeN:      2,
+		rpgAbilityMethod: abilityMethod4d6RerollOnes,
 		todoFilter:      "all",
 		pomodoroDuration: 25 * time.Minute, // Default 25-minute work session
 		pomodoroSession:  1,
@@ -34,6 +33,18 @@ func initialModel() model {
 		base64InputMode:  true,
 	}
 	
+	var err error
+	if m.diceHistory, m.diceHistoryPersist, err = loadDiceHistory(); err != nil {
+		m.diceMessage = "❌ " + err.Error()
+	}
+	m.diceMacros, _ = loadDiceMacros() // macro mode reports a damaged file
+	m.wheelSaved, _ = loadWheels() // the picker reports a damaged file
+	if m.wheelHistory, err = loadWheelHistory(); err != nil {
+		m.wheelMessage = "❌ " + err.Error()
+	}
+	m.rpgRoster, _ = loadCharacters() // the roster screen reports a damaged file
+	m = m.loadRPGPacks()
+	
 	// Initialize unit converter
 	m = initUnitConverter(m)
 	
@@ -68,8 +79,34 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateWheelSpinner(msg)
 	case rpgCharacterView:
 		return m.updateRPGCharacter(msg)
+	case rpgRaceSelectionView:
+		return m.updateRPGRaceSelection(msg)
 	case rpgClassSelectionView:
 		return m.updateRPGClassSelection(msg)
+	case rpgBackgroundSelectionView:
+		return m.updateRPGBackgroundSelection(msg)
+	case rpgAbilityMethodView:
+		return m.updateRPGAbilityMethod(msg)
+	case rpgAbilityScoresView:
+		return m.updateRPGAbilityScores(msg)
+	case rpgRosterView:
+		return m.updateRPGRoster(msg)
+	case rpgEditView:
+		return m.updateRPGEdit(msg)
+	case rpgLevelUpView:
+		return m.updateRPGLevelUp(msg)
+	case rpgPackView:
+		return m.updateRPGPacks(msg)
+	case rpgEquipmentView:
+		return m.updateRPGEquipment(msg)
+	case rpgExportView:
+		return m.updateRPGExport(msg)
+	case rpgPersonaView:
+		return m.updateRPGPersona(msg)
+	case rpgPartyView:
+		return m.updateRPGParty(msg)
+	case rpgSpellsView:
+		return m.updateRPGSpells(msg)
 	case todoListView:
 		return m.updateTodoList(msg)
 	case pomodoroView:
@@ -82,6 +119,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateNetworkInfo(msg)
 	case unitConverterView:
 		return m.updateUnitConverter(msg)
+	case shareView:
+		return m.updateShare(msg)
+	case combatView:
+		return m.updateCombat(msg)
+	case encounterView:
+		return m.updateEncounter(msg)
 	}
 	return m, nil
 }
@@ -98,8 +141,34 @@ func (m model) View() string {
 		return m.viewWheelSpinner()
 	case rpgCharacterView:
 		return m.viewRPGCharacter()
+	case rpgRaceSelectionView:
+		return m.viewRPGRaceSelection()
 	case rpgClassSelectionView:
 		return m.viewRPGClassSelection()
+	case rpgBackgroundSelectionView:
+		return m.viewRPGBackgroundSelection()
+	case rpgAbilityMethodView:
+		return m.viewRPGAbilityMethod()
+	case rpgAbilityScoresView:
+		return m.viewRPGAbilityScores()
+	case rpgRosterView:
+		return m.viewRPGRoster()
+	case rpgEditView:
+		return m.viewRPGEdit()
+	case rpgLevelUpView:
+		return m.viewRPGLevelUp()
+	case rpgPackView:
+		return m.viewRPGPacks()
+	case rpgEquipmentView:
+		return m.viewRPGEquipment()
+	case rpgExportView:
+		return m.viewRPGExport()
+	case rpgPersonaView:
+		return m.viewRPGPersona()
+	case rpgPartyView:
+		return m.viewRPGParty()
+	case rpgSpellsView:
+		return m.viewRPGSpells()
 	case todoListView:
 		return m.viewTodoList()
 	case pomodoroView:
@@ -112,6 +181,12 @@ func (m model) View() string {
 		return m.viewNetworkInfo()
 	case unitConverterView:
 		return m.viewUnitConverter()
+	case shareView:
+		return m.viewShare()
+	case combatView:
+		return m.viewCombat()
+	case encounterView:
+		return m.viewEncounter()
 	}
 	return ""
 }
@@ -121,6 +196,9 @@ func main() {
 		testTodoPersistence()
 		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "wheel" {
+		os.Exit(runWheelCommand(os.Args[2:]))
+	}
 	
 	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
@@ -469,7 +547,29 @@ func (m model) viewBase64() string {
 // All other tool implementations are now in separate files:
 // - dice.go: Dice roller functionality
 // - wheel.go: Wheel spinner functionality  
+// - wheel_store.go: Saved wheels and the wheel command
+// - wheel_edit.go: Wheel item editing and undo
+// - wheel_modes.go: Elimination, queue, team and draw modes, spin history
+// - wheel_render.go: Circular wheel rendering and spin physics
 // - rpg.go: RPG character creator functionality
+// - rpg_origins.go: RPG races, backgrounds and the creation wizard
+// - rpg_stats.go: Derived character stats such as HP, AC, saves and skills
+// - rpg_abilities.go: Ability score methods, assignment and point buy
+// - rpg_roster.go: Saved characters, the character editor and leveling up
+// - rpg_packs.go: Content packs of classes, races, backgrounds and gear
+// - rpg_equipment.go: Starting equipment choices and starting wealth
+// - rpg_export.go: Character sheet exports from templates
+// - rpg_persona.go: Character names, personality and backstory hooks
+// - rpg_party.go: Party generator with role balancing and party exports
+// - rpg_spells.go: Spell lists, spell choices and spell slot tracking
+// - pdf.go: Minimal PDF writer for printable sheets
+// - combat.go: Initiative and combat tracker
+// - combat_store.go: Saved encounters and adding saved characters to a fight
+// - encounter.go: Encounter difficulty builder and monster browser
+// - encounter_store.go: Encounter builder saves and random encounter tables
 // - pomodoro.go: Pomodoro timer functionality
 // - todo.go: Todo list functionality
-// - system_info.go: System and network info functionality
\ No newline at end of file
+// - todo_store.go: Todo saving, locking, backups and recovery
+// - todo_meta.go: Todo priorities, dates, tags, projects, search and sorting
+// - system_info.go: System and network info functionality
+// - share.go: LAN file sharing functionality
\ No newline at end of file
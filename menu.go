@@ -90,24 +90,36 @@ func (m model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.diceResult = 0
 					m.diceType = ""
 					m.diceRolling = false
+					m.diceInputMode = false
+					m.diceRoll = DiceRollResult{}
+					m.diceMessage = ""
+					m.diceShowHistory = false
+					m.diceShowCalc = false
+					m.diceShowMacros = false
+					m.diceMacroAdding = false
+					m.diceShowTray = false
 				case 2: // Wheel Spinner
 					m.state = wheelSpinnerView
 					m.wheelSpinning = false
 					m.wheelResult = ""
 					m.wheelInputMode = false
 					m.wheelInput = ""
+					m.wheelWeightInput = ""
+					m.wheelInputField = 0
+					m.wheelShowPicker = false
+					m.wheelPrompt = ""
+					m.wheelMessage = ""
+					m.wheelEditing = false
+					m.wheelShowHistory = false
 				case 3: // RPG Character Creator
-					m.state = rpgClassSelectionView
+					m.state = rpgRaceSelectionView
+					m.rpgRaceCursor = 0
 					m.rpgClassCursor = 0
-					m.rpgSelectedClass = ""
-					m.rpgCharacter = make(map[string]int)
+					m.rpgBackgroundCursor = 0
+					m.rpgCharacter = Character{}
 					m.rpgRolling = false
 				case 4: // Todo List
-					m.state = todoListView
-					m.todoInputMode = false
-					m.todoInput = ""
-					m.todoCursor = 0
-					m.todoMessage = ""
+					return m.openTodoList()
 				case 5: // Pomodoro Timer
 					m.state = pomodoroView
 					m.pomodoroMessage = ""
@@ -130,7 +142,25 @@ func (m model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.networkInterfaces = getNetworkInfo()
 					m.networkInfoMessage = "Network information loaded"
 					m.networkInfoLastUpdate = time.Now()
-				case 10: // Quit
+				case 10: // File Share
+					m.state = shareView
+					m.shareInput = ""
+					m.shareAsText = false
+					m.shareField = shareFieldSource
+					m.shareAddresses = getShareAddresses()
+					m.shareAddressCursor = 0
+					m.shareQRCode = ""
+					m.shareMessage = ""
+				case 11: // Initiative Tracker
+					m.state = combatView
+					m.combatPrompt = ""
+					m.combatInput = ""
+					m.combatMessage = ""
+					m.combatShowPicker = false
+					m.combatShowImport = false
+				case 12: // Encounter Builder
+					m = m.openEncounterBuilder()
+				case 13: // Quit
 					return m, tea.Quit
 				}
 			}
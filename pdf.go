@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A small PDF writer for plain text documents. It uses the standard Courier
+// fonts, which every PDF reader provides, so nothing needs embedding. Text is
+// encoded as WinAnsi; characters outside it are printed as "?".
+
+const (
+	pdfPageWidth  = 612.0 // US Letter, in points
+	pdfPageHeight = 792.0
+	pdfMargin     = 54.0
+	pdfFontSize   = 10.0
+	pdfLeading    = 13.0
+	pdfCharWidth  = 0.6 * pdfFontSize // Courier glyphs are 600/1000 em wide
+)
+
+// pdfLineChars is how many characters of Courier fit between the margins.
+var pdfLineChars = int((pdfPageWidth - 2*pdfMargin) / pdfCharWidth)
+
+// pdfWinAnsi maps the characters outside Latin-1 that WinAnsi encodes.
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+type pdfDocument struct {
+	pages []*bytes.Buffer // one content stream per page
+	y     float64         // baseline of the next line on the current page
+}
+
+func (d *pdfDocument) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin - pdfFontSize
+}
+
+// space moves down by height points, starting a new page if it doesn't fit.
+func (d *pdfDocument) space(height float64) *bytes.Buffer {
+	if len(d.pages) == 0 || d.y-height < pdfMargin-pdfFontSize {
+		d.addPage()
+	} else {
+		d.y -= height
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// writeLine prints one line of text, bold or regular, wrapping it at the
+// right margin.
+func (d *pdfDocument) writeLine(text string, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	for _, line := range wrapPDFLine(text, pdfLineChars) {
+		page := d.space(pdfLeading)
+		fmt.Fprintf(page, "BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", font, pdfFontSize, pdfMargin, d.y, pdfEscape(line))
+	}
+}
+
+// writeRule draws a horizontal line across the page.
+func (d *pdfDocument) writeRule() {
+	page := d.space(pdfLeading / 2)
+	y := d.y + pdfFontSize
+	fmt.Fprintf(page, "0.75 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+	d.y += pdfFontSize / 2
+}
+
+// bytes assembles the document: catalog, page tree, the two fonts, then a
+// page object and content stream for each page, followed by the
+// cross-reference table.
+func (d *pdfDocument) bytes() []byte {
+	if len(d.pages) == 0 {
+		d.addPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// pdfEscape encodes text as a WinAnsi PDF string body, escaping the
+// characters that are special inside parentheses.
+func pdfEscape(text string) string {
+	var out strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteByte(byte(r))
+		case r == '\t':
+			out.WriteString("    ")
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			out.WriteByte(byte(r))
+		case pdfWinAnsi[r] != 0:
+			out.WriteByte(pdfWinAnsi[r])
+		case r >= 0xFE00 && r <= 0xFE0F, r == 0x200D:
+			// Variation selectors and joiners have no glyph of their own
+		default:
+			out.WriteByte('?')
+		}
+	}
+	return out.String()
+}
+
+// wrapPDFLine splits a line into pieces of at most width characters,
+// breaking at spaces where possible and indenting continuations to match
+// the line's own indent.
+func wrapPDFLine(text string, width int) []string {
+	runes := []rune(strings.TrimRight(text, " "))
+	if len(runes) <= width {
+		return []string{string(runes)}
+	}
+
+	indent := 0
+	for indent < len(runes) && runes[indent] == ' ' {
+		indent++
+	}
+	indent = min(indent+2, width/2)
+
+	var lines []string
+	for len(runes) > width {
+		cut := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, string(runes[:cut]))
+		rest := strings.TrimLeft(string(runes[cut:]), " ")
+		runes = []rune(strings.Repeat(" ", indent) + rest)
+	}
+	return append(lines, string(runes))
+}
+
+// renderTextPDF lays out a plain text document as a PDF. A line made only of
+// "-" or "=" becomes a rule, and the line above it is set in bold as a
+// heading, so the text character sheet keeps its section headings. A form
+// feed starts a new page.
+func renderTextPDF(text string) []byte {
+	isRule := func(line string) bool {
+		line = strings.TrimSpace(line)
+		return len(line) >= 3 && (strings.Trim(line, "-") == "" || strings.Trim(line, "=") == "")
+	}
+
+	var doc pdfDocument
+	for page, pageText := range strings.Split(text, "\f") {
+		if page > 0 {
+			doc.addPage()
+		}
+		lines := strings.Split(strings.Trim(pageText, "\n"), "\n")
+		for i, line := range lines {
+			if isRule(line) {
+				doc.writeRule()
+				continue
+			}
+			heading := i+1 < len(lines) && isRule(lines[i+1])
+			doc.writeLine(line, heading)
+		}
+	}
+	return doc.bytes()
+}
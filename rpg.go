@@ -2,8 +2,6 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
-	"os"
 	"strings"
 	"time"
 
@@ -18,7 +16,7 @@ func (m model) updateRPGClassSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "esc":
-			m.state = menuView
+			m.state = rpgRaceSelectionView
 		case "up", "k":
 			if m.rpgClassCursor > 0 {
 				m.rpgClassCursor--
@@ -28,340 +26,72 @@ func (m model) updateRPGClassSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.rpgClassCursor++
 			}
 		case "enter", " ":
-			m.rpgSelectedClass = m.rpgClasses[m.rpgClassCursor]
-			m.state = rpgCharacterView
-			// Generate new character with the selected class
-			m.rpgCharacter = generateCharacter(m.rpgSelectedClass)
-			m.rpgGear = getStartingGear(m.rpgSelectedClass)
-			m.rpgGold = generateGold()
-			m.rpgExportStatus = ""
+			m.state = rpgBackgroundSelectionView
 		}
 	}
 	return m, nil
 }
 
-func getClassStats(className string) ClassStats {
-	// For now, using placeholder values - you can customize these later
-	classMap := map[string]ClassStats{
-		"Barbarian": {Primary: "Strength", Secondary: "Constitution"},
-		"Rogue":     {Primary: "Dexterity", Secondary: "Intelligence"},
-		"Wizard":    {Primary: "Intelligence", Secondary: "Wisdom"},
-		"Paladin":   {Primary: "Strength", Secondary: "Charisma"},
-		"Warlock":   {Primary: "Charisma", Secondary: "Constitution"},
-		"Cleric":    {Primary: "Wisdom", Secondary: "Constitution"},
-		"Monk":      {Primary: "Dexterity", Secondary: "Wisdom"},
-		"Ranger":    {Primary: "Dexterity", Secondary: "Wisdom"},
+func (pack ContentPack) getClassStats(className string) ClassStats {
+	for _, class := range pack.Classes {
+		if class.Name == className {
+			return class.ClassStats
+		}
 	}
-	return classMap[className]
+	return ClassStats{}
 }
 
-func getStartingGear(className string) StartingGear {
-	gearMap := map[string]StartingGear{
-		"Barbarian": {
-			Weapons: []string{"Greataxe", "Handaxe (2)", "Javelin (4)"},
-			Armor:   []string{"Leather armor", "Shield"},
-			Items:   []string{"Explorer's pack", "Bedroll", "Mess kit", "Tinderbox", "Torches (10)", "Rations (10 days)", "Waterskin", "Hemp rope (50 feet)"},
-		},
-		"Rogue": {
-			Weapons: []string{"Rapier", "Shortbow", "Arrows (20)", "Dagger (2)"},
-			Armor:   []string{"Leather armor"},
-			Items:   []string{"Burglar's pack", "Thieves' tools", "Bedroll", "Mess kit", "Tinderbox", "Torches (10)", "Rations (10 days)", "Waterskin", "Hemp rope (50 feet)"},
-		},
-		"Wizard": {
-			Weapons: []string{"Quarterstaff", "Dagger (2)"},
-			Armor:   []string{},
-			Items:   []string{"Scholar's pack", "Spellbook", "Component pouch", "Bedroll", "Mess kit", "Tinderbox", "Torches (10)", "Rations (10 days)", "Waterskin", "Hemp rope (50 feet)"},
-		},
-		"Paladin": {
-			Weapons: []string{"Longsword", "Shield", "Javelin (5)"},
-			Armor:   []string{"Chain mail"},
-			Items:   []string{"Explorer's pack", "Holy symbol", "Bedroll", "Mess kit", "Tinderbox", "Torches (10)", "Rations (10 days)", "Waterskin", "Hemp rope (50 feet)"},
-		},
-		"Warlock": {
-			Weapons: []string{"Light crossbow", "Crossbow bolts (20)", "Dagger (2)"},
-			Armor:   []string{"Leather armor"},
-			Items:   []string{"Scholar's pack", "Arcane focus", "Simple weapon", "Bedroll", "Mess kit", "Tinderbox", "Torches (10)", "Rations (10 days)", "Waterskin", "Hemp rope (50 feet)"},
-		},
-		"Cleric": {
-			Weapons: []string{"Mace", "Light crossbow", "Crossbow bolts (20)", "Shield"},
-			Armor:   []string{"Scale mail"},
-			Items:   []string{"Explorer's pack", "Holy symbol", "Bedroll", "Mess kit", "Tinderbox", "Torches (10)", "Rations (10 days)", "Waterskin", "Hemp rope (50 feet)"},
-		},
-		"Monk": {
-			Weapons: []string{"Shortsword", "Dart (10)"},
-			Armor:   []string{},
-			Items:   []string{"Dungeoneer's pack", "Bedroll", "Mess kit", "Tinderbox", "Torches (10)", "Rations (10 days)", "Waterskin", "Hemp rope (50 feet)"},
-		},
-		"Ranger": {
-			Weapons: []string{"Longbow", "Arrows (20)", "Shortsword (2)", "Handaxe (2)"},
-			Armor:   []string{"Leather armor"},
-			Items:   []string{"Explorer's pack", "Bedroll", "Mess kit", "Tinderbox", "Torches (10)", "Rations (10 days)", "Waterskin", "Hemp rope (50 feet)"},
-		},
+func (pack ContentPack) getWeaponStats(weaponName string) (WeaponStats, bool) {
+	for _, weapon := range pack.Weapons {
+		if weapon.Name == weaponName {
+			return weapon.WeaponStats, true
+		}
 	}
-	return gearMap[className]
+	return WeaponStats{}, false
 }
 
-func generateGold() int {
-	roll := rand.Intn(100)
-	if roll < 10 { // 10% chance
-		return 60
-	} else if roll < 40 { // 30% chance (10 + 30)
-		return 40
-	} else { // 60% chance (remaining)
-		return 20
+// gearBaseName strips a quantity suffix, turning "Handaxe (2)" into "Handaxe".
+func gearBaseName(item string) string {
+	if i := strings.Index(item, " ("); i >= 0 {
+		return item[:i]
 	}
+	return item
 }
 
-func exportCharacterText(className string, character map[string]int, gear StartingGear, gold int) (string, error) {
-	var content strings.Builder
-	
-	content.WriteString("===============================\n")
-	content.WriteString("       D&D 5E CHARACTER SHEET\n")
-	content.WriteString("===============================\n\n")
-	
-	if className != "" {
-		content.WriteString(fmt.Sprintf("Class: %s\n\n", className))
-	}
-	
-	content.WriteString("ABILITY SCORES:\n")
-	content.WriteString("---------------\n")
-	
-	stats := []string{"Strength", "Constitution", "Intelligence", "Wisdom", "Charisma", "Dexterity"}
-	classStats := getClassStats(className)
-	
-	for _, stat := range stats {
-		if value, exists := character[stat]; exists {
-			marker := ""
-			if stat == classStats.Primary {
-				marker = " (Primary)"
-			} else if stat == classStats.Secondary {
-				marker = " (Secondary)"
-			}
-			content.WriteString(fmt.Sprintf("%-13s: %2d%s\n", stat, value, marker))
-		}
-	}
-	
-	content.WriteString(fmt.Sprintf("\nGOLD: %d gp\n\n", gold))
-	
-	if len(gear.Weapons) > 0 {
-		content.WriteString("WEAPONS:\n")
-		content.WriteString("--------\n")
-		for _, weapon := range gear.Weapons {
-			content.WriteString(fmt.Sprintf("• %s\n", weapon))
-		}
-		content.WriteString("\n")
-	}
-	
-	if len(gear.Armor) > 0 {
-		content.WriteString("ARMOR:\n")
-		content.WriteString("------\n")
-		for _, armor := range gear.Armor {
-			content.WriteString(fmt.Sprintf("• %s\n", armor))
-		}
-		content.WriteString("\n")
-	}
-	
-	if len(gear.Items) > 0 {
-		content.WriteString("EQUIPMENT:\n")
-		content.WriteString("----------\n")
-		for _, item := range gear.Items {
-			content.WriteString(fmt.Sprintf("• %s\n", item))
-		}
-		content.WriteString("\n")
-	}
-	
-	content.WriteString("Generated by Big Dumb Toolbox RPG Character Creator\n")
-	
-	// Generate unique filename with class and timestamp
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	filename := fmt.Sprintf("%s_Character_%s.txt", className, timestamp)
-	
-	err := os.WriteFile(filename, []byte(content.String()), 0644)
-	return filename, err
+// abilityModifier converts an ability score to its modifier, rounding down.
+func abilityModifier(score int) int {
+	return floorDiv(score-10, 2)
 }
 
-func exportCharacterPDF(className string, character map[string]int, gear StartingGear, gold int) (string, error) {
-	// For PDF export, we'll create an HTML file and suggest using a browser to print to PDF
-	// This is a simple approach that works across all platforms
-	var content strings.Builder
-	
-	content.WriteString(`<!DOCTYPE html>
-<html>
-<head>
-    <title>D&D 5E Character Sheet</title>
-    <style>
-        body { font-family: Arial, sans-serif; margin: 20px; line-height: 1.6; }
-        .header { text-align: center; border-bottom: 2px solid #333; padding-bottom: 10px; margin-bottom: 20px; }
-        .section { margin-bottom: 20px; }
-        .section h3 { color: #333; border-bottom: 1px solid #ccc; padding-bottom: 5px; }
-        .stats { display: grid; grid-template-columns: repeat(2, 1fr); gap: 10px; }
-        .stat { padding: 8px; background: #f5f5f5; border-radius: 4px; }
-        .primary { background: #ffd700; font-weight: bold; }
-        .secondary { background: #c0c0c0; font-weight: bold; }
-        ul { padding-left: 20px; }
-        .footer { margin-top: 30px; text-align: center; color: #666; font-size: 12px; }
-        @media print { body { margin: 0; } }
-    </style>
-</head>
-<body>
-    <div class="header">
-        <h1>D&D 5E CHARACTER SHEET</h1>`)
-	
-	if className != "" {
-		content.WriteString(fmt.Sprintf(`        <h2>%s</h2>`, className))
-	}
-	
-	content.WriteString(`    </div>
-    
-    <div class="section">
-        <h3>Ability Scores</h3>
-        <div class="stats">`)
-	
-	stats := []string{"Strength", "Constitution", "Intelligence", "Wisdom", "Charisma", "Dexterity"}
-	classStats := getClassStats(className)
-	
-	for _, stat := range stats {
-		if value, exists := character[stat]; exists {
-			class := "stat"
-			if stat == classStats.Primary {
-				class = "stat primary"
-			} else if stat == classStats.Secondary {
-				class = "stat secondary"
-			}
-			content.WriteString(fmt.Sprintf(`            <div class="%s">%s: %d</div>`, class, stat, value))
-		}
-	}
-	
-	content.WriteString(`        </div>
-    </div>`)
-	
-	content.WriteString(fmt.Sprintf(`    
-    <div class="section">
-        <h3>Gold</h3>
-        <p><strong>%d gp</strong></p>
-    </div>`, gold))
-	
-	if len(gear.Weapons) > 0 {
-		content.WriteString(`    
-    <div class="section">
-        <h3>Weapons</h3>
-        <ul>`)
-		for _, weapon := range gear.Weapons {
-			content.WriteString(fmt.Sprintf(`            <li>%s</li>`, weapon))
-		}
-		content.WriteString(`        </ul>
-    </div>`)
-	}
-	
-	if len(gear.Armor) > 0 {
-		content.WriteString(`    
-    <div class="section">
-        <h3>Armor</h3>
-        <ul>`)
-		for _, armor := range gear.Armor {
-			content.WriteString(fmt.Sprintf(`            <li>%s</li>`, armor))
-		}
-		content.WriteString(`        </ul>
-    </div>`)
-	}
-	
-	if len(gear.Items) > 0 {
-		content.WriteString(`    
-    <div class="section">
-        <h3>Equipment</h3>
-        <ul>`)
-		for _, item := range gear.Items {
-			content.WriteString(fmt.Sprintf(`            <li>%s</li>`, item))
-		}
-		content.WriteString(`        </ul>
-    </div>`)
-	}
-	
-	content.WriteString(`    
-    <div class="footer">
-        <p>Generated by Big Dumb Toolbox RPG Character Creator</p>
-        <p><em>To convert to PDF: Open this file in your browser and use Print → Save as PDF</em></p>
-    </div>
-</body>
-</html>`)
-	
-	// Generate unique filename with class and timestamp
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	filename := fmt.Sprintf("%s_Character_%s.html", className, timestamp)
-	
-	err := os.WriteFile(filename, []byte(content.String()), 0644)
-	return filename, err
+func proficiencyBonus(level int) int {
+	return 2 + (max(level, 1)-1)/4
 }
 
-func rollStat() int {
-	var rolls []int
-	for i := 0; i < 4; i++ {
-		roll := rand.Intn(6) + 1
-		if roll == 1 {
-			roll = rand.Intn(6) + 1 // Reroll ones
-		}
-		rolls = append(rolls, roll)
+// characterFilePrefix names export files after the character, falling back
+// to the class for unnamed characters.
+func characterFilePrefix(c Character) string {
+	if c.Name == "" {
+		return c.Class
 	}
-	
-	// Sort in descending order and take highest 3
-	for i := 0; i < len(rolls); i++ {
-		for j := i + 1; j < len(rolls); j++ {
-			if rolls[i] < rolls[j] {
-				rolls[i], rolls[j] = rolls[j], rolls[i]
-			}
+	return strings.Map(func(r rune) rune {
+		if r == ' ' {
+			return '_'
 		}
-	}
-	
-	return rolls[0] + rolls[1] + rolls[2]
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(c.Name))
 }
 
-func generateCharacter(className string) map[string]int {
-	stats := []string{"Strength", "Constitution", "Intelligence", "Wisdom", "Charisma", "Dexterity"}
-	
-	// Roll all stats
-	var rolls []int
-	for range stats {
-		rolls = append(rolls, rollStat())
-	}
-	
-	// Sort rolls in descending order to get highest values first
-	for i := 0; i < len(rolls); i++ {
-		for j := i + 1; j < len(rolls); j++ {
-			if rolls[i] < rolls[j] {
-				rolls[i], rolls[j] = rolls[j], rolls[i]
-			}
-		}
+// rerollRPGCharacter makes a new character with the same choices. Rolled
+// methods get fresh scores laid out for the class; the standard array and
+// point buy keep the scores already chosen.
+func (m model) rerollRPGCharacter() model {
+	if isRolledMethod(m.rpgAbilityMethod) {
+		m.rpgScoreValues, m.rpgScoreRolls = m.rpgPack.generateAbilityValues(m.rpgAbilityMethod, m.rpgClasses[m.rpgClassCursor])
 	}
-	
-	// Initialize character map
-	character := make(map[string]int)
-	
-	// If class is selected, prioritize primary and secondary stats
-	if className != "" {
-		classStats := getClassStats(className)
-		
-		// Assign highest roll to primary stat
-		character[classStats.Primary] = rolls[0]
-		
-		// Assign second highest to secondary stat
-		character[classStats.Secondary] = rolls[1]
-		
-		// Assign remaining rolls to other stats
-		rollIndex := 2
-		for _, stat := range stats {
-			if stat != classStats.Primary && stat != classStats.Secondary {
-				character[stat] = rolls[rollIndex]
-				rollIndex++
-			}
-		}
-	} else {
-		// Random assignment if no class selected
-		for i, stat := range stats {
-			character[stat] = rolls[i]
-		}
-	}
-	
-	return character
+	return m.finishRPGCharacter()
 }
 
 func (m model) updateRPGCharacter(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -376,10 +106,7 @@ func (m model) updateRPGCharacter(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !m.rpgRolling {
 				m.rpgRolling = true
 				m.rpgRollTime = time.Now()
-				m.rpgCharacter = generateCharacter(m.rpgSelectedClass)
-				m.rpgGear = getStartingGear(m.rpgSelectedClass)
-				m.rpgGold = generateGold()
-				m.rpgExportStatus = ""
+				m = m.rerollRPGCharacter()
 				
 				return m, tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
 					return t
@@ -387,31 +114,63 @@ func (m model) updateRPGCharacter(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "r":
 			if !m.rpgRolling {
-				m.rpgCharacter = generateCharacter(m.rpgSelectedClass)
-				m.rpgGear = getStartingGear(m.rpgSelectedClass)
-				m.rpgGold = generateGold()
-				m.rpgExportStatus = ""
+				m = m.rerollRPGCharacter()
 			}
+		case "a":
+			// Rearrange the current scores rather than generating new ones
+			m.rpgScoreCursor = 0
+			m.rpgScoreHeld = -1
+			m.state = rpgAbilityScoresView
 		case "b":
-			m.state = rpgClassSelectionView
-		case "s":
-			if len(m.rpgCharacter) > 0 {
-				filename, err := exportCharacterText(m.rpgSelectedClass, m.rpgCharacter, m.rpgGear, m.rpgGold)
+			m.state = rpgRaceSelectionView
+		case "w":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				saved, err := m.saveRPGCharacter()
 				if err != nil {
-					m.rpgExportStatus = "❌ Export failed: " + err.Error()
+					m.rpgExportStatus = "❌ Save failed: " + err.Error()
 				} else {
-					m.rpgExportStatus = "✅ Character saved to " + filename
+					m = saved
+					m.rpgExportStatus = fmt.Sprintf("✅ %s saved to the roster", characterDisplayName(m.rpgCharacter))
 				}
 			}
-		case "p":
-			if len(m.rpgCharacter) > 0 {
-				filename, err := exportCharacterPDF(m.rpgSelectedClass, m.rpgCharacter, m.rpgGear, m.rpgGold)
-				if err != nil {
-					m.rpgExportStatus = "❌ PDF export failed: " + err.Error()
+		case "o":
+			m = m.openRPGRoster()
+		case "g":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				m = m.openRPGEquipment(rpgCharacterView)
+			}
+		case "e":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				m = m.openRPGEditor()
+			}
+		case "n":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				m = m.openRPGPersona()
+			}
+		case "c":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				m = m.openRPGSpells()
+			}
+		case "l":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				if m.rpgCharacter.Level >= maxCharacterLevel {
+					m.rpgExportStatus = fmt.Sprintf("Already at level %d", maxCharacterLevel)
 				} else {
-					m.rpgExportStatus = "✅ Character saved to " + filename + " (open in browser to print as PDF)"
+					m.state = rpgLevelUpView
 				}
 			}
+		case "s":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				m = m.exportRPGCharacter(findExportFormat("txt"))
+			}
+		case "p":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				m = m.exportRPGCharacter(findExportFormat("pdf"))
+			}
+		case "x":
+			if len(m.rpgCharacter.Abilities) > 0 {
+				m = m.openRPGExport()
+			}
 		}
 	case time.Time:
 		if m.rpgRolling && time.Since(m.rpgRollTime) > time.Second*2 {
@@ -455,8 +214,7 @@ func (m model) viewRPGCharacter() string {
 
 	statStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#10B981")).
-		MarginBottom(1)
+		Foreground(lipgloss.Color("#10B981"))
 
 	rollingStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -480,68 +238,164 @@ func (m model) viewRPGCharacter() string {
 		rollingFrames := []string{"🎲", "🎯", "⚡", "🔥", "✨", "🌟"}
 		frame := rollingFrames[int(time.Since(m.rpgRollTime)/time.Millisecond/200)%len(rollingFrames)]
 		characterDisplay = rollingStyle.Render(fmt.Sprintf("Rolling character stats... %s", frame))
-	} else if len(m.rpgCharacter) > 0 {
+	} else if len(m.rpgCharacter.Abilities) > 0 {
 		// Show character stats
 		stats := []string{
 			"Strength", "Constitution", "Intelligence", 
 			"Wisdom", "Charisma", "Dexterity",
 		}
 		
-		var statLines []string
+		derived := m.rpgPack.deriveStats(m.rpgCharacter)
+		classStats := m.rpgPack.getClassStats(m.rpgCharacter.Class)
+		
+		statLines := []string{statStyle.Render(fmt.Sprintf("%-13s %-8s %4s %6s", "Ability", "Score", "Mod", "Save"))}
 		for _, stat := range stats {
-			if value, exists := m.rpgCharacter[stat]; exists {
+			if value, exists := m.rpgCharacter.Abilities[stat]; exists {
+				score := fmt.Sprintf("%d", value)
+				if bonus := m.rpgCharacter.RaceBonus[stat]; bonus > 0 {
+					score += fmt.Sprintf(" (+%d)", bonus)
+				}
+				saveMarker := " "
+				if derived.Saves[stat] != derived.Modifiers[stat] {
+					saveMarker = "●"
+				}
+				line := fmt.Sprintf("%-13s %-8s %4s %5s%s", stat, score, formatModifier(derived.Modifiers[stat]), formatModifier(derived.Saves[stat]), saveMarker)
 				// Highlight primary and secondary stats
-				if m.rpgSelectedClass != "" {
-					classStats := getClassStats(m.rpgSelectedClass)
-					if stat == classStats.Primary {
-						statLines = append(statLines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFD700")).Render(fmt.Sprintf("%-13s: %2d", stat, value)))
-					} else if stat == classStats.Secondary {
-						statLines = append(statLines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#C0C0C0")).Render(fmt.Sprintf("%-13s: %2d", stat, value)))
-					} else {
-						statLines = append(statLines, statStyle.Render(fmt.Sprintf("%-13s: %2d", stat, value)))
-					}
+				if stat == classStats.Primary {
+					statLines = append(statLines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFD700")).Render(line))
+				} else if stat == classStats.Secondary {
+					statLines = append(statLines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#C0C0C0")).Render(line))
 				} else {
-					statLines = append(statLines, statStyle.Render(fmt.Sprintf("%-13s: %2d", stat, value)))
+					statLines = append(statLines, line)
 				}
 			}
 		}
 		
-		classTitle := "🧙 Your Character Stats:"
-		if m.rpgSelectedClass != "" {
-			classTitle = fmt.Sprintf("🧙 %s Character Stats:", m.rpgSelectedClass)
+		classTitle := fmt.Sprintf("🧙 Level %d %s %s • %s", derived.Level, m.rpgCharacter.Race, m.rpgCharacter.Class, m.rpgCharacter.Background)
+		if m.rpgCharacter.Alignment != "" {
+			classTitle += " • " + m.rpgCharacter.Alignment
 		}
+		if m.rpgCharacter.Name != "" {
+			classTitle = statStyle.Render(m.rpgCharacter.Name) + "\n" + classTitle
+		}
+		xpLine := fmt.Sprintf("XP %d", m.rpgCharacter.XP)
+		if next := nextLevelXP(derived.Level); next > 0 {
+			xpLine += fmt.Sprintf(" / %d", next)
+			if m.rpgCharacter.XP >= next {
+				xpLine += " • ⬆ Level up available"
+			}
+		}
+		paragraphStyle := lipgloss.NewStyle().Width(48).AlignHorizontal(lipgloss.Center)
 		
-		// Add gear and gold information
+		// Add combat numbers, gear and gold information
 		var gearDisplay strings.Builder
-		gearDisplay.WriteString(classTitle + "\n\n")
+		gearDisplay.WriteString(classTitle + "\n" + xpLine + "\n\n")
+		gearDisplay.WriteString(statStyle.Render(fmt.Sprintf("HP %d • AC %d • Initiative %s • Speed %d ft", derived.HitPoints, derived.ArmorClass, formatModifier(derived.Initiative), derived.Speed)))
+		gearDisplay.WriteString(fmt.Sprintf("\nProficiency %s • Passive Perception %d\nAC from %s\n\n", formatModifier(derived.Proficiency), derived.PassivePerception, derived.ArmorSource))
 		gearDisplay.WriteString(strings.Join(statLines, "\n"))
+		gearDisplay.WriteString("\n● proficient saving throw")
 		
-		if m.rpgGold > 0 {
-			gearDisplay.WriteString(fmt.Sprintf("\n\n💰 Gold: %d gp", m.rpgGold))
+		if len(derived.SkillProficiencies) > 0 {
+			gearDisplay.WriteString("\n\n🎯 Skills:\n")
+			gearDisplay.WriteString(paragraphStyle.Render(formatSkillList(derived, derived.SkillProficiencies)))
 		}
 		
-		if len(m.rpgGear.Weapons) > 0 {
-			gearDisplay.WriteString("\n\n⚔️  Weapons:")
-			for _, weapon := range m.rpgGear.Weapons {
-				gearDisplay.WriteString(fmt.Sprintf("\n  • %s", weapon))
+		if len(derived.Attacks) > 0 {
+			gearDisplay.WriteString("\n\n⚔️  Attacks:")
+			for _, attack := range derived.Attacks {
+				gearDisplay.WriteString(fmt.Sprintf("\n%-15s %3s to hit  %-6s %-11s", attack.Name, formatModifier(attack.AttackBonus), attack.Damage, attack.DamageType))
 			}
 		}
 		
-		if len(m.rpgGear.Armor) > 0 {
-			gearDisplay.WriteString("\n\n🛡️  Armor:")
-			for _, armor := range m.rpgGear.Armor {
-				gearDisplay.WriteString(fmt.Sprintf("\n  • %s", armor))
+		if ability, saveDC, attack := m.rpgPack.spellcastingNumbers(m.rpgCharacter); ability != "" {
+			gearDisplay.WriteString(fmt.Sprintf("\n\n✨ Spells: %s • Save DC %d • Attack %s", ability, saveDC, formatModifier(attack)))
+			if slots := m.rpgPack.formatSpellSlots(m.rpgCharacter); slots != "" {
+				gearDisplay.WriteString("\nSlots: " + slots)
+			}
+			if len(m.rpgCharacter.Cantrips) > 0 {
+				gearDisplay.WriteString("\nCantrips:\n" + paragraphStyle.Render(strings.Join(m.rpgCharacter.Cantrips, ", ")))
+			}
+			if len(m.rpgCharacter.Spells) > 0 {
+				spells := append([]string{}, m.rpgCharacter.Spells...)
+				for i, spell := range spells {
+					if indexOf(m.rpgCharacter.Prepared, spell) >= 0 {
+						spells[i] += " ◆"
+					}
+				}
+				label := "Spells"
+				if _, _, prepared := m.rpgPack.spellLimits(m.rpgCharacter); prepared > 0 {
+					label = "Spellbook (◆ prepared)"
+				}
+				gearDisplay.WriteString("\n" + label + ":\n" + paragraphStyle.Render(strings.Join(spells, ", ")))
+			}
+			if open := m.rpgPack.spellsToChoose(m.rpgCharacter); open > 0 {
+				gearDisplay.WriteString(fmt.Sprintf("\n%d still to choose • C to choose", open))
 			}
 		}
 		
-		if len(m.rpgGear.Items) > 0 {
-			gearDisplay.WriteString("\n\n🎒 Equipment:")
-			for _, item := range m.rpgGear.Items {
-				gearDisplay.WriteString(fmt.Sprintf("\n  • %s", item))
+		if m.rpgCharacter.Wealth != "" {
+			gearDisplay.WriteString(fmt.Sprintf("\n\n💰 Gold: %d gp from starting wealth\n%s", m.rpgCharacter.Gold, m.rpgCharacter.Wealth))
+		} else if m.rpgCharacter.Gold > 0 {
+			gearDisplay.WriteString(fmt.Sprintf("\n\n💰 Gold: %d gp", m.rpgCharacter.Gold))
+		}
+		
+		if len(m.rpgCharacter.Gear.Weapons) > 0 {
+			gearDisplay.WriteString("\n\n🗡️  Weapons:\n")
+			gearDisplay.WriteString(paragraphStyle.Render(strings.Join(m.rpgCharacter.Gear.Weapons, ", ")))
+		}
+		
+		if len(m.rpgCharacter.Gear.Armor) > 0 {
+			gearDisplay.WriteString("\n\n🛡️  Armor: " + strings.Join(m.rpgCharacter.Gear.Armor, ", "))
+		}
+		
+		if len(m.rpgCharacter.Gear.Items) > 0 {
+			gearDisplay.WriteString("\n\n🎒 Equipment:\n")
+			gearDisplay.WriteString(paragraphStyle.Render(strings.Join(m.rpgCharacter.Gear.Items, ", ")))
+		}
+		
+		if m.rpgCharacter.Backstory != "" {
+			gearDisplay.WriteString("\n\n📖 Backstory:\n")
+			gearDisplay.WriteString(paragraphStyle.Render(m.rpgCharacter.Backstory))
+		}
+		
+		if m.rpgCharacter.Notes != "" {
+			gearDisplay.WriteString("\n\n📝 Notes:\n")
+			gearDisplay.WriteString(paragraphStyle.Render(m.rpgCharacter.Notes))
+		}
+		
+		// Race and background details beside the stats
+		race := m.rpgPack.getRaceStats(m.rpgCharacter.Race)
+		background := m.rpgPack.getBackgroundStats(m.rpgCharacter.Background)
+		var origins strings.Builder
+		origins.WriteString(fmt.Sprintf("🧬 %s\n\n", m.rpgCharacter.Race))
+		origins.WriteString(fmt.Sprintf("Size: %s\nSpeed: %d ft\nLanguages: %s\n\nTraits:", race.Size, race.Speed, strings.Join(race.Languages, ", ")))
+		for _, trait := range race.Traits {
+			origins.WriteString("\n  • " + trait)
+		}
+		origins.WriteString(fmt.Sprintf("\n\n📜 %s\n\nSkills: %s", m.rpgCharacter.Background, strings.Join(background.Skills, ", ")))
+		if len(background.Tools) > 0 {
+			origins.WriteString("\nTools: " + strings.Join(background.Tools, ", "))
+		}
+		if background.Languages > 0 {
+			origins.WriteString(fmt.Sprintf("\nLanguages: %d of your choice", background.Languages))
+		}
+		origins.WriteString("\nFeature: " + background.Feature)
+		if len(m.rpgCharacter.Traits) > 0 {
+			origins.WriteString("\n\n🎭 Personality")
+			for _, trait := range m.rpgCharacter.Traits {
+				origins.WriteString("\n  • " + trait)
 			}
+			origins.WriteString(fmt.Sprintf("\nIdeal: %s\nBond: %s\nFlaw: %s", m.rpgCharacter.Ideal, m.rpgCharacter.Bond, m.rpgCharacter.Flaw))
 		}
 		
-		characterDisplay = characterStyle.Render(gearDisplay.String())
+		originsStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#10B981")).
+			Padding(1, 2).
+			MarginBottom(2).
+			Width(36)
+		
+		characterDisplay = lipgloss.JoinHorizontal(lipgloss.Top, characterStyle.Render(gearDisplay.String()), "  ", originsStyle.Render(origins.String()))
 	} else {
 		// Show initial state
 		characterDisplay = characterStyle.Render("🧙 Ready to create your character!\n\nPress Enter to roll stats")
@@ -550,11 +404,11 @@ func (m model) viewRPGCharacter() string {
 	// Help text
 	var helpText string
 	if m.rpgRolling {
-		helpText = "Rolling stats using 4d6, reroll 1s, take highest 3..."
-	} else if len(m.rpgCharacter) > 0 {
-		helpText = "Enter/R to reroll • B to change class • S to save as text • P to save as HTML • ESC to go back"
+		helpText = fmt.Sprintf("Generating stats using %s...", abilityMethodNames[m.rpgAbilityMethod])
+	} else if len(m.rpgCharacter.Abilities) > 0 {
+		helpText = "Enter/R to reroll • A to arrange scores • B to change race, class or background • G to change equipment • E to edit • N for name and backstory • C for spells • L to level up • W to save to roster • O for saved characters • S to save as text • P to save as PDF • X for more formats • ESC to go back"
 	} else {
-		helpText = "Enter to roll character • B to change class • ESC to go back"
+		helpText = "Enter to roll character • B to change race, class or background • O for saved characters • ESC to go back"
 	}
 	help := helpStyle.Render(helpText)
 	
@@ -576,70 +430,21 @@ func (m model) viewRPGCharacter() string {
 }
 
 func (m model) viewRPGClassSelection() string {
-	// Define styles
-	containerStyle := lipgloss.NewStyle().
-		Width(m.width).
-		Height(m.height).
-		AlignHorizontal(lipgloss.Center).
-		AlignVertical(lipgloss.Center)
-	
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#8B5CF6")).
-		Padding(1, 2).
-		MarginBottom(2).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#8B5CF6")).
-		Width(50).
-		AlignHorizontal(lipgloss.Center)
-
-	classMenuStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#8B5CF6")).
-		Padding(1, 2).
-		MarginBottom(2).
-		Width(50)
-
-	selectedClassStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#10B981")).
-		Padding(0, 1)
-
-	normalClassStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#8B5CF6")).
-		Padding(0, 1)
-
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#626262")).
-		Italic(true).
-		AlignHorizontal(lipgloss.Center).
-		Width(50)
-
-	// Build content
-	title := titleStyle.Render("⚔️  Choose Your Class")
+	className := m.rpgClasses[m.rpgClassCursor]
+	classStats := m.rpgPack.getClassStats(className)
+	choices, wealthDice := m.rpgPack.getClassEquipment(className)
 	
-	// Class selection menu
-	var classOptions []string
-	for i, class := range m.rpgClasses {
-		var style lipgloss.Style
-		cursor := "  "
-		if m.rpgClassCursor == i {
-			cursor = "▶ "
-			style = selectedClassStyle
-		} else {
-			style = normalClassStyle
-		}
-		
-		classOptions = append(classOptions, style.Render(cursor+class))
+	detail := fmt.Sprintf("Primary ability: %s\nSecondary ability: %s\nHit die: d%d", classStats.Primary, classStats.Secondary, classStats.HitDie)
+	if casting := classStats.Spellcasting; casting != nil {
+		detail += fmt.Sprintf("\nSpellcasting: %s", casting.Ability)
+	}
+	detail += "\n\nEquipment:"
+	for _, choice := range choices {
+		detail += "\n  • " + describeEquipmentChoice(choice)
+	}
+	if wealthDice != "" {
+		detail += fmt.Sprintf("\n\nOr starting wealth: %s gp", wealthDice)
 	}
 	
-	classMenu := classMenuStyle.Render("Select your character class:\n\n" + strings.Join(classOptions, "\n"))
-	
-	help := helpStyle.Render("Use ↑/↓ or j/k to navigate • Enter to select • ESC to go back • Ctrl+C to quit")
-	
-	content := lipgloss.JoinVertical(lipgloss.Center, title, classMenu, help)
-	
-	return containerStyle.Render(content)
-}
\ No newline at end of file
+	return m.viewRPGWizardStep(2, "Choose Your Class", m.rpgClasses, m.rpgClassCursor, detail, "ESC to change race")
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Ability score generation methods, in the order they are offered.
+const (
+	abilityMethod4d6 = iota
+	abilityMethod4d6RerollOnes
+	abilityMethod3d6InOrder
+	abilityMethodStandardArray
+	abilityMethodPointBuy
+)
+
+var abilityMethodNames = []string{"4d6 drop lowest", "4d6 reroll 1s", "3d6 in order", "Standard array", "Point buy"}
+
+// Dice expressions for the rolled methods.
+var abilityMethodDice = map[int]string{
+	abilityMethod4d6:           "4d6dl1",
+	abilityMethod4d6RerollOnes: "4d6r1dl1",
+	abilityMethod3d6InOrder:    "3d6",
+}
+
+var standardArray = []int{15, 14, 13, 12, 10, 8}
+
+// Point buy: every score starts at 8 and may be raised to 15.
+const pointBuyBudget = 27
+
+var pointBuyCost = map[int]int{8: 0, 9: 1, 10: 2, 11: 3, 12: 4, 13: 5, 14: 7, 15: 9}
+
+func abilityMethodDescription(method int) string {
+	switch method {
+	case abilityMethod4d6:
+		return "Roll 4d6 six times, dropping the lowest die each time, then arrange the results as you like."
+	case abilityMethod4d6RerollOnes:
+		return "Like 4d6 drop lowest, but any 1 is rerolled once first. A generous house rule."
+	case abilityMethod3d6InOrder:
+		return "Roll 3d6 for each ability straight down the line: Strength, Dexterity, Constitution, Intelligence, Wisdom, Charisma. No rearranging."
+	case abilityMethodStandardArray:
+		return "Arrange 15, 14, 13, 12, 10 and 8 among the six abilities."
+	case abilityMethodPointBuy:
+		return fmt.Sprintf("Every score starts at 8. Spend %d points to raise scores up to 15; 14 and 15 cost extra.", pointBuyBudget)
+	}
+	return ""
+}
+
+// isRolledMethod reports whether a method uses dice, so a reroll makes sense.
+func isRolledMethod(method int) bool {
+	_, ok := abilityMethodDice[method]
+	return ok
+}
+
+// rollAbilityValues rolls six scores with the method's dice expression.
+func rollAbilityValues(method int) ([]int, []DiceRollResult) {
+	var values []int
+	var rolls []DiceRollResult
+	for range abilityNames {
+		result, err := rollDiceExpression(abilityMethodDice[method])
+		if err != nil {
+			result = DiceRollResult{Total: 10}
+		}
+		values = append(values, result.Total)
+		rolls = append(rolls, result)
+	}
+	return values, rolls
+}
+
+// classAbilityOrder lists the abilities with the class's primary and
+// secondary first, then the rest in sheet order.
+func (pack ContentPack) classAbilityOrder(className string) []int {
+	classStats := pack.getClassStats(className)
+	rank := func(ability string) int {
+		switch ability {
+		case classStats.Primary:
+			return 2
+		case classStats.Secondary:
+			return 1
+		}
+		return 0
+	}
+	order := []int{0, 1, 2, 3, 4, 5}
+	sort.SliceStable(order, func(i, j int) bool {
+		return rank(abilityNames[order[i]]) > rank(abilityNames[order[j]])
+	})
+	return order
+}
+
+// assignByClass puts the highest values on the class's primary and secondary
+// abilities and the rest in sheet order. Rolls, if any, follow their values.
+func (pack ContentPack) assignByClass(className string, values []int, rolls []DiceRollResult) ([]int, []DiceRollResult) {
+	sorted := make([]int, len(values))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return values[sorted[i]] > values[sorted[j]]
+	})
+
+	assigned := make([]int, len(values))
+	var assignedRolls []DiceRollResult
+	if rolls != nil {
+		assignedRolls = make([]DiceRollResult, len(rolls))
+	}
+	for rank, ability := range pack.classAbilityOrder(className) {
+		assigned[ability] = values[sorted[rank]]
+		if rolls != nil {
+			assignedRolls[ability] = rolls[sorted[rank]]
+		}
+	}
+	return assigned, assignedRolls
+}
+
+// pointBuySpent totals the cost of a set of point buy scores.
+func pointBuySpent(values []int) int {
+	spent := 0
+	for _, value := range values {
+		spent += pointBuyCost[value]
+	}
+	return spent
+}
+
+// generateAbilityValues produces a fresh set of base scores for a method, laid
+// out for the class the way the old auto-assignment did.
+func (pack ContentPack) generateAbilityValues(method int, className string) ([]int, []DiceRollResult) {
+	switch method {
+	case abilityMethodStandardArray:
+		values, _ := pack.assignByClass(className, standardArray, nil)
+		return values, nil
+	case abilityMethodPointBuy:
+		// Buying the standard array spends exactly 27 points
+		values, _ := pack.assignByClass(className, standardArray, nil)
+		return values, nil
+	case abilityMethod3d6InOrder:
+		return rollAbilityValues(method)
+	}
+	values, rolls := rollAbilityValues(method)
+	return pack.assignByClass(className, values, rolls)
+}
+
+// abilityScoreMap turns base scores in sheet order into a map by ability.
+func abilityScoreMap(values []int) map[string]int {
+	scores := make(map[string]int)
+	for i, ability := range abilityNames {
+		scores[ability] = values[i]
+	}
+	return scores
+}
+
+// startRPGAbilityScores opens the score screen with fresh values for the
+// selected method.
+func (m model) startRPGAbilityScores() model {
+	m.rpgScoreValues, m.rpgScoreRolls = m.rpgPack.generateAbilityValues(m.rpgAbilityMethod, m.rpgClasses[m.rpgClassCursor])
+	m.rpgScoreCursor = 0
+	m.rpgScoreHeld = -1
+	m.state = rpgAbilityScoresView
+	return m
+}
+
+// finishRPGCharacter builds the character from the wizard's choices and the
+// assigned scores. A character being reworked keeps its roster entry, name,
+// alignment and notes, and its level and spells too if the class is
+// unchanged.
+func (m model) finishRPGCharacter() model {
+	previous := m.rpgCharacter
+	m.rpgCharacter = m.rpgPack.buildCharacter(m.rpgRaces[m.rpgRaceCursor], m.rpgClasses[m.rpgClassCursor], m.rpgBackgrounds[m.rpgBackgroundCursor], abilityScoreMap(m.rpgScoreValues), m.rpgEquipment)
+	m.rpgCharacter.Method = abilityMethodNames[m.rpgAbilityMethod]
+	m.rpgCharacter.ID = previous.ID
+	m.rpgCharacter.Name = previous.Name
+	m.rpgCharacter.Alignment = previous.Alignment
+	m.rpgCharacter.Notes = previous.Notes
+	if m.rpgCharacter.Name == "" {
+		m.rpgCharacter.Name = m.rpgPack.generateCharacterName(m.rpgCharacter.Race)
+	}
+	if previous.Background == m.rpgCharacter.Background && previous.Backstory != "" {
+		m.rpgCharacter.Traits, m.rpgCharacter.Ideal, m.rpgCharacter.Bond = previous.Traits, previous.Ideal, previous.Bond
+		m.rpgCharacter.Flaw, m.rpgCharacter.Backstory = previous.Flaw, previous.Backstory
+	} else {
+		m.rpgCharacter = m.rpgPack.rollPersonality(m.rpgCharacter)
+	}
+	if previous.Class == m.rpgCharacter.Class && previous.Level > 1 {
+		m.rpgCharacter.Level = previous.Level
+		m.rpgCharacter.XP = previous.XP
+		m.rpgCharacter.HitDieRolls = previous.HitDieRolls
+	}
+	if previous.Class == m.rpgCharacter.Class {
+		m.rpgCharacter.Cantrips, m.rpgCharacter.Spells = previous.Cantrips, previous.Spells
+		m.rpgCharacter.Prepared, m.rpgCharacter.SlotsUsed = previous.Prepared, previous.SlotsUsed
+		m.rpgCharacter = m.rpgPack.chooseSpells(m.rpgCharacter)
+	}
+	m.rpgExportStatus = ""
+	m.state = rpgCharacterView
+	return m
+}
+
+func (m model) updateRPGAbilityMethod(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.state = rpgEquipmentView
+		case "up", "k":
+			if m.rpgAbilityMethod > 0 {
+				m.rpgAbilityMethod--
+			}
+		case "down", "j":
+			if m.rpgAbilityMethod < len(abilityMethodNames)-1 {
+				m.rpgAbilityMethod++
+			}
+		case "enter", " ":
+			return m.startRPGAbilityScores(), nil
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateRPGAbilityScores(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	// Scores rolled in order stay where they landed
+	canArrange := m.rpgAbilityMethod != abilityMethod3d6InOrder && m.rpgAbilityMethod != abilityMethodPointBuy
+
+	swap := func(i, j int) {
+		m.rpgScoreValues[i], m.rpgScoreValues[j] = m.rpgScoreValues[j], m.rpgScoreValues[i]
+		if m.rpgScoreRolls != nil {
+			m.rpgScoreRolls[i], m.rpgScoreRolls[j] = m.rpgScoreRolls[j], m.rpgScoreRolls[i]
+		}
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = rpgAbilityMethodView
+	case "up", "k":
+		if m.rpgScoreCursor > 0 {
+			m.rpgScoreCursor--
+		}
+	case "down", "j":
+		if m.rpgScoreCursor < len(abilityNames)-1 {
+			m.rpgScoreCursor++
+		}
+	case "shift+up", "K":
+		if canArrange && m.rpgScoreCursor > 0 {
+			swap(m.rpgScoreCursor, m.rpgScoreCursor-1)
+			m.rpgScoreCursor--
+		}
+	case "shift+down", "J":
+		if canArrange && m.rpgScoreCursor < len(abilityNames)-1 {
+			swap(m.rpgScoreCursor, m.rpgScoreCursor+1)
+			m.rpgScoreCursor++
+		}
+	case " ":
+		// Pick a score up, then drop it on another ability to swap them
+		if canArrange {
+			if m.rpgScoreHeld < 0 {
+				m.rpgScoreHeld = m.rpgScoreCursor
+			} else {
+				swap(m.rpgScoreHeld, m.rpgScoreCursor)
+				m.rpgScoreHeld = -1
+			}
+		}
+	case "right", "l", "+", "=":
+		if m.rpgAbilityMethod == abilityMethodPointBuy {
+			value := m.rpgScoreValues[m.rpgScoreCursor]
+			if value < 15 && pointBuySpent(m.rpgScoreValues)-pointBuyCost[value]+pointBuyCost[value+1] <= pointBuyBudget {
+				m.rpgScoreValues[m.rpgScoreCursor]++
+			}
+		}
+	case "left", "h", "-":
+		if m.rpgAbilityMethod == abilityMethodPointBuy && m.rpgScoreValues[m.rpgScoreCursor] > 8 {
+			m.rpgScoreValues[m.rpgScoreCursor]--
+		}
+	case "0":
+		if m.rpgAbilityMethod == abilityMethodPointBuy {
+			m.rpgScoreValues = []int{8, 8, 8, 8, 8, 8}
+		}
+	case "a":
+		if canArrange || m.rpgAbilityMethod == abilityMethodPointBuy {
+			m.rpgScoreValues, m.rpgScoreRolls = m.rpgPack.assignByClass(m.rpgClasses[m.rpgClassCursor], m.rpgScoreValues, m.rpgScoreRolls)
+			m.rpgScoreHeld = -1
+		}
+	case "r":
+		if isRolledMethod(m.rpgAbilityMethod) {
+			cursor := m.rpgScoreCursor
+			m = m.startRPGAbilityScores()
+			m.rpgScoreCursor = cursor
+		}
+	case "enter":
+		m = m.finishRPGCharacter()
+		if cantrips, spells, _ := m.rpgPack.spellLimits(m.rpgCharacter); cantrips+spells > 0 {
+			// Casters go on to look over the spells picked for them
+			m = m.openRPGSpells()
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewRPGAbilityMethod() string {
+	detail := abilityMethodDescription(m.rpgAbilityMethod)
+	if expression, ok := abilityMethodDice[m.rpgAbilityMethod]; ok {
+		detail += "\n\nDice: " + expression
+	}
+	return m.viewRPGWizardStep(5, "Choose How to Generate Ability Scores", abilityMethodNames, m.rpgAbilityMethod, detail, "ESC back")
+}
+
+func (m model) viewRPGAbilityScores() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(78).
+		AlignHorizontal(lipgloss.Center)
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(78)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981"))
+
+	heldStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#1A1A1A")).
+		Background(lipgloss.Color("#FFD700"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(78)
+
+	title := titleStyle.Render("⚔️  " + abilityMethodNames[m.rpgAbilityMethod])
+
+	// Preview the racial increases on top of the current scores
+	className := m.rpgClasses[m.rpgClassCursor]
+	base := abilityScoreMap(m.rpgScoreValues)
+	bonus := m.rpgPack.raceAbilityBonus(m.rpgRaces[m.rpgRaceCursor], className, base)
+	final := applyRaceBonus(base, bonus)
+	classStats := m.rpgPack.getClassStats(className)
+
+	notesHeading := ""
+	if m.rpgScoreRolls != nil {
+		notesHeading = "Roll"
+	} else if m.rpgAbilityMethod == abilityMethodPointBuy {
+		notesHeading = "Cost"
+	}
+	rows := []string{lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("  %-13s %4s %5s %6s %4s   %s", "Ability", "Base", "Race", "Final", "Mod", notesHeading))}
+	for i, ability := range abilityNames {
+		race := ""
+		if bonus[ability] > 0 {
+			race = fmt.Sprintf("+%d", bonus[ability])
+		}
+		note := ""
+		switch {
+		case m.rpgScoreRolls != nil:
+			note = formatDiceRollPlain(m.rpgScoreRolls[i])
+		case m.rpgAbilityMethod == abilityMethodPointBuy:
+			note = fmt.Sprint(pointBuyCost[m.rpgScoreValues[i]])
+		}
+		if ability == classStats.Primary {
+			note += " (primary)"
+		} else if ability == classStats.Secondary {
+			note += " (secondary)"
+		}
+		row := fmt.Sprintf("%-13s %4d %5s %6d %4s   %s", ability, m.rpgScoreValues[i], race, final[ability], formatModifier(abilityModifier(final[ability])), strings.TrimSpace(note))
+		row = fmt.Sprintf("%-70s", row)
+		switch {
+		case i == m.rpgScoreHeld:
+			rows = append(rows, heldStyle.Render("✋ "+row))
+		case i == m.rpgScoreCursor:
+			rows = append(rows, selectedStyle.Render("▶ "+row))
+		default:
+			rows = append(rows, "  "+row)
+		}
+	}
+
+	elements := []string{title, m.viewRPGWizardBreadcrumb(5), panelStyle.Render(strings.Join(rows, "\n"))}
+
+	var help string
+	switch m.rpgAbilityMethod {
+	case abilityMethodPointBuy:
+		spent := pointBuySpent(m.rpgScoreValues)
+		budget := fmt.Sprintf("Points: %d of %d spent • %d left", spent, pointBuyBudget, pointBuyBudget-spent)
+		budgetStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#10B981"))
+		if spent < pointBuyBudget {
+			budgetStyle = budgetStyle.Foreground(lipgloss.Color("#F59E0B"))
+		}
+		elements = append(elements, budgetStyle.MarginBottom(1).Render(budget))
+		help = "↑/↓ select • ←/→ adjust • A auto • 0 reset • Enter accept • ESC back"
+	case abilityMethod3d6InOrder:
+		help = "R to reroll • Enter accept • ESC back"
+	case abilityMethodStandardArray:
+		help = "↑/↓ select • Space swap • Shift+↑/↓ move • A auto • Enter accept • ESC back"
+	default:
+		help = "↑/↓ select • Space swap • Shift+↑/↓ move • A auto • R reroll • Enter accept • ESC back"
+	}
+	elements = append(elements, helpStyle.Render(help))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
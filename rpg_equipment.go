@@ -0,0 +1,413 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Ammunition is listed with weapons on the sheet.
+var ammunitionNames = []string{"Arrows", "Crossbow bolts", "Sling bullets", "Blowgun needles"}
+
+func (pack ContentPack) getClassEquipment(className string) ([]EquipmentChoice, string) {
+	for _, class := range pack.Classes {
+		if class.Name == className {
+			return class.Equipment, class.StartingWealth
+		}
+	}
+	return nil, ""
+}
+
+func (pack ContentPack) getEquipmentPack(packName string) ([]string, bool) {
+	for _, equipment := range pack.EquipmentPacks {
+		if equipment.Name == packName {
+			return equipment.Contents, true
+		}
+	}
+	return nil, false
+}
+
+// equipmentPlaceholder returns the kind of weapon a slot such as
+// "{martial melee}" asks for.
+func equipmentPlaceholder(item string) (string, bool) {
+	if strings.HasPrefix(item, "{") && strings.HasSuffix(item, "}") {
+		return strings.TrimSpace(item[1 : len(item)-1]), true
+	}
+	return "", false
+}
+
+// weaponsOfKind lists the weapons of a kind: "simple" or "martial",
+// optionally followed by "melee" or "ranged".
+func weaponsOfKind(weapons []PackWeapon, kind string) []string {
+	parts := strings.Fields(kind)
+	var names []string
+	for _, weapon := range weapons {
+		if len(parts) == 0 || weapon.Category != parts[0] {
+			continue
+		}
+		if len(parts) > 1 && weapon.Ranged != (parts[1] == "ranged") {
+			continue
+		}
+		names = append(names, weapon.Name)
+	}
+	return names
+}
+
+func describeEquipmentOption(option []string) string {
+	items := make([]string, len(option))
+	for i, item := range option {
+		if kind, ok := equipmentPlaceholder(item); ok {
+			item = "any " + kind + " weapon"
+		}
+		items[i] = item
+	}
+	return strings.Join(items, ", ")
+}
+
+// describeEquipmentChoice reads like the rulebook: "(a) Greataxe or (b) any
+// martial melee weapon".
+func describeEquipmentChoice(choice EquipmentChoice) string {
+	if len(choice) == 1 {
+		return describeEquipmentOption(choice[0])
+	}
+	var options []string
+	for i, option := range choice {
+		options = append(options, fmt.Sprintf("(%c) %s", 'a'+i, describeEquipmentOption(option)))
+	}
+	return strings.Join(options, " or ")
+}
+
+// equipmentSlots lists the kinds of weapon the chosen options leave to the
+// player, in order.
+func equipmentSlots(choices []EquipmentChoice, options []int) []string {
+	var slots []string
+	for i, choice := range choices {
+		for _, item := range choice[options[i]] {
+			if kind, ok := equipmentPlaceholder(item); ok {
+				slots = append(slots, kind)
+			}
+		}
+	}
+	return slots
+}
+
+// fitEquipmentSelection makes a selection match a class's equipment: options
+// start over if the lines differ, and weapon slots that are empty or no
+// longer fit take the first weapon of their kind.
+func (pack ContentPack) fitEquipmentSelection(choices []EquipmentChoice, selection EquipmentSelection) EquipmentSelection {
+	if len(selection.Options) != len(choices) {
+		selection.Options = make([]int, len(choices))
+	}
+	for i := range choices {
+		if selection.Options[i] >= len(choices[i]) {
+			selection.Options[i] = 0
+		}
+	}
+
+	slots := equipmentSlots(choices, selection.Options)
+	weapons := make([]string, len(slots))
+	for i, kind := range slots {
+		options := weaponsOfKind(pack.Weapons, kind)
+		if i < len(selection.Weapons) && indexOf(options, selection.Weapons[i]) >= 0 {
+			weapons[i] = selection.Weapons[i]
+		} else if len(options) > 0 {
+			weapons[i] = options[0]
+		}
+	}
+	selection.Weapons = weapons
+	return selection
+}
+
+// addGearItem files an item under weapons, armor or items, unpacking
+// equipment packs into their contents.
+func (pack ContentPack) addGearItem(gear *StartingGear, item string) {
+	name := gearBaseName(item)
+	if _, ok := pack.getWeaponStats(name); ok || indexOf(ammunitionNames, name) >= 0 {
+		gear.Weapons = append(gear.Weapons, item)
+	} else if _, ok := pack.getArmorStats(name); ok || name == "Shield" {
+		gear.Armor = append(gear.Armor, item)
+	} else if contents, ok := pack.getEquipmentPack(name); ok {
+		gear.Items = append(gear.Items, contents...)
+	} else {
+		gear.Items = append(gear.Items, item)
+	}
+}
+
+// equipCharacter gives a character their class equipment as chosen plus
+// their background's equipment and gold, or rolls starting wealth in place
+// of both. The choices are recorded on the character.
+func (pack ContentPack) equipCharacter(c Character, selection EquipmentSelection) Character {
+	choices, wealthDice := pack.getClassEquipment(c.Class)
+	selection = pack.fitEquipmentSelection(choices, selection)
+	c.Gear = StartingGear{Weapons: []string{}, Armor: []string{}, Items: []string{}}
+	c.Equipment = nil
+	c.Wealth = ""
+
+	if selection.Wealth && wealthDice != "" {
+		result, err := rollDiceExpression(wealthDice)
+		if err == nil {
+			c.Gold = result.Total
+			c.Wealth = fmt.Sprintf("%s (%s) = %d gp", wealthDice, formatDiceRollPlain(result), result.Total)
+			return c
+		}
+	}
+
+	slot := 0
+	for i, choice := range choices {
+		option := choice[selection.Options[i]]
+		var picked []string
+		decided := len(choice) > 1
+		for _, item := range option {
+			if _, ok := equipmentPlaceholder(item); ok {
+				item = selection.Weapons[slot]
+				slot++
+				decided = true
+			}
+			picked = append(picked, item)
+			pack.addGearItem(&c.Gear, item)
+		}
+		if decided {
+			record := strings.Join(picked, ", ")
+			if len(choice) > 1 {
+				record = fmt.Sprintf("(%c) %s", 'a'+selection.Options[i], record)
+			}
+			c.Equipment = append(c.Equipment, record)
+		}
+	}
+
+	background := pack.getBackgroundStats(c.Background)
+	for _, item := range background.Equipment {
+		pack.addGearItem(&c.Gear, item)
+	}
+	c.Gold = background.Gold
+	return c
+}
+
+// equipmentRow is a line on the equipment screen the cursor can land on.
+type equipmentRow struct {
+	line int // index into the class's equipment, or -1 for starting wealth
+	slot int // index into the selection's weapons, or -1
+}
+
+func equipmentRows(choices []EquipmentChoice, selection EquipmentSelection, wealthDice string) []equipmentRow {
+	var rows []equipmentRow
+	if wealthDice != "" {
+		rows = append(rows, equipmentRow{line: -1, slot: -1})
+		if selection.Wealth {
+			return rows
+		}
+	}
+	slot := 0
+	for i, choice := range choices {
+		if len(choice) > 1 {
+			rows = append(rows, equipmentRow{line: i, slot: -1})
+		}
+		for _, item := range choice[selection.Options[i]] {
+			if _, ok := equipmentPlaceholder(item); ok {
+				rows = append(rows, equipmentRow{line: i, slot: slot})
+				slot++
+			}
+		}
+	}
+	return rows
+}
+
+// openRPGEquipment shows the equipment step, going back to from on ESC.
+func (m model) openRPGEquipment(from sessionState) model {
+	choices, _ := m.rpgPack.getClassEquipment(m.rpgClasses[m.rpgClassCursor])
+	m.rpgEquipment = m.rpgPack.fitEquipmentSelection(choices, m.rpgEquipment)
+	m.rpgEquipmentCursor = 0
+	m.rpgEquipmentReturn = from
+	m.state = rpgEquipmentView
+	return m
+}
+
+func (m model) updateRPGEquipment(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	choices, wealthDice := m.rpgPack.getClassEquipment(m.rpgClasses[m.rpgClassCursor])
+	rows := equipmentRows(choices, m.rpgEquipment, wealthDice)
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = m.rpgEquipmentReturn
+	case "up", "k":
+		if m.rpgEquipmentCursor > 0 {
+			m.rpgEquipmentCursor--
+		}
+	case "down", "j":
+		if m.rpgEquipmentCursor < len(rows)-1 {
+			m.rpgEquipmentCursor++
+		}
+	case "w":
+		if wealthDice != "" {
+			m.rpgEquipment.Wealth = !m.rpgEquipment.Wealth
+			m.rpgEquipmentCursor = 0
+		}
+	case "left", "right", "h", "l":
+		if len(rows) == 0 {
+			return m, nil
+		}
+		step := 1
+		if keyMsg.String() == "left" || keyMsg.String() == "h" {
+			step = -1
+		}
+		row := rows[m.rpgEquipmentCursor]
+		switch {
+		case row.line < 0:
+			m.rpgEquipment.Wealth = !m.rpgEquipment.Wealth
+		case row.slot < 0:
+			count := len(choices[row.line])
+			m.rpgEquipment.Options[row.line] = (m.rpgEquipment.Options[row.line] + step + count) % count
+		default:
+			kind := equipmentSlots(choices, m.rpgEquipment.Options)[row.slot]
+			weapons := weaponsOfKind(m.rpgPack.Weapons, kind)
+			current := indexOf(weapons, m.rpgEquipment.Weapons[row.slot])
+			m.rpgEquipment.Weapons[row.slot] = weapons[(current+step+len(weapons))%len(weapons)]
+		}
+		m.rpgEquipment = m.rpgPack.fitEquipmentSelection(choices, m.rpgEquipment)
+	case "enter", " ":
+		if m.rpgEquipmentReturn == rpgCharacterView {
+			m.rpgCharacter = m.rpgPack.equipCharacter(m.rpgCharacter, m.rpgEquipment)
+			m.state = rpgCharacterView
+			m = m.autosaveRPGCharacter("✅ Equipment updated")
+			return m, nil
+		}
+		m.state = rpgAbilityMethodView
+	}
+	return m, nil
+}
+
+func (m model) viewRPGEquipment() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(78).
+		AlignHorizontal(lipgloss.Center)
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(78)
+
+	previewStyle := panelStyle.BorderForeground(lipgloss.Color("#10B981"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981"))
+
+	chosenStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#10B981"))
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(78)
+
+	className := m.rpgClasses[m.rpgClassCursor]
+	choices, wealthDice := m.rpgPack.getClassEquipment(className)
+	selection := m.rpgEquipment
+	rows := equipmentRows(choices, selection, wealthDice)
+
+	title := titleStyle.Render("🎒 Starting Equipment: " + className)
+
+	cursorRow := equipmentRow{line: -2, slot: -2}
+	if m.rpgEquipmentCursor < len(rows) {
+		cursorRow = rows[m.rpgEquipmentCursor]
+	}
+	render := func(row equipmentRow, text string) string {
+		if row == cursorRow {
+			return selectedStyle.Render("▶ " + text)
+		}
+		return "  " + text
+	}
+
+	var lines []string
+	if wealthDice != "" {
+		take := "◀ Class and background equipment ▶"
+		if selection.Wealth {
+			take = fmt.Sprintf("◀ Starting wealth: %s gp instead ▶", wealthDice)
+		}
+		lines = append(lines, render(equipmentRow{line: -1, slot: -1}, "Take: "+take), "")
+	}
+	if selection.Wealth {
+		lines = append(lines, dimStyle.Render("  You give up your class and background equipment and buy\n  your gear with the gold you roll."))
+	} else {
+		slots := equipmentSlots(choices, selection.Options)
+		slot := 0
+		for i, choice := range choices {
+			if len(choice) == 1 {
+				lines = append(lines, "  • "+describeEquipmentOption(choice[0]))
+			} else {
+				var options []string
+				for j, option := range choice {
+					text := fmt.Sprintf("(%c) %s", 'a'+j, describeEquipmentOption(option))
+					if j == selection.Options[i] {
+						options = append(options, chosenStyle.Render(text))
+					} else {
+						options = append(options, dimStyle.Render(text))
+					}
+				}
+				lines = append(lines, render(equipmentRow{line: i, slot: -1}, strings.Join(options, "  ")))
+			}
+			for _, item := range choice[selection.Options[i]] {
+				if _, ok := equipmentPlaceholder(item); ok {
+					text := fmt.Sprintf("    Any %s weapon: ◀ %s ▶", slots[slot], selection.Weapons[slot])
+					lines = append(lines, render(equipmentRow{line: i, slot: slot}, text))
+					slot++
+				}
+			}
+		}
+	}
+
+	// Preview what the character will carry
+	preview := m.rpgPack.equipCharacter(Character{Class: className, Background: m.rpgBackgrounds[m.rpgBackgroundCursor]}, selection)
+	var gear strings.Builder
+	if selection.Wealth {
+		gear.WriteString(fmt.Sprintf("💰 Roll %s gp when the character is made", wealthDice))
+	} else {
+		gear.WriteString("🗡️  " + orDefault(strings.Join(preview.Gear.Weapons, ", "), "No weapons"))
+		gear.WriteString("\n🛡️  " + orDefault(strings.Join(preview.Gear.Armor, ", "), "No armor"))
+		gear.WriteString("\n🎒 " + strings.Join(preview.Gear.Items, ", "))
+		gear.WriteString(fmt.Sprintf("\n💰 %d gp from %s", preview.Gold, preview.Background))
+	}
+
+	elements := []string{
+		title,
+		m.viewRPGWizardBreadcrumb(4),
+		panelStyle.Render(strings.Join(lines, "\n")),
+		previewStyle.Render(gear.String()),
+	}
+	help := "↑/↓ select • ←/→ change • Enter continue • ESC back"
+	if wealthDice != "" {
+		help = "↑/↓ select • ←/→ change • W starting wealth • Enter continue • ESC back"
+	}
+	elements = append(elements, helpStyle.Render(help))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
@@ -0,0 +1,626 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+//go:embed templates/character.*.tmpl
+var builtinTemplates embed.FS
+
+// characterSheet is everything an export shows about a character, worked
+// out once so every format agrees. Templates render it and the JSON export
+// is this struct as it stands.
+type characterSheet struct {
+	Name              string         `json:"name,omitempty"`
+	Race              string         `json:"race"`
+	Class             string         `json:"class"`
+	Background        string         `json:"background"`
+	Alignment         string         `json:"alignment,omitempty"`
+	Method            string         `json:"ability_method,omitempty"`
+	Pack              string         `json:"pack,omitempty"`
+	Level             int            `json:"level"`
+	XP                int            `json:"xp"`
+	NextLevelXP       int            `json:"next_level_xp,omitempty"`
+	HitPoints         int            `json:"hit_points"`
+	ArmorClass        int            `json:"armor_class"`
+	ArmorSource       string         `json:"armor_source"`
+	Initiative        int            `json:"initiative"`
+	Size              string         `json:"size"`
+	Speed             int            `json:"speed"`
+	Proficiency       int            `json:"proficiency_bonus"`
+	PassivePerception int            `json:"passive_perception"`
+	Abilities         []sheetAbility `json:"abilities"`
+	Skills            []sheetSkill   `json:"skills"`
+	Attacks           []sheetAttack  `json:"attacks,omitempty"`
+	Spellcasting      *sheetCasting  `json:"spellcasting,omitempty"`
+	Languages         []string       `json:"languages,omitempty"`
+	RaceTraits        []string       `json:"race_traits,omitempty"`
+	BackgroundSkills  []string       `json:"background_skills,omitempty"`
+	BackgroundTools   []string       `json:"background_tools,omitempty"`
+	BackgroundFeature string         `json:"background_feature,omitempty"`
+	Gold              int            `json:"gold"`
+	StartingWealth    string         `json:"starting_wealth,omitempty"`
+	EquipmentChoices  []string       `json:"equipment_choices,omitempty"`
+	Weapons           []string       `json:"weapons,omitempty"`
+	Armor             []string       `json:"armor,omitempty"`
+	Items             []string       `json:"items,omitempty"`
+	Traits            []string       `json:"personality_traits,omitempty"`
+	Ideal             string         `json:"ideal,omitempty"`
+	Bond              string         `json:"bond,omitempty"`
+	Flaw              string         `json:"flaw,omitempty"`
+	Backstory         string         `json:"backstory,omitempty"`
+	Notes             string         `json:"notes,omitempty"`
+}
+
+type sheetAbility struct {
+	Name           string `json:"name"`
+	Score          int    `json:"score"`
+	RaceBonus      int    `json:"racial_bonus,omitempty"`
+	Modifier       int    `json:"modifier"`
+	Save           int    `json:"save"`
+	SaveProficient bool   `json:"save_proficient,omitempty"`
+	Role           string `json:"role,omitempty"` // "primary" or "secondary" for the class
+}
+
+type sheetSkill struct {
+	Name       string `json:"name"`
+	Ability    string `json:"ability"`
+	Bonus      int    `json:"bonus"`
+	Proficient bool   `json:"proficient,omitempty"`
+}
+
+type sheetAttack struct {
+	Name        string `json:"name"`
+	AttackBonus int    `json:"attack_bonus"`
+	Damage      string `json:"damage"`
+	DamageType  string `json:"damage_type"`
+	Proficient  bool   `json:"proficient"`
+}
+
+// sheetCasting is a caster's spellcasting numbers, slots and chosen spells.
+type sheetCasting struct {
+	Ability     string       `json:"ability"`
+	SaveDC      int          `json:"save_dc"`
+	AttackBonus int          `json:"attack_bonus"`
+	PactMagic   bool         `json:"pact_magic,omitempty"`
+	Slots       []sheetSlots `json:"slots,omitempty"`
+	Cantrips    []sheetSpell `json:"cantrips,omitempty"`
+	Spells      []sheetSpell `json:"spells,omitempty"`
+	Spellbook   bool         `json:"spellbook,omitempty"` // the spells are a spellbook, some of them prepared
+}
+
+type sheetSlots struct {
+	Level int    `json:"level"`
+	Name  string `json:"name"` // "1st", "2nd" and so on
+	Total int    `json:"total"`
+	Used  int    `json:"used,omitempty"`
+}
+
+type sheetSpell struct {
+	Name        string `json:"name"`
+	Level       int    `json:"level"`
+	Kind        string `json:"kind"` // as in "1st-level evocation (ritual)"
+	CastingTime string `json:"casting_time"`
+	Range       string `json:"range"`
+	Components  string `json:"components"`
+	Duration    string `json:"duration"`
+	Prepared    bool   `json:"prepared,omitempty"`
+	Description string `json:"description"`
+}
+
+// exportFormat is one kind of file the character sheet can be saved as.
+// Formats with a template can be overridden by a file of the same name in
+// the templates folder.
+type exportFormat struct {
+	Name      string
+	Extension string
+	Template  string
+	render    func(sheet characterSheet) ([]byte, error)
+}
+
+var exportFormats = []exportFormat{
+	{Name: "Text", Extension: "txt", Template: "character.txt.tmpl", render: renderTextSheet},
+	{Name: "Markdown", Extension: "md", Template: "character.md.tmpl", render: renderMarkdownSheet},
+	{Name: "HTML", Extension: "html", Template: "character.html.tmpl", render: renderHTMLSheet},
+	{Name: "JSON", Extension: "json", render: renderJSONSheet},
+	{Name: "PDF", Extension: "pdf", Template: "character.txt.tmpl", render: renderPDFSheet},
+}
+
+func findExportFormat(extension string) exportFormat {
+	for _, format := range exportFormats {
+		if format.Extension == extension {
+			return format
+		}
+	}
+	return exportFormats[0]
+}
+
+func getTemplatesDir() string {
+	return filepath.Join(getDataDir(), "templates")
+}
+
+// getExportDir is where character sheets are written: the folder chosen on
+// the export screen, or an exports folder in the data directory.
+func getExportDir() string {
+	settings, _ := loadRPGSettings() // a damaged file falls back to the default folder
+	dir := settings.ExportDir
+	if dir == "" {
+		return filepath.Join(getDataDir(), "exports")
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[1:])
+		}
+	}
+	return dir
+}
+
+// buildCharacterSheet works out everything the exports show.
+func (pack ContentPack) buildCharacterSheet(c Character) characterSheet {
+	derived := pack.deriveStats(c)
+	classStats := pack.getClassStats(c.Class)
+	race := pack.getRaceStats(c.Race)
+	background := pack.getBackgroundStats(c.Background)
+
+	sheet := characterSheet{
+		Name:              c.Name,
+		Race:              c.Race,
+		Class:             c.Class,
+		Background:        c.Background,
+		Alignment:         c.Alignment,
+		Method:            c.Method,
+		Pack:              c.Pack,
+		Level:             derived.Level,
+		XP:                c.XP,
+		NextLevelXP:       nextLevelXP(derived.Level),
+		HitPoints:         derived.HitPoints,
+		ArmorClass:        derived.ArmorClass,
+		ArmorSource:       derived.ArmorSource,
+		Initiative:        derived.Initiative,
+		Size:              race.Size,
+		Speed:             derived.Speed,
+		Proficiency:       derived.Proficiency,
+		PassivePerception: derived.PassivePerception,
+		Languages:         race.Languages,
+		RaceTraits:        race.Traits,
+		BackgroundSkills:  background.Skills,
+		BackgroundTools:   background.Tools,
+		BackgroundFeature: background.Feature,
+		Gold:              c.Gold,
+		StartingWealth:    c.Wealth,
+		EquipmentChoices:  c.Equipment,
+		Weapons:           c.Gear.Weapons,
+		Armor:             c.Gear.Armor,
+		Items:             c.Gear.Items,
+		Traits:            c.Traits,
+		Ideal:             c.Ideal,
+		Bond:              c.Bond,
+		Flaw:              c.Flaw,
+		Backstory:         c.Backstory,
+		Notes:             c.Notes,
+	}
+
+	for _, ability := range abilityNames {
+		role := ""
+		if ability == classStats.Primary {
+			role = "primary"
+		} else if ability == classStats.Secondary {
+			role = "secondary"
+		}
+		sheet.Abilities = append(sheet.Abilities, sheetAbility{
+			Name:           ability,
+			Score:          c.Abilities[ability],
+			RaceBonus:      c.RaceBonus[ability],
+			Modifier:       derived.Modifiers[ability],
+			Save:           derived.Saves[ability],
+			SaveProficient: indexOf(derived.SaveProficiencies, ability) >= 0,
+			Role:           role,
+		})
+	}
+	for _, skill := range skillNames {
+		sheet.Skills = append(sheet.Skills, sheetSkill{
+			Name:       skill,
+			Ability:    skillAbilities[skill],
+			Bonus:      derived.Skills[skill],
+			Proficient: indexOf(derived.SkillProficiencies, skill) >= 0,
+		})
+	}
+	for _, attack := range derived.Attacks {
+		sheet.Attacks = append(sheet.Attacks, sheetAttack(attack))
+	}
+	if ability, saveDC, attack := pack.spellcastingNumbers(c); ability != "" {
+		_, _, prepared := pack.spellLimits(c)
+		casting := &sheetCasting{
+			Ability:     ability,
+			SaveDC:      saveDC,
+			AttackBonus: attack,
+			PactMagic:   classStats.Spellcasting.Slots == "pact",
+			Spellbook:   prepared > 0,
+		}
+		for i, total := range pack.spellSlots(c) {
+			if total > 0 {
+				slots := sheetSlots{Level: i + 1, Name: formatSpellLevel(i + 1), Total: total}
+				if i < len(c.SlotsUsed) {
+					slots.Used = c.SlotsUsed[i]
+				}
+				casting.Slots = append(casting.Slots, slots)
+			}
+		}
+		sheetSpells := func(names []string) []sheetSpell {
+			var spells []sheetSpell
+			for _, name := range names {
+				spell, ok := pack.getSpell(name)
+				if !ok {
+					continue
+				}
+				spells = append(spells, sheetSpell{
+					Name:        spell.Name,
+					Level:       spell.Level,
+					Kind:        describeSpell(spell),
+					CastingTime: spell.CastingTime,
+					Range:       spell.Range,
+					Components:  spell.Components,
+					Duration:    spell.Duration,
+					Prepared:    indexOf(c.Prepared, spell.Name) >= 0,
+					Description: spell.Description,
+				})
+			}
+			sort.SliceStable(spells, func(i, j int) bool {
+				if spells[i].Level != spells[j].Level {
+					return spells[i].Level < spells[j].Level
+				}
+				return spells[i].Name < spells[j].Name
+			})
+			return spells
+		}
+		casting.Cantrips = sheetSpells(c.Cantrips)
+		casting.Spells = sheetSpells(c.Spells)
+		sheet.Spellcasting = casting
+	}
+	return sheet
+}
+
+// sheetTemplateFuncs are the helpers export templates can call.
+var sheetTemplateFuncs = map[string]any{
+	"mod":   formatModifier,
+	"join":  func(items []string) string { return strings.Join(items, ", ") },
+	"upper": strings.ToUpper,
+	"pad":   func(width int, text string) string { return fmt.Sprintf("%-*s", width, text) },
+	"md":    escapeMarkdown,
+}
+
+// escapeMarkdown backslash-escapes the characters Markdown would treat as
+// formatting or markup, so names and notes print as typed.
+func escapeMarkdown(text string) string {
+	var out strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune("\\`*_{}[]()<>#+-!|~", r) {
+			out.WriteByte('\\')
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// loadSheetTemplate returns the user's copy of a template from the templates
+// folder if there is one, otherwise the built-in template, along with where
+// it came from.
+func loadSheetTemplate(name string) (string, string, error) {
+	path := filepath.Join(getTemplatesDir(), name)
+	if data, err := os.ReadFile(path); err == nil {
+		return string(data), path, nil
+	} else if !os.IsNotExist(err) {
+		return "", path, err
+	}
+	data, err := builtinTemplates.ReadFile("templates/" + name)
+	return string(data), "built-in", err
+}
+
+func executeTextTemplate(name string, sheet characterSheet) ([]byte, error) {
+	text, source, err := loadSheetTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(name).Funcs(sheetTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, sheet); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return out.Bytes(), nil
+}
+
+func renderTextSheet(sheet characterSheet) ([]byte, error) {
+	return executeTextTemplate("character.txt.tmpl", sheet)
+}
+
+func renderMarkdownSheet(sheet characterSheet) ([]byte, error) {
+	return executeTextTemplate("character.md.tmpl", sheet)
+}
+
+// renderHTMLSheet uses html/template, which escapes every value for the
+// place it appears, so names, gear and notes can't inject markup.
+func renderHTMLSheet(sheet characterSheet) ([]byte, error) {
+	text, source, err := loadSheetTemplate("character.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := htmltemplate.New("character.html.tmpl").Funcs(sheetTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, sheet); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return out.Bytes(), nil
+}
+
+func renderJSONSheet(sheet characterSheet) ([]byte, error) {
+	data, err := json.MarshalIndent(sheet, "", "  ")
+	return append(data, '\n'), err
+}
+
+// renderPDFSheet prints the text sheet straight to a PDF, so a custom text
+// template changes the PDF too.
+func renderPDFSheet(sheet characterSheet) ([]byte, error) {
+	text, err := renderTextSheet(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return renderTextPDF(string(text)), nil
+}
+
+// exportCharacter writes the character sheet in the given format to the
+// export folder and returns the file's path.
+func (pack ContentPack) exportCharacter(c Character, format exportFormat) (string, error) {
+	data, err := format.render(pack.buildCharacterSheet(c))
+	if err != nil {
+		return "", err
+	}
+
+	dir := getExportDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := filepath.Join(dir, fmt.Sprintf("%s_Character_%s.%s", characterFilePrefix(c), timestamp, format.Extension))
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// copyBuiltinTemplates writes the built-in templates into the templates
+// folder for editing, leaving any that are already there alone.
+func copyBuiltinTemplates() (int, error) {
+	if err := os.MkdirAll(getTemplatesDir(), 0755); err != nil {
+		return 0, err
+	}
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return 0, err
+	}
+	copied := 0
+	for _, entry := range entries {
+		path := filepath.Join(getTemplatesDir(), entry.Name())
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		data, err := builtinTemplates.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return copied, err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+// exportRPGCharacter exports the current character and reports the result on
+// the character sheet.
+func (m model) exportRPGCharacter(format exportFormat) model {
+	filename, err := m.rpgPack.exportCharacter(m.rpgCharacter, format)
+	if err != nil {
+		m.rpgExportStatus = fmt.Sprintf("❌ %s export failed: %v", format.Name, err)
+	} else {
+		m.rpgExportStatus = "✅ Character saved to " + filename
+	}
+	return m
+}
+
+func (m model) openRPGExport() model {
+	m.rpgExportDirEditing = false
+	m.rpgExportStatus = ""
+	m.state = rpgExportView
+	return m
+}
+
+func (m model) updateRPGExport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.rpgExportDirEditing {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.rpgExportDirEditing = false
+		case "backspace":
+			if len(m.rpgExportDirInput) > 0 {
+				m.rpgExportDirInput = m.rpgExportDirInput[:len(m.rpgExportDirInput)-1]
+			}
+		case "enter":
+			settings, _ := loadRPGSettings() // saveRPGSettings reports a damaged file
+			settings.ExportDir = strings.TrimSpace(m.rpgExportDirInput)
+			if err := saveRPGSettings(settings); err != nil {
+				m.rpgExportStatus = "❌ Could not save the export folder: " + err.Error()
+			} else {
+				m.rpgExportStatus = "✅ Sheets will be saved to " + getExportDir()
+			}
+			m.rpgExportDirEditing = false
+		default:
+			if len(keyMsg.String()) == 1 {
+				m.rpgExportDirInput += keyMsg.String()
+			}
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "x":
+		m.state = rpgCharacterView
+	case "up", "k":
+		if m.rpgExportCursor > 0 {
+			m.rpgExportCursor--
+		}
+	case "down", "j":
+		if m.rpgExportCursor < len(exportFormats)-1 {
+			m.rpgExportCursor++
+		}
+	case "enter", " ":
+		m = m.exportRPGCharacter(exportFormats[m.rpgExportCursor])
+	case "a":
+		for _, format := range exportFormats {
+			if _, err := m.rpgPack.exportCharacter(m.rpgCharacter, format); err != nil {
+				m.rpgExportStatus = fmt.Sprintf("❌ %s export failed: %v", format.Name, err)
+				return m, nil
+			}
+		}
+		m.rpgExportStatus = fmt.Sprintf("✅ Saved %d formats to %s", len(exportFormats), getExportDir())
+	case "d":
+		settings, err := loadRPGSettings()
+		if err != nil {
+			m.rpgExportStatus = "❌ " + err.Error()
+		}
+		m.rpgExportDirInput = settings.ExportDir
+		m.rpgExportDirEditing = true
+	case "t":
+		copied, err := copyBuiltinTemplates()
+		if err != nil {
+			m.rpgExportStatus = "❌ Could not copy templates: " + err.Error()
+		} else {
+			m.rpgExportStatus = fmt.Sprintf("✅ Copied %d templates to %s", copied, getTemplatesDir())
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewRPGExport() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(78).
+		AlignHorizontal(lipgloss.Center)
+
+	menuStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(24)
+
+	detailStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#10B981")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(52)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981")).
+		Padding(0, 1)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8B5CF6")).
+		Padding(0, 1)
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#10B981")).
+		Padding(0, 1).
+		MarginBottom(1).
+		Width(78)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(78)
+
+	title := titleStyle.Render("💾 Export " + characterDisplayName(m.rpgCharacter))
+
+	var optionLines []string
+	for i, format := range exportFormats {
+		if i == m.rpgExportCursor {
+			optionLines = append(optionLines, selectedStyle.Render("▶ "+format.Name))
+		} else {
+			optionLines = append(optionLines, normalStyle.Render("  "+format.Name))
+		}
+	}
+	menu := menuStyle.Render(strings.Join(optionLines, "\n"))
+
+	format := exportFormats[m.rpgExportCursor]
+	var detail strings.Builder
+	detail.WriteString(lipgloss.NewStyle().Bold(true).Render(format.Name) + "\n\n")
+	detail.WriteString(fmt.Sprintf("File: %s_Character_<time>.%s\nFolder: %s\n", characterFilePrefix(m.rpgCharacter), format.Extension, getExportDir()))
+	if format.Template == "" {
+		detail.WriteString("\nEvery number on the sheet, for other tools to read")
+	} else {
+		_, source, _ := loadSheetTemplate(format.Template)
+		detail.WriteString(fmt.Sprintf("\nTemplate: %s (%s)", format.Template, source))
+		if format.Extension == "pdf" {
+			detail.WriteString("\nPrinted from the text template, ready to print")
+		}
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, menu, "  ", detailStyle.Render(detail.String()))
+
+	elements := []string{title, body}
+	if m.rpgExportDirEditing {
+		elements = append(elements, inputStyle.Render("Export folder: "+m.rpgExportDirInput+"█"))
+	}
+	if m.rpgExportStatus != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.rpgExportStatus))
+	}
+	if m.rpgExportDirEditing {
+		elements = append(elements, helpStyle.Render("Enter to save • leave empty for the default folder • ESC to cancel"))
+	} else {
+		elements = append(elements, helpStyle.Render(fmt.Sprintf("↑/↓ to select • Enter to export • A to export all • D to change folder • T to copy templates • ESC to go back\nTemplates in %s replace the built-in ones", getTemplatesDir())))
+	}
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var abilityNames = []string{"Strength", "Dexterity", "Constitution", "Intelligence", "Wisdom", "Charisma"}
+
+func (pack ContentPack) getRaceStats(raceName string) RaceStats {
+	for _, race := range pack.Races {
+		if race.Name == raceName {
+			return race.RaceStats
+		}
+	}
+	return RaceStats{}
+}
+
+func (pack ContentPack) getBackgroundStats(backgroundName string) BackgroundStats {
+	for _, background := range pack.Backgrounds {
+		if background.Name == backgroundName {
+			return background.BackgroundStats
+		}
+	}
+	return BackgroundStats{}
+}
+
+// raceAbilityBonus works out the racial increases for a character. Fixed
+// increases come straight from the race; flexible +1s (Half-Elf) go to the
+// class's primary and secondary abilities, then the highest remaining scores,
+// skipping any ability the race already raises.
+func (pack ContentPack) raceAbilityBonus(raceName, className string, scores map[string]int) map[string]int {
+	race := pack.getRaceStats(raceName)
+	bonus := make(map[string]int)
+	for ability, increase := range race.AbilityIncreases {
+		bonus[ability] = increase
+	}
+
+	if race.FlexibleIncrease > 0 {
+		classStats := pack.getClassStats(className)
+		candidates := append([]string{}, abilityNames...)
+		sort.SliceStable(candidates, func(i, j int) bool {
+			rank := func(ability string) int {
+				switch ability {
+				case classStats.Primary:
+					return 2
+				case classStats.Secondary:
+					return 1
+				}
+				return 0
+			}
+			if rank(candidates[i]) != rank(candidates[j]) {
+				return rank(candidates[i]) > rank(candidates[j])
+			}
+			return scores[candidates[i]] > scores[candidates[j]]
+		})
+
+		remaining := race.FlexibleIncrease
+		for _, ability := range candidates {
+			if remaining == 0 {
+				break
+			}
+			if bonus[ability] == 0 {
+				bonus[ability] = 1
+				remaining--
+			}
+		}
+	}
+	return bonus
+}
+
+// applyRaceBonus adds the racial increases to the rolled scores, capped at 20.
+func applyRaceBonus(scores, bonus map[string]int) map[string]int {
+	final := make(map[string]int)
+	for ability, score := range scores {
+		final[ability] = min(score+bonus[ability], 20)
+	}
+	return final
+}
+
+// buildCharacter makes a new character for the chosen race, class and
+// background from base ability scores: racial increases applied, class skill
+// picks, the chosen starting equipment or starting wealth, and spells for a
+// caster.
+func (pack ContentPack) buildCharacter(raceName, className, backgroundName string, scores map[string]int, equipment EquipmentSelection) Character {
+	bonus := pack.raceAbilityBonus(raceName, className, scores)
+
+	character := Character{
+		Race:       raceName,
+		Class:      className,
+		Background: backgroundName,
+		Level:      1,
+		Pack:       pack.ID,
+		Abilities:  applyRaceBonus(scores, bonus),
+		RaceBonus:  bonus,
+	}
+	character = pack.equipCharacter(character, equipment)
+	character.Skills = pack.chooseClassSkills(character)
+	return pack.chooseSpells(character)
+}
+
+func formatAbilityIncreases(increases map[string]int, flexible int) string {
+	var parts []string
+	for _, ability := range abilityNames {
+		if increase, exists := increases[ability]; exists {
+			parts = append(parts, fmt.Sprintf("%s +%d", ability[:3], increase))
+		}
+	}
+	if len(parts) == 6 {
+		parts = []string{"All abilities +1"}
+	}
+	if flexible > 0 {
+		parts = append(parts, fmt.Sprintf("+1 to %d others", flexible))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (m model) updateRPGRaceSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if len(m.rpgRaces) == 0 {
+		return m.updateRPGPacks(msg)
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.state = menuView
+		case "o":
+			m = m.openRPGRoster()
+		case "g":
+			m = m.openRPGParty()
+		case "p":
+			m.rpgPackCursor = max(0, findContentPack(m.rpgPacks, m.rpgPack.ID))
+			m.state = rpgPackView
+		case "up", "k":
+			if m.rpgRaceCursor > 0 {
+				m.rpgRaceCursor--
+			}
+		case "down", "j":
+			if m.rpgRaceCursor < len(m.rpgRaces)-1 {
+				m.rpgRaceCursor++
+			}
+		case "enter", " ":
+			m.state = rpgClassSelectionView
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateRPGBackgroundSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.state = rpgClassSelectionView
+		case "up", "k":
+			if m.rpgBackgroundCursor > 0 {
+				m.rpgBackgroundCursor--
+			}
+		case "down", "j":
+			if m.rpgBackgroundCursor < len(m.rpgBackgrounds)-1 {
+				m.rpgBackgroundCursor++
+			}
+		case "enter", " ":
+			m = m.openRPGEquipment(rpgBackgroundSelectionView)
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewRPGRaceSelection() string {
+	if len(m.rpgRaces) == 0 {
+		return m.viewRPGPacks()
+	}
+	race := m.rpgPack.getRaceStats(m.rpgRaces[m.rpgRaceCursor])
+	detail := fmt.Sprintf("Ability scores: %s\nSize: %s • Speed: %d ft\nLanguages: %s\n\nTraits:\n  • %s",
+		formatAbilityIncreases(race.AbilityIncreases, race.FlexibleIncrease),
+		race.Size, race.Speed,
+		strings.Join(race.Languages, ", "),
+		strings.Join(race.Traits, "\n  • "))
+	return m.viewRPGWizardStep(1, "Choose Your Race • "+m.rpgPack.Name, m.rpgRaces, m.rpgRaceCursor, detail, "P for content packs • O for saved characters • G for a party • ESC to go back")
+}
+
+func (m model) viewRPGBackgroundSelection() string {
+	background := m.rpgPack.getBackgroundStats(m.rpgBackgrounds[m.rpgBackgroundCursor])
+	detail := fmt.Sprintf("Skills: %s\n", strings.Join(background.Skills, ", "))
+	if len(background.Tools) > 0 {
+		detail += fmt.Sprintf("Tools: %s\n", strings.Join(background.Tools, ", "))
+	}
+	if background.Languages > 0 {
+		detail += fmt.Sprintf("Languages: %d of your choice\n", background.Languages)
+	}
+	detail += fmt.Sprintf("Feature: %s\n\nEquipment:\n  • %s\n  • %d gp",
+		background.Feature,
+		strings.Join(background.Equipment, "\n  • "),
+		background.Gold)
+	return m.viewRPGWizardStep(3, "Choose Your Background", m.rpgBackgrounds, m.rpgBackgroundCursor, detail, "ESC to change class")
+}
+
+// viewRPGWizardStep draws one step of the character creation wizard:
+// the options on the left and details of the highlighted one on the right.
+func (m model) viewRPGWizardStep(step int, heading string, options []string, cursor int, detail string, backHint string) string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(78).
+		AlignHorizontal(lipgloss.Center)
+
+	menuStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(30)
+
+	detailStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#10B981")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(46)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981")).
+		Padding(0, 1)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8B5CF6")).
+		Padding(0, 1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(78)
+
+	title := titleStyle.Render("⚔️  " + heading)
+
+	var optionLines []string
+	for i, option := range options {
+		if i == cursor {
+			optionLines = append(optionLines, selectedStyle.Render("▶ "+option))
+		} else {
+			optionLines = append(optionLines, normalStyle.Render("  "+option))
+		}
+	}
+	menu := menuStyle.Render(strings.Join(optionLines, "\n"))
+	details := detailStyle.Render(lipgloss.NewStyle().Bold(true).Render(options[cursor]) + "\n\n" + detail)
+	body := lipgloss.JoinHorizontal(lipgloss.Top, menu, "  ", details)
+
+	help := helpStyle.Render("Use ↑/↓ or j/k to navigate • Enter to select • " + backHint + " • Ctrl+C to quit")
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, title, m.viewRPGWizardBreadcrumb(step), body, help))
+}
+
+// viewRPGWizardBreadcrumb shows the wizard steps with the choices made so far.
+func (m model) viewRPGWizardBreadcrumb(step int) string {
+	stepStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8B5CF6")).
+		Bold(true).
+		MarginBottom(1)
+
+	equipment := "Equipment"
+	if m.rpgEquipment.Wealth {
+		equipment = "Starting wealth"
+	}
+	steps := []string{"Race", "Class", "Background", "Equipment", "Abilities"}
+	choices := []string{m.rpgRaces[m.rpgRaceCursor], m.rpgClasses[m.rpgClassCursor], m.rpgBackgrounds[m.rpgBackgroundCursor], equipment, abilityMethodNames[m.rpgAbilityMethod]}
+	var crumbs []string
+	for i, name := range steps {
+		switch {
+		case i+1 < step:
+			crumbs = append(crumbs, fmt.Sprintf("✓ %s", choices[i]))
+		case i+1 == step:
+			crumbs = append(crumbs, fmt.Sprintf("▶ %s", name))
+		default:
+			crumbs = append(crumbs, name)
+		}
+	}
+	return stepStyle.Render(fmt.Sprintf("Step %d of %d • %s", step, len(steps), strings.Join(crumbs, " → ")))
+}
@@ -0,0 +1,632 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+//go:embed packs/srd-5e.json
+var builtinPackData []byte
+
+const defaultPackID = "srd-5e"
+
+var validHitDice = map[int]bool{4: true, 6: true, 8: true, 10: true, 12: true, 20: true}
+
+type rpgSettings struct {
+	Pack      string `json:"pack"`
+	ExportDir string `json:"export_dir,omitempty"`
+	PartySize int    `json:"party_size,omitempty"`
+	PartyRule string `json:"party_rule,omitempty"`
+	PartyPlan string `json:"party_plan,omitempty"` // roles for the custom rule, e.g. "tank, healer, any"
+}
+
+func getPacksDir() string {
+	return filepath.Join(getDataDir(), "packs")
+}
+
+func getRPGSettingsFilePath() string {
+	return filepath.Join(getDataDir(), "rpg-settings.json")
+}
+
+// loadRPGSettings reads the creator's settings. A missing file means the
+// defaults, but a file that can't be read is an error, and saveRPGSettings
+// won't write over it.
+func loadRPGSettings() (rpgSettings, error) {
+	var settings rpgSettings
+	data, err := os.ReadFile(getRPGSettingsFilePath())
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return rpgSettings{}, fmt.Errorf("%s is damaged (%s) • fix or move it to save settings again", getRPGSettingsFilePath(), describeJSONError(data, 0, err))
+	}
+	return settings, nil
+}
+
+func saveRPGSettings(settings rpgSettings) error {
+	if _, err := loadRPGSettings(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getRPGSettingsFilePath(), data, 0644)
+}
+
+// parseContentPack decodes a pack file, recording any problem in the pack's
+// Errors rather than failing. A pack without an ID is named after its file.
+func parseContentPack(data []byte, source, filename string) ContentPack {
+	var pack ContentPack
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&pack); err != nil {
+		pack = ContentPack{Errors: []string{describeJSONError(data, decoder.InputOffset(), err)}}
+	}
+
+	if pack.ID == "" {
+		pack.ID = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+	if pack.Name == "" {
+		pack.Name = pack.ID
+	}
+	pack.Source = source
+	return pack
+}
+
+// describeJSONError turns a decoding error into a message with the line it
+// happened on.
+func describeJSONError(data []byte, offset int64, err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+		err = fmt.Errorf("%s should be %s, not %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	line := 1 + strings.Count(string(data[:min(int(offset), len(data))]), "\n")
+	return fmt.Sprintf("line %d: %s", line, strings.TrimPrefix(err.Error(), "json: "))
+}
+
+// loadContentPacks returns the built-in pack followed by the user's packs
+// from the packs directory, with extended packs merged onto their base and
+// every pack validated.
+func loadContentPacks() []ContentPack {
+	packs := []ContentPack{parseContentPack(builtinPackData, "built-in", "srd-5e.json")}
+
+	files, _ := filepath.Glob(filepath.Join(getPacksDir(), "*.json"))
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			packs = append(packs, ContentPack{ID: filepath.Base(file), Name: filepath.Base(file), Source: file, Errors: []string{err.Error()}})
+			continue
+		}
+		pack := parseContentPack(data, file, filepath.Base(file))
+		if i := findContentPack(packs, pack.ID); i >= 0 {
+			pack.Errors = append(pack.Errors, fmt.Sprintf("id %q is already used by %s", pack.ID, packs[i].Source))
+		}
+		packs = append(packs, pack)
+	}
+
+	// Packs are JSON only; list YAML files so they don't just go missing
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		files, _ := filepath.Glob(filepath.Join(getPacksDir(), pattern))
+		for _, file := range files {
+			packs = append(packs, ContentPack{ID: filepath.Base(file), Name: filepath.Base(file), Source: file, Errors: []string{"packs must be JSON; convert this YAML file to .json"}})
+		}
+	}
+
+	resolved := make([]ContentPack, len(packs))
+	for i := range packs {
+		resolved[i] = resolveContentPack(packs, i, map[string]bool{})
+		resolved[i].Errors = append(resolved[i].Errors, validateContentPack(resolved[i])...)
+	}
+	return resolved
+}
+
+func findContentPack(packs []ContentPack, id string) int {
+	for i, pack := range packs {
+		if pack.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolveContentPack merges a pack onto the pack it extends, following the
+// chain of bases.
+func resolveContentPack(packs []ContentPack, index int, visiting map[string]bool) ContentPack {
+	pack := packs[index]
+	if pack.Extends == "" || len(pack.Errors) > 0 {
+		return pack
+	}
+	if visiting[pack.ID] {
+		pack.Errors = append(pack.Errors, fmt.Sprintf("extends %q, which extends it back", pack.Extends))
+		return pack
+	}
+	baseIndex := findContentPack(packs, pack.Extends)
+	if baseIndex < 0 || baseIndex == index {
+		pack.Errors = append(pack.Errors, fmt.Sprintf("extends %q, which is not installed", pack.Extends))
+		return pack
+	}
+
+	visiting[pack.ID] = true
+	base := resolveContentPack(packs, baseIndex, visiting)
+	if len(base.Errors) > 0 {
+		pack.Errors = append(pack.Errors, fmt.Sprintf("extends %q, which has errors", pack.Extends))
+		return pack
+	}
+
+	merged := pack
+	merged.Classes = mergeByName(base.Classes, pack.Classes, func(c PackClass) string { return c.Name })
+	merged.Races = mergeByName(base.Races, pack.Races, func(r PackRace) string { return r.Name })
+	merged.Backgrounds = mergeByName(base.Backgrounds, pack.Backgrounds, func(b PackBackground) string { return b.Name })
+	merged.Weapons = mergeByName(base.Weapons, pack.Weapons, func(w PackWeapon) string { return w.Name })
+	merged.Armor = mergeByName(base.Armor, pack.Armor, func(a PackArmor) string { return a.Name })
+	merged.EquipmentPacks = mergeByName(base.EquipmentPacks, pack.EquipmentPacks, func(p EquipmentPack) string { return p.Name })
+	merged.Spells = mergeByName(base.Spells, pack.Spells, func(s PackSpell) string { return s.Name })
+	merged.Monsters = mergeByName(base.Monsters, pack.Monsters, func(m PackMonster) string { return m.Name })
+	return merged
+}
+
+// mergeByName replaces base entries with overrides of the same name and
+// appends the rest.
+func mergeByName[T any](base, overrides []T, name func(T) string) []T {
+	merged := append([]T{}, base...)
+	for _, override := range overrides {
+		replaced := false
+		for i := range merged {
+			if name(merged[i]) == name(override) {
+				merged[i] = override
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
+
+// validateContentPack checks a resolved pack for anything that would break
+// the character creator, such as unknown abilities, skills or weapons.
+func validateContentPack(pack ContentPack) []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	if len(pack.Errors) > 0 {
+		return nil
+	}
+
+	isAbility := func(name string) bool {
+		for _, ability := range abilityNames {
+			if ability == name {
+				return true
+			}
+		}
+		return false
+	}
+	checkSkills := func(where string, skills []string) {
+		for _, skill := range skills {
+			if _, ok := skillAbilities[skill]; !ok {
+				add("%s: unknown skill %q", where, skill)
+			}
+		}
+	}
+	weapons := make(map[string]bool)
+	for _, weapon := range pack.Weapons {
+		weapons[weapon.Name] = true
+	}
+	checkWeapons := func(where string, proficiencies []string) {
+		for _, proficiency := range proficiencies {
+			if proficiency != "simple" && proficiency != "martial" && !weapons[proficiency] {
+				add("%s: unknown weapon %q (use simple, martial or a weapon from the pack)", where, proficiency)
+			}
+		}
+	}
+	checkNames := func(kind string, count int, name func(int) string) {
+		if count == 0 {
+			add("no %s", kind)
+		}
+		seen := make(map[string]bool)
+		for i := 0; i < count; i++ {
+			switch n := name(i); {
+			case n == "":
+				add("%s[%d]: missing name", kind, i)
+			case seen[n]:
+				add("%s: %q appears twice", kind, n)
+			default:
+				seen[n] = true
+			}
+		}
+	}
+
+	checkNames("classes", len(pack.Classes), func(i int) string { return pack.Classes[i].Name })
+	classes := make(map[string]bool)
+	for _, class := range pack.Classes {
+		classes[class.Name] = true
+	}
+	for _, class := range pack.Classes {
+		where := fmt.Sprintf("class %q", class.Name)
+		if !isAbility(class.Primary) || !isAbility(class.Secondary) {
+			add("%s: primary and secondary must be abilities, e.g. \"Strength\"", where)
+		}
+		if !validHitDice[class.HitDie] {
+			add("%s: hit_die must be 4, 6, 8, 10, 12 or 20", where)
+		}
+		for _, ability := range class.SavingThrows {
+			if !isAbility(ability) {
+				add("%s: unknown saving throw %q", where, ability)
+			}
+		}
+		if class.SkillChoices < 0 {
+			add("%s: skill_choices cannot be negative", where)
+		}
+		checkSkills(where, class.SkillOptions)
+		checkWeapons(where, class.WeaponProficiencies)
+		for _, role := range class.Roles {
+			if indexOf(partyRoles, role) < 0 {
+				add("%s: unknown role %q (use %s)", where, role, strings.Join(partyRoles, ", "))
+			}
+		}
+		for i, choice := range class.Equipment {
+			if len(choice) == 0 {
+				add("%s: equipment line %d has no options", where, i+1)
+			}
+			for _, option := range choice {
+				for _, item := range option {
+					if kind, ok := equipmentPlaceholder(item); ok && len(weaponsOfKind(pack.Weapons, kind)) == 0 {
+						add("%s: no weapons match %q", where, item)
+					}
+				}
+			}
+		}
+		if class.StartingWealth != "" {
+			if _, err := parseDiceExpression(class.StartingWealth); err != nil {
+				add("%s: starting_wealth %q is not a dice expression", where, class.StartingWealth)
+			}
+		}
+		if casting := class.Spellcasting; casting != nil {
+			if !isAbility(casting.Ability) {
+				add("%s: spellcasting ability must be an ability, e.g. \"Wisdom\"", where)
+			}
+			if casting.Slots != "full" && casting.Slots != "half" && casting.Slots != "pact" {
+				add("%s: spellcasting slots must be full, half or pact", where)
+			}
+			if casting.Prepares != "" && casting.Prepares != "level" && casting.Prepares != "half level" {
+				add("%s: spellcasting prepares must be level or half level", where)
+			}
+			if len(casting.Known) == 0 && casting.Prepares == "" {
+				add("%s: spellcasting needs known spells or prepares", where)
+			}
+			for _, table := range []struct {
+				name   string
+				values []int
+			}{{"cantrips", casting.Cantrips}, {"known", casting.Known}} {
+				if len(table.values) > 0 && len(table.values) != 20 {
+					add("%s: spellcasting %s needs a number for each level, 1 to 20", where, table.name)
+				}
+			}
+			if casting.SpellList != "" && !classes[casting.SpellList] {
+				add("%s: spell_list %q is not a class in the pack", where, casting.SpellList)
+			}
+		}
+	}
+
+	checkNames("races", len(pack.Races), func(i int) string { return pack.Races[i].Name })
+	for _, race := range pack.Races {
+		where := fmt.Sprintf("race %q", race.Name)
+		for ability, increase := range race.AbilityIncreases {
+			if !isAbility(ability) {
+				add("%s: unknown ability %q", where, ability)
+			} else if increase < 1 {
+				add("%s: %s increase must be at least 1", where, ability)
+			}
+		}
+		if race.Speed <= 0 {
+			add("%s: speed must be above 0", where)
+		}
+		if race.Size == "" {
+			add("%s: missing size", where)
+		}
+		checkSkills(where, race.Skills)
+		checkWeapons(where, race.Weapons)
+	}
+
+	checkNames("backgrounds", len(pack.Backgrounds), func(i int) string { return pack.Backgrounds[i].Name })
+	for _, background := range pack.Backgrounds {
+		where := fmt.Sprintf("background %q", background.Name)
+		checkSkills(where, background.Skills)
+		if background.Gold < 0 {
+			add("%s: gold cannot be negative", where)
+		}
+		for _, hook := range background.Personality.Hooks {
+			for _, placeholder := range bracePlaceholders(hook) {
+				if indexOf(hookPlaceholders, placeholder) < 0 {
+					add("%s: unknown %s in a hook (use %s)", where, placeholder, strings.Join(hookPlaceholders, ", "))
+				}
+			}
+		}
+	}
+
+	for _, weapon := range pack.Weapons {
+		where := fmt.Sprintf("weapon %q", weapon.Name)
+		if _, err := parseDiceExpression(weapon.Damage); err != nil {
+			add("%s: damage %q is not a dice expression", where, weapon.Damage)
+		}
+		if weapon.Category != "simple" && weapon.Category != "martial" {
+			add("%s: category must be simple or martial", where)
+		}
+	}
+
+	for _, armor := range pack.Armor {
+		where := fmt.Sprintf("armor %q", armor.Name)
+		if armor.BaseAC <= 0 {
+			add("%s: base_ac must be above 0", where)
+		}
+		if armor.Category != "light" && armor.Category != "medium" && armor.Category != "heavy" {
+			add("%s: category must be light, medium or heavy", where)
+		}
+	}
+
+	for _, equipmentPack := range pack.EquipmentPacks {
+		if equipmentPack.Name == "" || len(equipmentPack.Contents) == 0 {
+			add("equipment pack %q: needs a name and contents", equipmentPack.Name)
+		}
+	}
+
+	if len(pack.Spells) > 0 {
+		checkNames("spells", len(pack.Spells), func(i int) string { return pack.Spells[i].Name })
+	}
+	for _, spell := range pack.Spells {
+		where := fmt.Sprintf("spell %q", spell.Name)
+		if spell.Level < 0 || spell.Level > 9 {
+			add("%s: level must be 0 (a cantrip) to 9", where)
+		}
+		if indexOf(spellSchools, spell.School) < 0 {
+			add("%s: unknown school %q (use %s)", where, spell.School, strings.Join(spellSchools, ", "))
+		}
+		for _, class := range spell.Classes {
+			if !classes[class] {
+				add("%s: unknown class %q", where, class)
+			}
+		}
+	}
+
+	if len(pack.Monsters) > 0 {
+		checkNames("monsters", len(pack.Monsters), func(i int) string { return pack.Monsters[i].Name })
+	}
+	for _, monster := range pack.Monsters {
+		where := fmt.Sprintf("monster %q", monster.Name)
+		if _, ok := challengeXP[monster.CR]; !ok {
+			add("%s: unknown cr %q (use 0, 1/8, 1/4, 1/2 or 1 to 30)", where, monster.CR)
+		}
+		if monster.AC <= 0 || monster.HP <= 0 {
+			add("%s: ac and hp must be above 0", where)
+		}
+	}
+	return problems
+}
+
+// bracePlaceholders finds the "{...}" placeholders in some text.
+func bracePlaceholders(text string) []string {
+	var found []string
+	for {
+		open := strings.Index(text, "{")
+		if open < 0 {
+			return found
+		}
+		length := strings.Index(text[open:], "}")
+		if length < 0 {
+			return append(found, text[open:])
+		}
+		found = append(found, text[open:open+length+1])
+		text = text[open+length+1:]
+	}
+}
+
+// useContentPack makes pack the active pack and starts the wizard over on
+// its first options.
+func (m model) useContentPack(pack ContentPack) model {
+	m.rpgPack = pack
+	m.rpgRaces, m.rpgClasses, m.rpgBackgrounds = pack.raceNames(), pack.classNames(), pack.backgroundNames()
+	m.rpgRaceCursor, m.rpgClassCursor, m.rpgBackgroundCursor = 0, 0, 0
+	if m.rpgCharacter.Pack != pack.ID {
+		m.rpgCharacter = Character{}
+	}
+	return m
+}
+
+// loadRPGPacks reads the installed packs and switches to the saved choice,
+// falling back to the built-in pack if it is missing or broken.
+func (m model) loadRPGPacks() model {
+	m.rpgPacks = loadContentPacks()
+	settings, err := loadRPGSettings()
+	if err != nil {
+		m.rpgPackMessage = "❌ " + err.Error()
+	}
+	wanted := settings.Pack
+	if wanted == "" {
+		wanted = defaultPackID
+	}
+	i := findContentPack(m.rpgPacks, wanted)
+	if i < 0 || len(m.rpgPacks[i].Errors) > 0 {
+		m.rpgPackMessage = fmt.Sprintf("❌ Pack %q could not be used, so the built-in pack is active", wanted)
+		i = 0
+	}
+	m.rpgPackCursor = i
+	if len(m.rpgPacks[i].Errors) > 0 {
+		// Only a broken build can get here, but say so rather than offer an
+		// empty wizard
+		m.rpgPackMessage = fmt.Sprintf("❌ The built-in pack has errors, so no pack can be used: %s", m.rpgPacks[i].Errors[0])
+		return m.useContentPack(ContentPack{})
+	}
+	return m.useContentPack(m.rpgPacks[i])
+}
+
+func (m model) updateRPGPacks(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		if len(m.rpgRaces) == 0 {
+			m.state = menuView // there's no pack to make characters with
+			return m, nil
+		}
+		m.rpgPackMessage = ""
+		m.state = rpgRaceSelectionView
+	case "up", "k":
+		if m.rpgPackCursor > 0 {
+			m.rpgPackCursor--
+		}
+	case "down", "j":
+		if m.rpgPackCursor < len(m.rpgPacks)-1 {
+			m.rpgPackCursor++
+		}
+	case "r":
+		active := m.rpgPack.ID
+		m.rpgPacks = loadContentPacks()
+		m.rpgPackCursor = max(0, findContentPack(m.rpgPacks, active))
+		m.rpgPackMessage = fmt.Sprintf("✅ Reloaded %d packs", len(m.rpgPacks))
+	case "enter", " ":
+		pack := m.rpgPacks[m.rpgPackCursor]
+		if len(pack.Errors) > 0 {
+			m.rpgPackMessage = fmt.Sprintf("❌ %s has errors and cannot be used", pack.Name)
+			return m, nil
+		}
+		m = m.useContentPack(pack)
+		settings, _ := loadRPGSettings() // saveRPGSettings reports a damaged file
+		settings.Pack = pack.ID
+		if err := saveRPGSettings(settings); err != nil {
+			m.rpgPackMessage = fmt.Sprintf("❌ Switched to %s but could not save the choice: %v", pack.Name, err)
+			return m, nil
+		}
+		m.rpgPackMessage = ""
+		m.state = rpgRaceSelectionView
+	}
+	return m, nil
+}
+
+func (m model) viewRPGPacks() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(78).
+		AlignHorizontal(lipgloss.Center)
+
+	menuStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(30)
+
+	detailStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#10B981")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(46)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981")).
+		Padding(0, 1)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8B5CF6")).
+		Padding(0, 1)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(78)
+
+	title := titleStyle.Render("📦 Content Packs")
+
+	var optionLines []string
+	for i, pack := range m.rpgPacks {
+		marker := "  "
+		if pack.ID == m.rpgPack.ID {
+			marker = "✓ "
+		} else if len(pack.Errors) > 0 {
+			marker = "✗ "
+		}
+		if i == m.rpgPackCursor {
+			optionLines = append(optionLines, selectedStyle.Render("▶ "+marker+pack.Name))
+		} else {
+			optionLines = append(optionLines, normalStyle.Render("  "+marker+pack.Name))
+		}
+	}
+	menu := menuStyle.Render(strings.Join(optionLines, "\n"))
+
+	pack := m.rpgPacks[m.rpgPackCursor]
+	var detail strings.Builder
+	detail.WriteString(lipgloss.NewStyle().Bold(true).Render(pack.Name) + "\n\n")
+	if pack.Description != "" {
+		detail.WriteString(pack.Description + "\n\n")
+	}
+	detail.WriteString(fmt.Sprintf("ID: %s\nSource: %s\n", pack.ID, pack.Source))
+	if pack.Extends != "" {
+		detail.WriteString(fmt.Sprintf("Extends: %s\n", pack.Extends))
+	}
+	if len(pack.Errors) > 0 {
+		detail.WriteString(errorStyle.Render("\nCannot be used:"))
+		for i, problem := range pack.Errors {
+			if i == 8 {
+				detail.WriteString(errorStyle.Render(fmt.Sprintf("\n  … and %d more", len(pack.Errors)-i)))
+				break
+			}
+			detail.WriteString(errorStyle.Render("\n  • " + problem))
+		}
+	} else {
+		detail.WriteString(fmt.Sprintf("\n%d classes • %d races • %d backgrounds\n%d weapons • %d armor • %d equipment packs\n%d spells • %d monsters",
+			len(pack.Classes), len(pack.Races), len(pack.Backgrounds), len(pack.Weapons), len(pack.Armor), len(pack.EquipmentPacks), len(pack.Spells), len(pack.Monsters)))
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, menu, "  ", detailStyle.Render(detail.String()))
+
+	elements := []string{title, body}
+	if m.rpgPackMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.rpgPackMessage))
+	}
+	elements = append(elements, helpStyle.Render(fmt.Sprintf("↑/↓ to select • Enter to use • R to reload • ESC to go back\nAdd packs as .json files (YAML is not supported) in %s", getPacksDir())))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
@@ -0,0 +1,632 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var partyRoles = []string{"tank", "healer", "damage", "utility"}
+
+const (
+	minPartySize     = 2
+	maxPartySize     = 8
+	defaultPartySize = 4
+)
+
+// partyRule decides which role each seat in the party is filled for.
+// "any" leaves a seat open to every class.
+type partyRule struct {
+	Name        string
+	Description string
+	plan        func(size int, custom string) []string
+}
+
+var partyRules = []partyRule{
+	{
+		Name:        "Balanced",
+		Description: "A tank, a healer, damage and utility before anything doubles up",
+		plan: func(size int, custom string) []string {
+			return cycleRoles([]string{"tank", "healer", "damage", "utility", "damage", "utility", "tank", "healer"}, size)
+		},
+	},
+	{
+		Name:        "Hard hitters",
+		Description: "A tank and a healer, then as much damage as possible",
+		plan: func(size int, custom string) []string {
+			return append([]string{"tank", "healer"}, cycleRoles([]string{"damage"}, size-2)...)[:size]
+		},
+	},
+	{
+		Name:        "Any classes",
+		Description: "No roles, just different classes",
+		plan: func(size int, custom string) []string {
+			return cycleRoles([]string{"any"}, size)
+		},
+	},
+	{
+		Name:        "Custom",
+		Description: "Your own roles, in order; seats past the list are open",
+		plan: func(size int, custom string) []string {
+			roles, _ := parsePartyPlan(custom)
+			for len(roles) < size {
+				roles = append(roles, "any")
+			}
+			return roles[:size]
+		},
+	},
+}
+
+func cycleRoles(pattern []string, size int) []string {
+	roles := make([]string, max(size, 0))
+	for i := range roles {
+		roles[i] = pattern[i%len(pattern)]
+	}
+	return roles
+}
+
+// parsePartyPlan reads a custom plan such as "tank, healer, any", returning
+// an error for anything that isn't a role.
+func parsePartyPlan(plan string) ([]string, error) {
+	var roles []string
+	for _, role := range strings.FieldsFunc(strings.ToLower(plan), func(r rune) bool { return r == ',' || r == ' ' }) {
+		if role != "any" && indexOf(partyRoles, role) < 0 {
+			return roles, fmt.Errorf("unknown role %q (use %s or any)", role, strings.Join(partyRoles, ", "))
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func findPartyRule(name string) int {
+	for i, rule := range partyRules {
+		if rule.Name == name {
+			return i
+		}
+	}
+	return 0
+}
+
+func (pack ContentPack) getClassRoles(className string) []string {
+	for _, class := range pack.Classes {
+		if class.Name == className {
+			return class.Roles
+		}
+	}
+	return nil
+}
+
+// pickUnused picks at random from the options not yet used, or from all of
+// them once every option has been used.
+func pickUnused(options []string, used []string) string {
+	var fresh []string
+	for _, option := range options {
+		if indexOf(used, option) < 0 {
+			fresh = append(fresh, option)
+		}
+	}
+	if len(fresh) == 0 {
+		fresh = options
+	}
+	if len(fresh) == 0 {
+		return ""
+	}
+	return fresh[rand.Intn(len(fresh))]
+}
+
+// partyClassFor picks a class for a role that no one else in the party has:
+// one whose best role it is if possible, then one that can fill it, then any
+// unused class. Classes repeat only once the pack runs out.
+func (pack ContentPack) partyClassFor(role string, used []string) string {
+	if role == "any" {
+		return pickUnused(pack.classNames(), used)
+	}
+	var best, able []string
+	for _, class := range pack.Classes {
+		if indexOf(used, class.Name) >= 0 {
+			continue
+		}
+		if i := indexOf(class.Roles, role); i == 0 {
+			best = append(best, class.Name)
+		} else if i > 0 {
+			able = append(able, class.Name)
+		}
+	}
+	for _, options := range [][]string{best, able} {
+		if len(options) > 0 {
+			return options[rand.Intn(len(options))]
+		}
+	}
+	return pickUnused(pack.classNames(), used)
+}
+
+func (pack ContentPack) classNames() []string {
+	var names []string
+	for _, class := range pack.Classes {
+		names = append(names, class.Name)
+	}
+	return names
+}
+
+func (pack ContentPack) raceNames() []string {
+	var names []string
+	for _, race := range pack.Races {
+		names = append(names, race.Name)
+	}
+	return names
+}
+
+func (pack ContentPack) backgroundNames() []string {
+	var names []string
+	for _, background := range pack.Backgrounds {
+		names = append(names, background.Name)
+	}
+	return names
+}
+
+// generatePartyMember makes a complete character for a seat, going through
+// the same scores, equipment, name and personality steps as the creator.
+// Classes, races and backgrounds already in the party are avoided.
+func (pack ContentPack) generatePartyMember(role string, method int, others []Character) Character {
+	var classes, races, backgrounds []string
+	for _, other := range others {
+		classes = append(classes, other.Class)
+		races = append(races, other.Race)
+		backgrounds = append(backgrounds, other.Background)
+	}
+
+	className := pack.partyClassFor(role, classes)
+	race := pickUnused(pack.raceNames(), races)
+	background := pickUnused(pack.backgroundNames(), backgrounds)
+	values, _ := pack.generateAbilityValues(method, className)
+
+	c := pack.buildCharacter(race, className, background, abilityScoreMap(values), EquipmentSelection{})
+	c.Method = abilityMethodNames[method]
+	c.Name = pack.generateCharacterName(race)
+	return pack.rollPersonality(c)
+}
+
+// generateParty fills every seat of a plan in turn.
+func (pack ContentPack) generateParty(plan []string, method int) []Character {
+	var party []Character
+	for _, role := range plan {
+		party = append(party, pack.generatePartyMember(role, method, party))
+	}
+	return party
+}
+
+// partyMemberRole is the role a member plays: the one their seat asked for,
+// or their class's best role for an open seat.
+func (pack ContentPack) partyMemberRole(c Character, seat string) string {
+	if seat != "any" && seat != "" {
+		return seat
+	}
+	if roles := pack.getClassRoles(c.Class); len(roles) > 0 {
+		return roles[0]
+	}
+	return "any"
+}
+
+// partyKeyStat shows a character's primary ability, e.g. "STR 16 (+3)".
+func (pack ContentPack) partyKeyStat(c Character) string {
+	primary := pack.getClassStats(c.Class).Primary
+	if primary == "" {
+		return ""
+	}
+	score := c.Abilities[primary]
+	return fmt.Sprintf("%s %d (%s)", strings.ToUpper(primary[:3]), score, formatModifier(abilityModifier(score)))
+}
+
+var partySummaryHeader = []string{"Name", "Class", "Role", "Race", "HP", "AC", "Init", "Key stat"}
+
+// partySummaryRows lists each member's name, class, role, race, HP, AC,
+// initiative and primary ability, for the party table and its exports.
+func (pack ContentPack) partySummaryRows(party []Character, seats []string) [][]string {
+	var rows [][]string
+	for i, c := range party {
+		derived := pack.deriveStats(c)
+		seat := ""
+		if i < len(seats) {
+			seat = seats[i]
+		}
+		rows = append(rows, []string{
+			characterDisplayName(c), c.Class, pack.partyMemberRole(c, seat), c.Race,
+			fmt.Sprint(derived.HitPoints), fmt.Sprint(derived.ArmorClass), formatModifier(derived.Initiative), pack.partyKeyStat(c),
+		})
+	}
+	return rows
+}
+
+// partyTotals sums up the party: total HP, average AC and any role no one
+// covers.
+func (pack ContentPack) partyTotals(party []Character, seats []string) string {
+	hp, ac := 0, 0
+	covered := make(map[string]bool)
+	for i, c := range party {
+		derived := pack.deriveStats(c)
+		hp += derived.HitPoints
+		ac += derived.ArmorClass
+		for _, role := range pack.getClassRoles(c.Class) {
+			covered[role] = true
+		}
+		if i < len(seats) {
+			covered[seats[i]] = true
+		}
+	}
+	summary := fmt.Sprintf("Total HP %d • Average AC %.1f", hp, float64(ac)/float64(max(len(party), 1)))
+	var missing []string
+	for _, role := range partyRoles {
+		if !covered[role] {
+			missing = append(missing, role)
+		}
+	}
+	if len(missing) > 0 {
+		summary += " • No " + strings.Join(missing, " or ")
+	}
+	return summary
+}
+
+// formatPartyTable lays out the summary as fixed-width text.
+func (pack ContentPack) formatPartyTable(party []Character, seats []string) string {
+	format := "%-18.18s %-10.10s %-8s %-18.18s %3s %3s %4s  %s"
+	lines := []string{fmt.Sprintf(format, toAny(partySummaryHeader)...)}
+	for _, row := range pack.partySummaryRows(party, seats) {
+		lines = append(lines, fmt.Sprintf(format, toAny(row)...))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func toAny(values []string) []any {
+	out := make([]any, len(values))
+	for i, value := range values {
+		out[i] = value
+	}
+	return out
+}
+
+// partyExportFormats are the formats a whole party can be exported in as a
+// single file.
+var partyExportFormats = []string{"txt", "md", "json", "pdf"}
+
+// renderParty renders the party summary followed by every member's sheet.
+func (pack ContentPack) renderParty(party []Character, seats []string, format exportFormat) ([]byte, error) {
+	var sheets []characterSheet
+	for _, c := range party {
+		sheets = append(sheets, pack.buildCharacterSheet(c))
+	}
+	title := fmt.Sprintf("Party of %d", len(party))
+
+	switch format.Extension {
+	case "json":
+		type member struct {
+			Role string `json:"role"`
+			characterSheet
+		}
+		export := struct {
+			Party   string   `json:"party"`
+			Members []member `json:"members"`
+		}{Party: title}
+		for i, sheet := range sheets {
+			export.Members = append(export.Members, member{Role: pack.partyMemberRole(party[i], seats[i]), characterSheet: sheet})
+		}
+		data, err := json.MarshalIndent(export, "", "  ")
+		return append(data, '\n'), err
+
+	case "md":
+		var out strings.Builder
+		out.WriteString("# " + title + "\n\n")
+		out.WriteString("| " + strings.Join(partySummaryHeader, " | ") + " |\n")
+		out.WriteString(strings.Repeat("|---", len(partySummaryHeader)) + "|\n")
+		for _, row := range pack.partySummaryRows(party, seats) {
+			row[0] = escapeMarkdown(row[0])
+			out.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		}
+		out.WriteString("\n" + pack.partyTotals(party, seats) + "\n")
+		for _, sheet := range sheets {
+			text, err := renderMarkdownSheet(sheet)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString("\n---\n\n")
+			out.Write(text)
+		}
+		return []byte(out.String()), nil
+	}
+
+	// Text, and PDF printed from it with each sheet on its own page
+	separator := "\n\n"
+	if format.Extension == "pdf" {
+		separator = "\f"
+	}
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("%s\n%s\n\n%s\n\n%s\n", strings.ToUpper(title), strings.Repeat("=", len(title)), pack.formatPartyTable(party, seats), pack.partyTotals(party, seats)))
+	for _, sheet := range sheets {
+		text, err := renderTextSheet(sheet)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(separator)
+		out.Write(text)
+	}
+	if format.Extension == "pdf" {
+		return renderTextPDF(out.String()), nil
+	}
+	return []byte(out.String()), nil
+}
+
+// exportParty writes the whole party to one file in the export folder.
+func (pack ContentPack) exportParty(party []Character, seats []string, format exportFormat) (string, error) {
+	data, err := pack.renderParty(party, seats, format)
+	if err != nil {
+		return "", err
+	}
+	dir := getExportDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	filename := filepath.Join(dir, fmt.Sprintf("Party_%s.%s", time.Now().Format("2006-01-02_15-04-05"), format.Extension))
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// openRPGParty shows the party generator with the saved size and rule,
+// generating a party if there isn't one yet.
+func (m model) openRPGParty() model {
+	var err error
+	m.rpgPartySettings, err = loadRPGSettings()
+	if m.rpgPartySettings.PartySize == 0 {
+		m.rpgPartySettings.PartySize = defaultPartySize
+	}
+	m.rpgPartyEditing = false
+	m.rpgPartyMessage = ""
+	if err != nil {
+		m.rpgPartyMessage = "❌ " + err.Error()
+	}
+	m.state = rpgPartyView
+	if len(m.rpgParty) == 0 {
+		m = m.generateRPGParty()
+	}
+	return m
+}
+
+func (m model) rpgPartyRule() partyRule {
+	return partyRules[findPartyRule(m.rpgPartySettings.PartyRule)]
+}
+
+func (m model) generateRPGParty() model {
+	seats := m.rpgPartyRule().plan(m.rpgPartySettings.PartySize, m.rpgPartySettings.PartyPlan)
+	m.rpgParty = m.rpgPack.generateParty(seats, m.rpgAbilityMethod)
+	m.rpgPartyRoles = seats
+	m.rpgPartyCursor = 0
+	return m
+}
+
+// saveRPGPartySettings remembers the size and rule, reporting a failure on
+// the party screen.
+func (m model) saveRPGPartySettings() model {
+	settings, _ := loadRPGSettings() // saveRPGSettings reports a damaged file
+	settings.PartySize = m.rpgPartySettings.PartySize
+	settings.PartyRule = m.rpgPartySettings.PartyRule
+	settings.PartyPlan = m.rpgPartySettings.PartyPlan
+	if err := saveRPGSettings(settings); err != nil {
+		m.rpgPartyMessage = "❌ Could not save party settings: " + err.Error()
+	}
+	return m
+}
+
+func (m model) updateRPGParty(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.rpgPartyEditing {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.rpgPartyEditing = false
+		case "backspace":
+			if len(m.rpgPartyInput) > 0 {
+				m.rpgPartyInput = m.rpgPartyInput[:len(m.rpgPartyInput)-1]
+			}
+		case "enter":
+			if _, err := parsePartyPlan(m.rpgPartyInput); err != nil {
+				m.rpgPartyMessage = "❌ " + err.Error()
+				return m, nil
+			}
+			m.rpgPartySettings.PartyPlan = strings.TrimSpace(m.rpgPartyInput)
+			m.rpgPartySettings.PartyRule = "Custom"
+			m.rpgPartyEditing = false
+			m.rpgPartyMessage = ""
+			m = m.saveRPGPartySettings().generateRPGParty()
+		default:
+			if len(keyMsg.String()) == 1 {
+				m.rpgPartyInput += keyMsg.String()
+			}
+		}
+		return m, nil
+	}
+
+	m.rpgPartyMessage = ""
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = rpgRaceSelectionView
+	case "up", "k":
+		if m.rpgPartyCursor > 0 {
+			m.rpgPartyCursor--
+		}
+	case "down", "j":
+		if m.rpgPartyCursor < len(m.rpgParty)-1 {
+			m.rpgPartyCursor++
+		}
+	case "+", "=":
+		if m.rpgPartySettings.PartySize < maxPartySize {
+			m.rpgPartySettings.PartySize++
+			m = m.saveRPGPartySettings().generateRPGParty()
+		}
+	case "-":
+		if m.rpgPartySettings.PartySize > minPartySize {
+			m.rpgPartySettings.PartySize--
+			m = m.saveRPGPartySettings().generateRPGParty()
+		}
+	case "left", "h", "right", "l":
+		i := findPartyRule(m.rpgPartySettings.PartyRule)
+		if keyMsg.String() == "left" || keyMsg.String() == "h" {
+			i = (i + len(partyRules) - 1) % len(partyRules)
+		} else {
+			i = (i + 1) % len(partyRules)
+		}
+		m.rpgPartySettings.PartyRule = partyRules[i].Name
+		m = m.saveRPGPartySettings().generateRPGParty()
+	case "c":
+		m.rpgPartyInput = m.rpgPartySettings.PartyPlan
+		m.rpgPartyEditing = true
+	case "m":
+		m.rpgAbilityMethod = (m.rpgAbilityMethod + 1) % len(abilityMethodNames)
+		m = m.generateRPGParty()
+	case "g":
+		m = m.generateRPGParty()
+	case "r", " ":
+		others := append(append([]Character{}, m.rpgParty[:m.rpgPartyCursor]...), m.rpgParty[m.rpgPartyCursor+1:]...)
+		m.rpgParty[m.rpgPartyCursor] = m.rpgPack.generatePartyMember(m.rpgPartyRoles[m.rpgPartyCursor], m.rpgAbilityMethod, others)
+	case "enter":
+		m = m.loadRPGCharacter(m.rpgParty[m.rpgPartyCursor])
+	case "w":
+		roster, err := loadCharacters()
+		if err != nil {
+			m.rpgPartyMessage = "❌ Save failed: " + err.Error()
+			return m, nil
+		}
+		for i := range m.rpgParty {
+			roster, m.rpgParty[i] = storeCharacter(roster, m.rpgParty[i])
+		}
+		if err := saveCharacters(roster); err != nil {
+			m.rpgPartyMessage = "❌ Save failed: " + err.Error()
+		} else {
+			m.rpgRoster = roster
+			m.rpgPartyMessage = fmt.Sprintf("✅ Saved %d characters to the roster", len(m.rpgParty))
+		}
+	case "f":
+		m.rpgPartyFormat = (m.rpgPartyFormat + 1) % len(partyExportFormats)
+	case "x":
+		format := findExportFormat(partyExportFormats[m.rpgPartyFormat])
+		if filename, err := m.rpgPack.exportParty(m.rpgParty, m.rpgPartyRoles, format); err != nil {
+			m.rpgPartyMessage = fmt.Sprintf("❌ %s export failed: %v", format.Name, err)
+		} else {
+			m.rpgPartyMessage = "✅ Party saved to " + filename
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewRPGParty() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(90).
+		AlignHorizontal(lipgloss.Center)
+
+	tableStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(90)
+
+	detailStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#10B981")).
+		Padding(0, 2).
+		MarginBottom(1).
+		Width(90)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#10B981"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981"))
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#10B981")).
+		Padding(0, 1).
+		MarginBottom(1).
+		Width(90)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(90)
+
+	rule := m.rpgPartyRule()
+	title := titleStyle.Render(fmt.Sprintf("🛡️  Party Generator • %d characters • %s", len(m.rpgParty), rule.Name))
+
+	tableLines := strings.Split(m.rpgPack.formatPartyTable(m.rpgParty, m.rpgPartyRoles), "\n")
+	var rows []string
+	for i, line := range tableLines {
+		switch {
+		case i == 0:
+			rows = append(rows, headerStyle.Render("  "+line))
+		case i-1 == m.rpgPartyCursor:
+			rows = append(rows, selectedStyle.Render("▶ "+line))
+		default:
+			rows = append(rows, "  "+line)
+		}
+	}
+	description := rule.Description
+	if rule.Name == "Custom" {
+		description += fmt.Sprintf(" (%s)", orDefault(m.rpgPartySettings.PartyPlan, "none set, C to set"))
+	}
+	rows = append(rows, "", "  "+m.rpgPack.partyTotals(m.rpgParty, m.rpgPartyRoles), "  "+description, "  Ability scores: "+abilityMethodNames[m.rpgAbilityMethod])
+	table := tableStyle.Render(strings.Join(rows, "\n"))
+
+	var detail string
+	if len(m.rpgParty) > 0 {
+		c := m.rpgParty[m.rpgPartyCursor]
+		detail = fmt.Sprintf("%s • %s • %s\n%s", characterDisplayName(c), c.Background, strings.Join(append(append([]string{}, c.Gear.Weapons...), c.Gear.Armor...), ", "), c.Backstory)
+		detail = detailStyle.Render(detail)
+	}
+
+	elements := []string{title, table, detail}
+	if m.rpgPartyEditing {
+		elements = append(elements, inputStyle.Render("Roles in order (tank, healer, damage, utility or any): "+m.rpgPartyInput+"█"))
+	}
+	if m.rpgPartyMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.rpgPartyMessage))
+	}
+	if m.rpgPartyEditing {
+		elements = append(elements, helpStyle.Render("Enter to use these roles • ESC to cancel"))
+	} else {
+		format := findExportFormat(partyExportFormats[m.rpgPartyFormat])
+		elements = append(elements, helpStyle.Render(fmt.Sprintf("↑/↓ select • R reroll member • G new party • +/- size • ←/→ rule • C custom roles\nM score method • Enter open on sheet • W save all • F format (%s) • X export • ESC back", format.Name)))
+	}
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
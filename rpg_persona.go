@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The parts of a character's persona, in the order the persona screen lists
+// them. Each can be rerolled on its own.
+var personaFields = []string{"Name", "Trait", "Trait", "Ideal", "Bond", "Flaw", "Backstory"}
+
+var hookPlaceholders = []string{"{name}", "{race}", "{class}", "{npc}", "{years}"}
+
+// raceNameTables returns the race's name tables, or every race's names together
+// for a race that has none, so homebrew races still get names.
+func (pack ContentPack) raceNameTables(raceName string) NameTables {
+	if names := pack.getRaceStats(raceName).Names; len(names.First) > 0 {
+		return names
+	}
+	var all NameTables
+	for _, race := range pack.Races {
+		all.First = append(all.First, race.Names.First...)
+		all.Family = append(all.Family, race.Names.Family...)
+	}
+	return all
+}
+
+// markovName invents a name from an order-2 character chain trained on a
+// list of names, so it sounds like the list without copying it. Names that
+// come out too short, too long or already on the list are thrown back; after
+// enough tries it settles for a name from the list.
+func markovName(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	type state [2]rune
+	const start, end = '^', '$'
+	chain := make(map[state][]rune)
+	known := make(map[string]bool)
+	shortest, longest := 100, 0
+	for _, name := range names {
+		runes := []rune(strings.ToLower(name))
+		known[string(runes)] = true
+		shortest, longest = min(shortest, len(runes)), max(longest, len(runes))
+		s := state{start, start}
+		for _, r := range append(runes, end) {
+			chain[s] = append(chain[s], r)
+			s = state{s[1], r}
+		}
+	}
+
+	for try := 0; try < 50; try++ {
+		var out []rune
+		s := state{start, start}
+		for len(out) <= longest {
+			next := chain[s][rand.Intn(len(chain[s]))]
+			if next == end {
+				break
+			}
+			out = append(out, next)
+			s = state{s[1], next}
+		}
+		name := string(out)
+		if len(out) >= max(3, shortest) && len(out) <= longest && !known[name] {
+			return capitalizeName(name)
+		}
+	}
+	return names[rand.Intn(len(names))]
+}
+
+// capitalizeName capitalizes each part of a name, including after hyphens
+// and apostrophes, as in "High-Hill".
+func capitalizeName(name string) string {
+	runes := []rune(name)
+	for i, r := range runes {
+		if i == 0 || runes[i-1] == '-' || runes[i-1] == '\'' || runes[i-1] == ' ' {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
+
+// generateCharacterName makes up a first name and, for races with family
+// names, a family name. A race with alternate names uses one of them
+// instead about a third of the time.
+func (pack ContentPack) generateCharacterName(raceName string) string {
+	names := pack.raceNameTables(raceName)
+	if len(names.Alternate) > 0 && rand.Intn(3) == 0 {
+		return names.Alternate[rand.Intn(len(names.Alternate))]
+	}
+	name := markovName(names.First)
+	if family := markovName(names.Family); family != "" {
+		name += " " + family
+	}
+	return name
+}
+
+// backgroundPersonality returns the background's personality tables. Any
+// table the background leaves empty is filled from every background in the
+// pack.
+func (pack ContentPack) backgroundPersonality(backgroundName string) PersonalityTables {
+	tables := pack.getBackgroundStats(backgroundName).Personality
+	fill := func(table *[]string, from func(PersonalityTables) []string) {
+		if len(*table) > 0 {
+			return
+		}
+		for _, background := range pack.Backgrounds {
+			*table = append(*table, from(background.Personality)...)
+		}
+	}
+	fill(&tables.Traits, func(t PersonalityTables) []string { return t.Traits })
+	fill(&tables.Ideals, func(t PersonalityTables) []string { return t.Ideals })
+	fill(&tables.Bonds, func(t PersonalityTables) []string { return t.Bonds })
+	fill(&tables.Flaws, func(t PersonalityTables) []string { return t.Flaws })
+	fill(&tables.Hooks, func(t PersonalityTables) []string { return t.Hooks })
+	return tables
+}
+
+// rollTable picks an entry from a table, avoiding the entries given where
+// the table has others.
+func rollTable(table []string, avoid ...string) string {
+	var options []string
+	for _, entry := range table {
+		if indexOf(avoid, entry) < 0 {
+			options = append(options, entry)
+		}
+	}
+	if len(options) == 0 {
+		options = table
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return options[rand.Intn(len(options))]
+}
+
+// backstoryHook rolls a hook from the background and fills in its
+// placeholders.
+func (pack ContentPack) backstoryHook(c Character, avoid string) string {
+	hook := rollTable(pack.backgroundPersonality(c.Background).Hooks, avoid)
+	name := c.Name
+	if name == "" {
+		name = "the " + c.Race
+	}
+	return strings.NewReplacer(
+		"{name}", name,
+		"{race}", c.Race,
+		"{class}", c.Class,
+		"{npc}", pack.generateCharacterName(c.Race),
+		"{years}", strconv.Itoa(2+rand.Intn(11)),
+	).Replace(hook)
+}
+
+// rerollPersona rolls one persona field again, by its index in
+// personaFields. A new name is also put into the backstory in place of the
+// old one.
+func (pack ContentPack) rerollPersona(c Character, field int) Character {
+	tables := pack.backgroundPersonality(c.Background)
+	switch personaFields[field] {
+	case "Name":
+		previous := c.Name
+		c.Name = pack.generateCharacterName(c.Race)
+		if previous != "" && c.Backstory != "" {
+			c.Backstory = strings.ReplaceAll(c.Backstory, previous, c.Name)
+		}
+	case "Trait":
+		traits := append([]string{}, c.Traits...)
+		for len(traits) < 2 {
+			traits = append(traits, "")
+		}
+		traits[field-1] = rollTable(tables.Traits, traits...)
+		c.Traits = traits
+	case "Ideal":
+		c.Ideal = rollTable(tables.Ideals, c.Ideal)
+	case "Bond":
+		c.Bond = rollTable(tables.Bonds, c.Bond)
+	case "Flaw":
+		c.Flaw = rollTable(tables.Flaws, c.Flaw)
+	case "Backstory":
+		c.Backstory = pack.backstoryHook(c, c.Backstory)
+	}
+	return c
+}
+
+// rollPersonality rolls two traits, an ideal, a bond, a flaw and a backstory
+// hook, keeping the character's name.
+func (pack ContentPack) rollPersonality(c Character) Character {
+	c.Traits, c.Ideal, c.Bond, c.Flaw, c.Backstory = nil, "", "", "", ""
+	for i := range personaFields {
+		if personaFields[i] != "Name" {
+			c = pack.rerollPersona(c, i)
+		}
+	}
+	return c
+}
+
+// personaValue is the current text of a persona field.
+func personaValue(c Character, field int) string {
+	switch personaFields[field] {
+	case "Name":
+		return c.Name
+	case "Trait":
+		if i := field - 1; i < len(c.Traits) {
+			return c.Traits[i]
+		}
+	case "Ideal":
+		return c.Ideal
+	case "Bond":
+		return c.Bond
+	case "Flaw":
+		return c.Flaw
+	case "Backstory":
+		return c.Backstory
+	}
+	return ""
+}
+
+func (m model) openRPGPersona() model {
+	m.rpgPersonaCursor = 0
+	m.rpgExportStatus = ""
+	m.state = rpgPersonaView
+	return m
+}
+
+func (m model) updateRPGPersona(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "enter", "n":
+		m.state = rpgCharacterView
+		m = m.autosaveRPGCharacter(fmt.Sprintf("✅ Persona updated for %s", characterDisplayName(m.rpgCharacter)))
+	case "up", "k":
+		if m.rpgPersonaCursor > 0 {
+			m.rpgPersonaCursor--
+		}
+	case "down", "j":
+		if m.rpgPersonaCursor < len(personaFields)-1 {
+			m.rpgPersonaCursor++
+		}
+	case "r", " ":
+		m.rpgCharacter = m.rpgPack.rerollPersona(m.rpgCharacter, m.rpgPersonaCursor)
+	case "a":
+		m.rpgCharacter = m.rpgPack.rerollPersona(m.rpgCharacter, 0)
+		m.rpgCharacter = m.rpgPack.rollPersonality(m.rpgCharacter)
+	}
+	return m, nil
+}
+
+func (m model) viewRPGPersona() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(78).
+		AlignHorizontal(lipgloss.Center)
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(78)
+
+	labelStyle := lipgloss.NewStyle().
+		Width(12)
+
+	valueStyle := lipgloss.NewStyle().
+		Width(58)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981"))
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8B5CF6"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(78)
+
+	c := m.rpgCharacter
+	title := titleStyle.Render(fmt.Sprintf("🎭 Name & Backstory • %s %s %s", c.Race, c.Class, c.Background))
+
+	var rows []string
+	for i, field := range personaFields {
+		label := "  " + field
+		style := normalStyle
+		if i == m.rpgPersonaCursor {
+			label = "▶ " + field
+			style = selectedStyle
+		}
+		if field == "Backstory" {
+			rows = append(rows, "")
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, style.Render(labelStyle.Render(label)), "  ", valueStyle.Render(personaValue(c, i))))
+	}
+
+	help := helpStyle.Render("↑/↓ to select • R or Space to reroll • A to reroll everything • Enter or ESC when done")
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, title, panelStyle.Render(strings.Join(rows, "\n")), help))
+}
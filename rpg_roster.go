@@ -0,0 +1,681 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxCharacterLevel = 20
+
+// XP needed to reach each level; index 0 is level 1.
+var xpThresholds = []int{0, 300, 900, 2700, 6500, 14000, 23000, 34000, 48000, 64000, 85000, 100000, 120000, 140000, 165000, 195000, 225000, 265000, 305000, 355000}
+
+var alignments = []string{"", "Lawful Good", "Neutral Good", "Chaotic Good", "Lawful Neutral", "True Neutral", "Chaotic Neutral", "Lawful Evil", "Neutral Evil", "Chaotic Evil"}
+
+// Fields in the character editor, in order.
+var rpgEditLabels = []string{"Name", "Alignment", "XP", "Gold", "Weapons", "Armor", "Equipment", "Notes"}
+
+const (
+	rpgEditName = iota
+	rpgEditAlignment
+	rpgEditXP
+	rpgEditGold
+	rpgEditWeapons
+	rpgEditArmor
+	rpgEditEquipment
+	rpgEditNotes
+)
+
+type charactersFile struct {
+	Characters []Character `json:"characters"`
+}
+
+func getCharactersFilePath() string {
+	return filepath.Join(getDataDir(), "characters.json")
+}
+
+// loadCharacters reads the roster. A missing file is an empty roster, but a
+// file that can't be read is an error, and saveCharacters won't write over it.
+func loadCharacters() ([]Character, error) {
+	data, err := os.ReadFile(getCharactersFilePath())
+	if os.IsNotExist(err) {
+		return []Character{}, nil
+	}
+	if err != nil {
+		return []Character{}, err
+	}
+
+	var file charactersFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return []Character{}, fmt.Errorf("%s is damaged (%s) • fix or move it to save characters again", getCharactersFilePath(), describeJSONError(data, 0, err))
+	}
+	return file.Characters, nil
+}
+
+func saveCharacters(characters []Character) error {
+	if _, err := loadCharacters(); err != nil {
+		return err
+	}
+	sort.SliceStable(characters, func(i, j int) bool {
+		return strings.ToLower(characterDisplayName(characters[i])) < strings.ToLower(characterDisplayName(characters[j]))
+	})
+	data, err := json.MarshalIndent(charactersFile{Characters: characters}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getCharactersFilePath(), data, 0644)
+}
+
+// findCharacter returns the index of the roster entry with the given ID, or -1.
+func findCharacter(characters []Character, id string) int {
+	for i, c := range characters {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// storeCharacter saves c in the roster, replacing its old entry, and returns
+// the roster and the stored character, which gets an ID if it had none.
+func storeCharacter(characters []Character, c Character) ([]Character, Character) {
+	// Several characters can be stored within one clock tick, so step past
+	// IDs already in use
+	for id := time.Now().UnixNano(); c.ID == ""; id++ {
+		if findCharacter(characters, strconv.FormatInt(id, 36)) < 0 {
+			c.ID = strconv.FormatInt(id, 36)
+		}
+	}
+	if i := findCharacter(characters, c.ID); i >= 0 {
+		characters[i] = c
+		return characters, c
+	}
+	return append(characters, c), c
+}
+
+// characterDisplayName is the character's name, or a description for an
+// unnamed one.
+func characterDisplayName(c Character) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return fmt.Sprintf("Unnamed %s %s", c.Race, c.Class)
+}
+
+// nextLevelXP returns the XP needed for the next level, or 0 at the cap.
+func nextLevelXP(level int) int {
+	if level >= maxCharacterLevel {
+		return 0
+	}
+	return xpThresholds[max(level, 1)]
+}
+
+// levelUpCharacter raises a character one level, adding a hit die result and
+// bringing XP up to the new level's threshold.
+func levelUpCharacter(c Character, hitDieResult int) Character {
+	c.Level = max(c.Level, 1) + 1
+	c.HitDieRolls = append(append([]int{}, c.HitDieRolls...), hitDieResult)
+	c.XP = max(c.XP, xpThresholds[c.Level-1])
+	return c
+}
+
+// splitGearList parses a comma separated list of gear, dropping blanks.
+func splitGearList(text string) []string {
+	items := []string{}
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// saveRPGCharacter writes the current character to the roster.
+func (m model) saveRPGCharacter() (model, error) {
+	roster, err := loadCharacters()
+	if err != nil {
+		return m, err
+	}
+	m.rpgRoster, m.rpgCharacter = storeCharacter(roster, m.rpgCharacter)
+	err = saveCharacters(m.rpgRoster)
+	return m, err
+}
+
+// autosaveRPGCharacter saves the character after a change if it is already in
+// the roster, and reports the outcome in the status line.
+func (m model) autosaveRPGCharacter(status string) model {
+	if m.rpgCharacter.ID == "" {
+		m.rpgExportStatus = status + " • W to save to the roster"
+		return m
+	}
+	saved, err := m.saveRPGCharacter()
+	if err != nil {
+		m.rpgExportStatus = "❌ Save failed: " + err.Error()
+		return m
+	}
+	saved.rpgExportStatus = status
+	return saved
+}
+
+// loadRPGCharacter makes c the current character and points the wizard at
+// its choices so rerolls and rearranging work from where it left off.
+func (m model) loadRPGCharacter(c Character) model {
+	m.rpgCharacter = c
+	m.rpgRaceCursor = max(0, indexOf(m.rpgRaces, c.Race))
+	m.rpgClassCursor = max(0, indexOf(m.rpgClasses, c.Class))
+	m.rpgBackgroundCursor = max(0, indexOf(m.rpgBackgrounds, c.Background))
+	if c.Method != "" {
+		m.rpgAbilityMethod = max(0, indexOf(abilityMethodNames, c.Method))
+	}
+	m.rpgEquipment = EquipmentSelection{Wealth: c.Wealth != ""}
+
+	// Base scores are the final scores less the racial increases
+	m.rpgScoreValues = make([]int, len(abilityNames))
+	for i, ability := range abilityNames {
+		m.rpgScoreValues[i] = c.Abilities[ability] - c.RaceBonus[ability]
+	}
+	m.rpgScoreRolls = nil
+	m.rpgExportStatus = ""
+	m.state = rpgCharacterView
+	return m
+}
+
+// openRPGRoster shows the roster, returning to the current screen on ESC.
+func (m model) openRPGRoster() model {
+	var err error
+	m.rpgRoster, err = loadCharacters()
+	m.rpgRosterCursor = max(0, findCharacter(m.rpgRoster, m.rpgCharacter.ID))
+	m.rpgRosterRenaming = false
+	m.rpgRosterMessage = ""
+	if err != nil {
+		m.rpgRosterMessage = "❌ " + err.Error()
+	}
+	m.rpgRosterReturn = m.state
+	m.state = rpgRosterView
+	return m
+}
+
+func (m model) updateRPGRoster(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.rpgRosterRenaming {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.rpgRosterRenaming = false
+		case "backspace":
+			if len(m.rpgRosterInput) > 0 {
+				m.rpgRosterInput = m.rpgRosterInput[:len(m.rpgRosterInput)-1]
+			}
+		case "enter":
+			name := strings.TrimSpace(m.rpgRosterInput)
+			if name == "" {
+				return m, nil
+			}
+			c := m.rpgRoster[m.rpgRosterCursor]
+			c.Name = name
+			m.rpgRoster, c = storeCharacter(m.rpgRoster, c)
+			if err := saveCharacters(m.rpgRoster); err != nil {
+				m.rpgRosterMessage = fmt.Sprintf("❌ Rename failed: %v", err)
+			} else {
+				m.rpgRosterMessage = fmt.Sprintf("✅ Renamed to %q", name)
+			}
+			if m.rpgCharacter.ID == c.ID {
+				m.rpgCharacter.Name = name
+			}
+			m.rpgRosterCursor = max(0, findCharacter(m.rpgRoster, c.ID))
+			m.rpgRosterRenaming = false
+		default:
+			if len(keyMsg.String()) == 1 {
+				m.rpgRosterInput += keyMsg.String()
+			}
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "o":
+		m.state = m.rpgRosterReturn
+	case "up", "k":
+		if m.rpgRosterCursor > 0 {
+			m.rpgRosterCursor--
+		}
+	case "down", "j":
+		if m.rpgRosterCursor < len(m.rpgRoster)-1 {
+			m.rpgRosterCursor++
+		}
+	case "enter":
+		if m.rpgRosterCursor < len(m.rpgRoster) {
+			c := m.rpgRoster[m.rpgRosterCursor]
+			if c.Pack != "" && c.Pack != m.rpgPack.ID {
+				i := findContentPack(m.rpgPacks, c.Pack)
+				if i < 0 || len(m.rpgPacks[i].Errors) > 0 {
+					m.rpgRosterMessage = fmt.Sprintf("❌ %s needs the %q pack, which is not available", characterDisplayName(c), c.Pack)
+					return m, nil
+				}
+				m = m.useContentPack(m.rpgPacks[i])
+			}
+			m = m.loadRPGCharacter(c)
+			m.rpgExportStatus = fmt.Sprintf("✅ Loaded %s", characterDisplayName(c))
+		}
+	case "r":
+		if m.rpgRosterCursor < len(m.rpgRoster) {
+			m.rpgRosterRenaming = true
+			m.rpgRosterInput = m.rpgRoster[m.rpgRosterCursor].Name
+			m.rpgRosterMessage = ""
+		}
+	case "c":
+		if m.rpgRosterCursor < len(m.rpgRoster) {
+			c := m.rpgRoster[m.rpgRosterCursor]
+			c.ID = ""
+			c.Name = characterDisplayName(c) + " (copy)"
+			m.rpgRoster, c = storeCharacter(m.rpgRoster, c)
+			if err := saveCharacters(m.rpgRoster); err != nil {
+				m.rpgRosterMessage = fmt.Sprintf("❌ Duplicate failed: %v", err)
+			} else {
+				m.rpgRosterMessage = fmt.Sprintf("✅ Created %q", c.Name)
+			}
+			m.rpgRosterCursor = max(0, findCharacter(m.rpgRoster, c.ID))
+		}
+	case "d":
+		if m.rpgRosterCursor < len(m.rpgRoster) {
+			c := m.rpgRoster[m.rpgRosterCursor]
+			m.rpgRoster = append(m.rpgRoster[:m.rpgRosterCursor], m.rpgRoster[m.rpgRosterCursor+1:]...)
+			if err := saveCharacters(m.rpgRoster); err != nil {
+				m.rpgRosterMessage = fmt.Sprintf("❌ Delete failed: %v", err)
+			} else {
+				m.rpgRosterMessage = fmt.Sprintf("✅ Deleted %q", characterDisplayName(c))
+			}
+			// The loaded copy is now unsaved
+			if m.rpgCharacter.ID == c.ID {
+				m.rpgCharacter.ID = ""
+			}
+			if m.rpgRosterCursor > 0 && m.rpgRosterCursor >= len(m.rpgRoster) {
+				m.rpgRosterCursor--
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewRPGRoster() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(70)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981"))
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3498DB")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(70)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render(fmt.Sprintf("📚 Character Roster (%d)", len(m.rpgRoster)))
+
+	var rows []string
+	if len(m.rpgRoster) == 0 {
+		rows = append(rows, "No saved characters yet.\n\nPress W on a character sheet to save it.")
+	}
+	for i, c := range m.rpgRoster {
+		name := characterDisplayName(c)
+		if len([]rune(name)) > 28 {
+			name = string([]rune(name)[:27]) + "…"
+		}
+		row := fmt.Sprintf("%-28s L%-2d %-30s", name, max(c.Level, 1), c.Race+" "+c.Class)
+		if i == m.rpgRosterCursor {
+			row = selectedStyle.Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	elements := []string{title, listStyle.Render(strings.Join(rows, "\n"))}
+	if m.rpgRosterCursor < len(m.rpgRoster) && !m.rpgRosterRenaming {
+		c := m.rpgRoster[m.rpgRosterCursor]
+		derived := m.rpgPack.deriveStats(c)
+		detail := fmt.Sprintf("%s • %s • XP %d\nHP %d • AC %d • %d gp", c.Background, orDefault(c.Alignment, "No alignment"), c.XP, derived.HitPoints, derived.ArmorClass, c.Gold)
+		elements = append(elements, listStyle.Render(detail))
+	}
+	if m.rpgRosterRenaming {
+		elements = append(elements, inputStyle.Render("Rename character to:\n"+fmt.Sprintf("▶ %s█", m.rpgRosterInput)))
+	}
+	if m.rpgRosterMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.rpgRosterMessage))
+	}
+	elements = append(elements, helpStyle.Render("↑/↓ select • Enter load • R rename • C duplicate • D delete • ESC close"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
+
+// indexOf returns the position of value in options, or -1.
+func indexOf(options []string, value string) int {
+	for i, option := range options {
+		if option == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// orDefault returns value, or fallback when value is empty.
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// openRPGEditor fills the editor fields from the current character.
+func (m model) openRPGEditor() model {
+	c := m.rpgCharacter
+	m.rpgEditFields = []string{
+		c.Name,
+		c.Alignment,
+		strconv.Itoa(c.XP),
+		strconv.Itoa(c.Gold),
+		strings.Join(c.Gear.Weapons, ", "),
+		strings.Join(c.Gear.Armor, ", "),
+		strings.Join(c.Gear.Items, ", "),
+		c.Notes,
+	}
+	m.rpgEditCursor = 0
+	m.rpgEditError = ""
+	m.state = rpgEditView
+	return m
+}
+
+func (m model) updateRPGEdit(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	field := &m.rpgEditFields[m.rpgEditCursor]
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = rpgCharacterView
+		m.rpgExportStatus = "Edit cancelled"
+	case "up", "shift+tab":
+		if m.rpgEditCursor > 0 {
+			m.rpgEditCursor--
+		}
+	case "down", "tab":
+		if m.rpgEditCursor < len(m.rpgEditFields)-1 {
+			m.rpgEditCursor++
+		}
+	case "left", "right":
+		if m.rpgEditCursor == rpgEditAlignment {
+			current := 0
+			for i, alignment := range alignments {
+				if alignment == *field {
+					current = i
+				}
+			}
+			step := 1
+			if keyMsg.String() == "left" {
+				step = len(alignments) - 1
+			}
+			*field = alignments[(current+step)%len(alignments)]
+		}
+	case "backspace":
+		if m.rpgEditCursor != rpgEditAlignment && len(*field) > 0 {
+			runes := []rune(*field)
+			*field = string(runes[:len(runes)-1])
+		}
+	case "enter":
+		xp, err := strconv.Atoi(strings.TrimSpace(m.rpgEditFields[rpgEditXP]))
+		if err != nil || xp < 0 {
+			m.rpgEditError = "XP must be a whole number"
+			m.rpgEditCursor = rpgEditXP
+			return m, nil
+		}
+		gold, err := strconv.Atoi(strings.TrimSpace(m.rpgEditFields[rpgEditGold]))
+		if err != nil || gold < 0 {
+			m.rpgEditError = "Gold must be a whole number"
+			m.rpgEditCursor = rpgEditGold
+			return m, nil
+		}
+
+		c := m.rpgCharacter
+		c.Name = strings.TrimSpace(m.rpgEditFields[rpgEditName])
+		c.Alignment = m.rpgEditFields[rpgEditAlignment]
+		c.XP = xp
+		c.Gold = gold
+		c.Gear.Weapons = splitGearList(m.rpgEditFields[rpgEditWeapons])
+		c.Gear.Armor = splitGearList(m.rpgEditFields[rpgEditArmor])
+		c.Gear.Items = splitGearList(m.rpgEditFields[rpgEditEquipment])
+		c.Notes = strings.TrimSpace(m.rpgEditFields[rpgEditNotes])
+		m.rpgCharacter = c
+		m.state = rpgCharacterView
+		m = m.autosaveRPGCharacter("✅ Character updated")
+	default:
+		if m.rpgEditCursor != rpgEditAlignment && len(keyMsg.String()) == 1 {
+			*field += keyMsg.String()
+		} else if keyMsg.Paste && m.rpgEditCursor != rpgEditAlignment {
+			*field += string(keyMsg.Runes)
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewRPGEdit() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#8B5CF6")).
+		Width(11)
+
+	fieldStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#626262")).
+		Width(52)
+
+	activeFieldStyle := fieldStyle.BorderForeground(lipgloss.Color("#10B981"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render("✏️  Edit " + characterDisplayName(m.rpgCharacter))
+
+	var rows []string
+	for i, label := range rpgEditLabels {
+		value := m.rpgEditFields[i]
+		style := fieldStyle
+		if i == rpgEditAlignment {
+			value = "◀ " + orDefault(value, "No alignment") + " ▶"
+		}
+		if i == m.rpgEditCursor {
+			style = activeFieldStyle
+			if i != rpgEditAlignment {
+				value += "█"
+			}
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Center, labelStyle.Render(label), style.Render(value)))
+	}
+
+	elements := []string{title, strings.Join(rows, "\n")}
+	if m.rpgEditError != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginTop(1).Render("❌ "+m.rpgEditError))
+	}
+	elements = append(elements, helpStyle.MarginTop(1).Render("↑/↓ or Tab to move • ←/→ to change alignment • Gear is comma separated • Enter to save • ESC to cancel"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
+
+func (m model) updateRPGLevelUp(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	hitDie := m.rpgPack.getClassStats(m.rpgCharacter.Class).HitDie
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = rpgCharacterView
+	case "up", "down", "k", "j", "tab":
+		m.rpgLevelUpAverage = !m.rpgLevelUpAverage
+	case "enter", " ":
+		before := m.rpgPack.deriveStats(m.rpgCharacter).HitPoints
+		result := hitDie/2 + 1
+		how := "took the average"
+		if !m.rpgLevelUpAverage {
+			roll, err := rollDiceExpression(fmt.Sprintf("1d%d", hitDie))
+			if err == nil {
+				result = roll.Total
+				how = fmt.Sprintf("rolled %d", result)
+			}
+		}
+		m.rpgCharacter = levelUpCharacter(m.rpgCharacter, result)
+		gained := m.rpgPack.deriveStats(m.rpgCharacter).HitPoints - before
+		status := fmt.Sprintf("✅ Reached level %d: %s on the d%d for +%d HP", m.rpgCharacter.Level, how, hitDie, gained)
+		if m.rpgPack.spellsToChoose(m.rpgCharacter) > 0 {
+			status += " • C to choose new spells"
+		}
+		m.state = rpgCharacterView
+		m = m.autosaveRPGCharacter(status)
+	}
+	return m, nil
+}
+
+func (m model) viewRPGLevelUp() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(60).
+		AlignHorizontal(lipgloss.Center)
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(60)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#10B981")).
+		Padding(0, 1)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8B5CF6")).
+		Padding(0, 1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(60)
+
+	c := m.rpgCharacter
+	level := max(c.Level, 1)
+	hitDie := m.rpgPack.getClassStats(c.Class).HitDie
+	con := abilityModifier(c.Abilities["Constitution"])
+	derived := m.rpgPack.deriveStats(c)
+
+	title := titleStyle.Render(fmt.Sprintf("⬆️  Level Up: %s", characterDisplayName(c)))
+
+	summary := fmt.Sprintf("%s %s • Level %d → %d\nXP %d (level %d needs %d)\nHP %d • Proficiency %s → %s",
+		c.Race, c.Class, level, level+1,
+		c.XP, level+1, xpThresholds[level],
+		derived.HitPoints, formatModifier(proficiencyBonus(level)), formatModifier(proficiencyBonus(level+1)))
+
+	options := []string{
+		fmt.Sprintf("🎲 Roll 1d%d %s CON", hitDie, formatModifier(con)),
+		fmt.Sprintf("📊 Take the average: %d %s CON", hitDie/2+1, formatModifier(con)),
+	}
+	for i, option := range options {
+		if (i == 1) == m.rpgLevelUpAverage {
+			options[i] = selectedStyle.Render("▶ " + option)
+		} else {
+			options[i] = normalStyle.Render("  " + option)
+		}
+	}
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		panelStyle.Render(summary),
+		panelStyle.Render("Hit points for the new level:\n\n"+strings.Join(options, "\n")),
+		helpStyle.Render("↑/↓ to choose • Enter to level up • ESC to cancel")))
+}
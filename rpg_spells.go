@@ -0,0 +1,676 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var spellSchools = []string{
+	"Abjuration", "Conjuration", "Divination", "Enchantment",
+	"Evocation", "Illusion", "Necromancy", "Transmutation",
+}
+
+// fullCasterSlots are a full caster's spell slots at each class level, 1st
+// level slots first. Half casters use the row for half their level, rounded
+// up.
+var fullCasterSlots = [20][]int{
+	{2},
+	{3},
+	{4, 2},
+	{4, 3},
+	{4, 3, 2},
+	{4, 3, 3},
+	{4, 3, 3, 1},
+	{4, 3, 3, 2},
+	{4, 3, 3, 3, 1},
+	{4, 3, 3, 3, 2},
+	{4, 3, 3, 3, 2, 1},
+	{4, 3, 3, 3, 2, 1},
+	{4, 3, 3, 3, 2, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1, 1, 1},
+	{4, 3, 3, 3, 3, 1, 1, 1, 1},
+	{4, 3, 3, 3, 3, 2, 1, 1, 1},
+	{4, 3, 3, 3, 3, 2, 2, 1, 1},
+}
+
+// getSpellcasting returns the class's spellcasting rules, or nil for a class
+// that casts no spells.
+func (pack ContentPack) getSpellcasting(className string) *Spellcasting {
+	return pack.getClassStats(className).Spellcasting
+}
+
+func (pack ContentPack) getSpell(name string) (PackSpell, bool) {
+	for _, spell := range pack.Spells {
+		if spell.Name == name {
+			return spell, true
+		}
+	}
+	return PackSpell{}, false
+}
+
+// castingLevel is the character's level as an index into the class tables.
+func castingLevel(c Character) int {
+	return min(max(c.Level, 1), maxCharacterLevel) - 1
+}
+
+// tableValue reads a class table at the character's level; an empty table
+// means none.
+func tableValue(table []int, c Character) int {
+	if level := castingLevel(c); level < len(table) {
+		return table[level]
+	}
+	return 0
+}
+
+// pactSlotLevel is the level of a warlock's pact slots.
+func pactSlotLevel(c Character) int {
+	return min(5, (castingLevel(c)+2)/2)
+}
+
+// spellSlots returns the character's spell slots at each slot level, 1st
+// level first. Pact magic has its slots all at one level, so the levels
+// below it are zero.
+func (pack ContentPack) spellSlots(c Character) []int {
+	casting := pack.getSpellcasting(c.Class)
+	if casting == nil {
+		return nil
+	}
+	level := castingLevel(c) + 1
+	switch casting.Slots {
+	case "full":
+		return fullCasterSlots[level-1]
+	case "half":
+		if level < 2 {
+			return nil
+		}
+		return fullCasterSlots[(level+1)/2-1]
+	case "pact":
+		count := 2
+		switch {
+		case level == 1:
+			count = 1
+		case level >= 17:
+			count = 4
+		case level >= 11:
+			count = 3
+		}
+		slots := make([]int, pactSlotLevel(c))
+		slots[len(slots)-1] = count
+		return slots
+	}
+	return nil
+}
+
+// maxSpellLevel is the highest level of spell the character has slots for.
+func (pack ContentPack) maxSpellLevel(c Character) int {
+	return len(pack.spellSlots(c))
+}
+
+// spellListClass is the class whose spell list the character chooses from.
+func (pack ContentPack) spellListClass(className string) string {
+	if casting := pack.getSpellcasting(className); casting != nil && casting.SpellList != "" {
+		return casting.SpellList
+	}
+	return className
+}
+
+// classSpells lists the spells on the class's spell list up to a spell
+// level, lowest level first and then by name.
+func (pack ContentPack) classSpells(className string, maxLevel int) []PackSpell {
+	listClass := pack.spellListClass(className)
+	var spells []PackSpell
+	for _, spell := range pack.Spells {
+		if spell.Level <= maxLevel && indexOf(spell.Classes, listClass) >= 0 {
+			spells = append(spells, spell)
+		}
+	}
+	sortSpells(spells)
+	return spells
+}
+
+func sortSpells(spells []PackSpell) {
+	sort.SliceStable(spells, func(i, j int) bool {
+		if spells[i].Level != spells[j].Level {
+			return spells[i].Level < spells[j].Level
+		}
+		return spells[i].Name < spells[j].Name
+	})
+}
+
+// spellLimits returns how many cantrips and spells the character has, and
+// how many of its spellbook it can prepare. A class that prepares without
+// a spellbook chooses its prepared spells straight from the class list, so
+// they count as its spells; only a class with both a spellbook and
+// preparation has a separate prepared number.
+func (pack ContentPack) spellLimits(c Character) (cantrips, spells, prepared int) {
+	casting := pack.getSpellcasting(c.Class)
+	if casting == nil {
+		return 0, 0, 0
+	}
+	cantrips = tableValue(casting.Cantrips, c)
+	if pack.maxSpellLevel(c) == 0 {
+		return cantrips, 0, 0
+	}
+
+	if casting.Prepares != "" {
+		level := castingLevel(c) + 1
+		if casting.Prepares == "half level" {
+			level /= 2
+		}
+		prepared = max(1, abilityModifier(c.Abilities[casting.Ability])+level)
+	}
+	if len(casting.Known) == 0 {
+		return cantrips, prepared, 0
+	}
+	spells = tableValue(casting.Known, c)
+	if prepared > 0 {
+		prepared = min(prepared, spells)
+	}
+	return cantrips, spells, prepared
+}
+
+// spellsToChoose counts the cantrips, spells and prepared spells the
+// character could still pick.
+func (pack ContentPack) spellsToChoose(c Character) int {
+	cantrips, spells, prepared := pack.spellLimits(c)
+	return max(0, cantrips-len(c.Cantrips)) + max(0, spells-len(c.Spells)) + max(0, prepared-len(c.Prepared))
+}
+
+// fitSpells drops any spells the character can no longer have, such as
+// spells off its class list or above its slots, and any beyond its limits.
+// Spell slots spent are kept within the slots it has.
+func (pack ContentPack) fitSpells(c Character) Character {
+	cantripLimit, spellLimit, preparedLimit := pack.spellLimits(c)
+	maxLevel := pack.maxSpellLevel(c)
+	listClass := pack.spellListClass(c.Class)
+
+	keep := func(names []string, limit int, allowed func(PackSpell) bool) []string {
+		var kept []string
+		for _, name := range names {
+			spell, ok := pack.getSpell(name)
+			if ok && len(kept) < limit && allowed(spell) && indexOf(kept, name) < 0 {
+				kept = append(kept, name)
+			}
+		}
+		return kept
+	}
+	onList := func(spell PackSpell) bool { return indexOf(spell.Classes, listClass) >= 0 }
+
+	c.Cantrips = keep(c.Cantrips, cantripLimit, func(spell PackSpell) bool {
+		return spell.Level == 0 && onList(spell)
+	})
+	c.Spells = keep(c.Spells, spellLimit, func(spell PackSpell) bool {
+		return spell.Level >= 1 && spell.Level <= maxLevel && onList(spell)
+	})
+	c.Prepared = keep(c.Prepared, preparedLimit, func(spell PackSpell) bool {
+		return indexOf(c.Spells, spell.Name) >= 0
+	})
+
+	slots := pack.spellSlots(c)
+	c.SlotsUsed = append([]int{}, c.SlotsUsed[:min(len(c.SlotsUsed), len(slots))]...)
+	used := false
+	for i := range c.SlotsUsed {
+		c.SlotsUsed[i] = min(max(c.SlotsUsed[i], 0), slots[i])
+		used = used || c.SlotsUsed[i] > 0
+	}
+	if !used {
+		c.SlotsUsed = nil
+	}
+	return c
+}
+
+// chooseSpells fits the character's spells to its class and level, then
+// fills any open picks at random from the class list.
+func (pack ContentPack) chooseSpells(c Character) Character {
+	c = pack.fitSpells(c)
+	cantripLimit, spellLimit, preparedLimit := pack.spellLimits(c)
+
+	fill := func(chosen []string, limit int, options []string) []string {
+		rand.Shuffle(len(options), func(i, j int) {
+			options[i], options[j] = options[j], options[i]
+		})
+		for _, name := range options {
+			if len(chosen) >= limit {
+				break
+			}
+			if indexOf(chosen, name) < 0 {
+				chosen = append(chosen, name)
+			}
+		}
+		return chosen
+	}
+
+	var cantrips, spells []string
+	for _, spell := range pack.classSpells(c.Class, pack.maxSpellLevel(c)) {
+		if spell.Level == 0 {
+			cantrips = append(cantrips, spell.Name)
+		} else {
+			spells = append(spells, spell.Name)
+		}
+	}
+	c.Cantrips = fill(c.Cantrips, cantripLimit, cantrips)
+	c.Spells = fill(c.Spells, spellLimit, spells)
+	c.Prepared = fill(c.Prepared, preparedLimit, append([]string{}, c.Spells...))
+	return c
+}
+
+// spellcastingNumbers returns the character's spellcasting ability, spell
+// save DC and spell attack bonus.
+func (pack ContentPack) spellcastingNumbers(c Character) (ability string, saveDC, attack int) {
+	casting := pack.getSpellcasting(c.Class)
+	if casting == nil {
+		return "", 0, 0
+	}
+	attack = proficiencyBonus(c.Level) + abilityModifier(c.Abilities[casting.Ability])
+	return casting.Ability, 8 + attack, attack
+}
+
+// formatSpellLevel names a spell level, as in "Cantrip" or "3rd".
+func formatSpellLevel(level int) string {
+	switch level {
+	case 0:
+		return "Cantrip"
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	}
+	return fmt.Sprintf("%dth", level)
+}
+
+// describeSpell is a spell's level, school and tags, as in "1st-level
+// divination (ritual)".
+func describeSpell(spell PackSpell) string {
+	text := strings.ToLower(spell.School) + " cantrip"
+	if spell.Level > 0 {
+		text = formatSpellLevel(spell.Level) + "-level " + strings.ToLower(spell.School)
+	}
+	var tags []string
+	if spell.Concentration {
+		tags = append(tags, "concentration")
+	}
+	if spell.Ritual {
+		tags = append(tags, "ritual")
+	}
+	if len(tags) > 0 {
+		text += " (" + strings.Join(tags, ", ") + ")"
+	}
+	return text
+}
+
+// formatSpellSlots shows each slot level with its slots, spent ones hollow,
+// as in "1st ●●○ 2nd ●●".
+func (pack ContentPack) formatSpellSlots(c Character) string {
+	var parts []string
+	for i, total := range pack.spellSlots(c) {
+		if total == 0 {
+			continue
+		}
+		used := 0
+		if i < len(c.SlotsUsed) {
+			used = c.SlotsUsed[i]
+		}
+		parts = append(parts, formatSpellLevel(i+1)+" "+strings.Repeat("●", total-used)+strings.Repeat("○", used))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// spendSpellSlot marks one slot of the given level used.
+func (pack ContentPack) spendSpellSlot(c Character, level int) (Character, error) {
+	slots := pack.spellSlots(c)
+	if level < 1 || level > len(slots) || slots[level-1] == 0 {
+		return c, fmt.Errorf("no %s-level slots", formatSpellLevel(level))
+	}
+	used := make([]int, len(slots))
+	copy(used, c.SlotsUsed)
+	if used[level-1] >= slots[level-1] {
+		return c, fmt.Errorf("no %s-level slots left; rest to get them back", formatSpellLevel(level))
+	}
+	used[level-1]++
+	c.SlotsUsed = used
+	return c, nil
+}
+
+// toggleSpellName adds a name to a list, or takes it off if it is there.
+// Adding fails once the list is at its limit.
+func toggleSpellName(names []string, name string, limit int, what string) ([]string, bool, error) {
+	if i := indexOf(names, name); i >= 0 {
+		return append(append([]string{}, names[:i]...), names[i+1:]...), false, nil
+	}
+	if len(names) >= limit {
+		return names, false, fmt.Errorf("already %d of %d %s; drop one first", len(names), limit, what)
+	}
+	return append(append([]string{}, names...), name), true, nil
+}
+
+// rpgSpellList is the spells the spell screen shows: the class list up to
+// the character's highest slot, or every spell in the pack, narrowed by the
+// search text.
+func (m model) rpgSpellList() []PackSpell {
+	var spells []PackSpell
+	if m.rpgSpellAll {
+		spells = append(spells, m.rpgPack.Spells...)
+		sortSpells(spells)
+	} else {
+		spells = m.rpgPack.classSpells(m.rpgCharacter.Class, m.rpgPack.maxSpellLevel(m.rpgCharacter))
+	}
+	search := strings.ToLower(strings.TrimSpace(m.rpgSpellSearch))
+	if search == "" {
+		return spells
+	}
+	var found []PackSpell
+	for _, spell := range spells {
+		text := strings.ToLower(spell.Name + " " + spell.School + " " + strings.Join(spell.Classes, " "))
+		if strings.Contains(text, search) {
+			found = append(found, spell)
+		}
+	}
+	return found
+}
+
+func (m model) openRPGSpells() model {
+	m.rpgSpellCursor = 0
+	m.rpgSpellSearch = ""
+	m.rpgSpellSearching = false
+	m.rpgSpellAll = m.rpgPack.getSpellcasting(m.rpgCharacter.Class) == nil
+	m.rpgSpellMessage = ""
+	m.rpgExportStatus = ""
+	m.state = rpgSpellsView
+	return m
+}
+
+func (m model) updateRPGSpells(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	key := keyMsg.String()
+
+	if m.rpgSpellSearching {
+		switch key {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.rpgSpellSearch = ""
+			m.rpgSpellSearching = false
+		case "enter":
+			m.rpgSpellSearching = false
+		case "backspace":
+			if len(m.rpgSpellSearch) > 0 {
+				runes := []rune(m.rpgSpellSearch)
+				m.rpgSpellSearch = string(runes[:len(runes)-1])
+			}
+		default:
+			if len(key) == 1 {
+				m.rpgSpellSearch += key
+			}
+		}
+		m.rpgSpellCursor = 0
+		return m, nil
+	}
+
+	spells := m.rpgSpellList()
+	c := m.rpgCharacter
+	cantripLimit, spellLimit, preparedLimit := m.rpgPack.spellLimits(c)
+	m.rpgSpellMessage = ""
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "enter":
+		m.state = rpgCharacterView
+		m = m.autosaveRPGCharacter(fmt.Sprintf("✅ Spells updated for %s", characterDisplayName(c)))
+	case "up", "k":
+		if m.rpgSpellCursor > 0 {
+			m.rpgSpellCursor--
+		}
+	case "down", "j":
+		if m.rpgSpellCursor < len(spells)-1 {
+			m.rpgSpellCursor++
+		}
+	case "/":
+		m.rpgSpellSearching = true
+	case "a":
+		m.rpgSpellAll = !m.rpgSpellAll
+		m.rpgSpellCursor = 0
+	case " ":
+		if m.rpgSpellCursor >= len(spells) {
+			break
+		}
+		spell := spells[m.rpgSpellCursor]
+		var err error
+		var added bool
+		switch {
+		case m.rpgPack.getSpellcasting(c.Class) == nil:
+			err = fmt.Errorf("%ss don't cast spells", c.Class)
+		case indexOf(spell.Classes, m.rpgPack.spellListClass(c.Class)) < 0:
+			err = fmt.Errorf("%s isn't on the %s spell list", spell.Name, m.rpgPack.spellListClass(c.Class))
+		case spell.Level > m.rpgPack.maxSpellLevel(c):
+			err = fmt.Errorf("%s needs a %s-level slot", spell.Name, formatSpellLevel(spell.Level))
+		case spell.Level == 0:
+			c.Cantrips, added, err = toggleSpellName(c.Cantrips, spell.Name, cantripLimit, "cantrips")
+		default:
+			c.Spells, added, err = toggleSpellName(c.Spells, spell.Name, spellLimit, "spells")
+			if i := indexOf(c.Prepared, spell.Name); err == nil && !added && i >= 0 {
+				c.Prepared = append(append([]string{}, c.Prepared[:i]...), c.Prepared[i+1:]...)
+			}
+		}
+		if err != nil {
+			m.rpgSpellMessage = "❌ " + err.Error()
+		} else if added {
+			m.rpgSpellMessage = "✅ Chose " + spell.Name
+		} else {
+			m.rpgSpellMessage = "Dropped " + spell.Name
+		}
+		m.rpgCharacter = c
+	case "p":
+		if m.rpgSpellCursor >= len(spells) {
+			break
+		}
+		spell := spells[m.rpgSpellCursor]
+		if preparedLimit == 0 {
+			m.rpgSpellMessage = "❌ Only casters with a spellbook prepare from it; Space chooses spells"
+			break
+		}
+		if indexOf(c.Spells, spell.Name) < 0 {
+			m.rpgSpellMessage = fmt.Sprintf("❌ %s isn't in the spellbook; Space adds it", spell.Name)
+			break
+		}
+		prepared, added, err := toggleSpellName(c.Prepared, spell.Name, preparedLimit, "prepared")
+		if err != nil {
+			m.rpgSpellMessage = "❌ " + err.Error()
+			break
+		}
+		m.rpgCharacter.Prepared = prepared
+		if added {
+			m.rpgSpellMessage = "✅ Prepared " + spell.Name
+		} else {
+			m.rpgSpellMessage = "Unprepared " + spell.Name
+		}
+	case "f":
+		m.rpgCharacter = m.rpgPack.chooseSpells(c)
+		m.rpgSpellMessage = "✅ Filled the open picks at random"
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		level := int(key[0] - '0')
+		spent, err := m.rpgPack.spendSpellSlot(c, level)
+		if err != nil {
+			m.rpgSpellMessage = "❌ " + err.Error()
+			break
+		}
+		m.rpgCharacter = spent
+		m.rpgSpellMessage = fmt.Sprintf("✅ Spent a %s-level slot", formatSpellLevel(level))
+	case "r":
+		m.rpgCharacter.SlotsUsed = nil
+		m.rpgSpellMessage = "✅ Long rest: all spell slots restored"
+	case "t":
+		if casting := m.rpgPack.getSpellcasting(c.Class); casting == nil || casting.Slots != "pact" {
+			m.rpgSpellMessage = "❌ Only pact magic slots come back on a short rest"
+			break
+		}
+		m.rpgCharacter.SlotsUsed = nil
+		m.rpgSpellMessage = "✅ Short rest: pact slots restored"
+	}
+	return m, nil
+}
+
+func (m model) viewRPGSpells() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Width(90).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#8B5CF6")).
+		Padding(1, 1).
+		Width(42)
+
+	detailStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#10B981")).
+		Padding(1, 2).
+		Width(46)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#8B5CF6"))
+
+	headingStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#10B981"))
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(90)
+
+	c := m.rpgCharacter
+	title := titleStyle.Render(fmt.Sprintf("✨ Spells • %s • Level %d %s", characterDisplayName(c), max(1, c.Level), c.Class))
+
+	// Numbers and slots across the top
+	var summary []string
+	cantripLimit, spellLimit, preparedLimit := m.rpgPack.spellLimits(c)
+	if ability, saveDC, attack := m.rpgPack.spellcastingNumbers(c); ability != "" {
+		summary = append(summary, fmt.Sprintf("%s • Save DC %d • Attack %s", ability, saveDC, formatModifier(attack)))
+		counts := fmt.Sprintf("Cantrips %d/%d • Spells %d/%d", len(c.Cantrips), cantripLimit, len(c.Spells), spellLimit)
+		if preparedLimit > 0 {
+			counts = fmt.Sprintf("Cantrips %d/%d • Spellbook %d/%d • Prepared %d/%d", len(c.Cantrips), cantripLimit, len(c.Spells), spellLimit, len(c.Prepared), preparedLimit)
+		}
+		summary = append(summary, counts)
+		if slots := m.rpgPack.formatSpellSlots(c); slots != "" {
+			summary = append(summary, "Slots: "+slots)
+		} else {
+			summary = append(summary, "No spell slots until level 2")
+		}
+	} else {
+		summary = append(summary, fmt.Sprintf("%ss don't cast spells; browsing every spell in the pack", c.Class))
+	}
+
+	// The list, scrolled to keep the cursor in view
+	spells := m.rpgSpellList()
+	const visible = 14
+	start := 0
+	if m.rpgSpellCursor >= visible {
+		start = m.rpgSpellCursor - visible + 1
+	}
+	var rows []string
+	heading := fmt.Sprintf("%s spell list", m.rpgPack.spellListClass(c.Class))
+	if m.rpgSpellAll {
+		heading = "All spells"
+	}
+	if m.rpgSpellSearching || m.rpgSpellSearch != "" {
+		cursor := ""
+		if m.rpgSpellSearching {
+			cursor = "█"
+		}
+		heading += fmt.Sprintf(" • 🔍 %s%s", m.rpgSpellSearch, cursor)
+	}
+	rows = append(rows, headingStyle.Render(heading), "")
+	if len(spells) == 0 {
+		rows = append(rows, dimStyle.Render("No spells match."))
+	}
+	for i := start; i < len(spells) && i < start+visible; i++ {
+		spell := spells[i]
+		mark := " "
+		switch {
+		case indexOf(c.Prepared, spell.Name) >= 0:
+			mark = "◆"
+		case indexOf(c.Cantrips, spell.Name) >= 0, indexOf(c.Spells, spell.Name) >= 0:
+			mark = "✓"
+		}
+		row := fmt.Sprintf("%s %-24s %-7s", mark, truncateSpellName(spell.Name, 24), formatSpellLevel(spell.Level))
+		if i == m.rpgSpellCursor {
+			row = selectedStyle.Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+	if len(spells) > visible {
+		rows = append(rows, "", dimStyle.Render(fmt.Sprintf("%d of %d", m.rpgSpellCursor+1, len(spells))))
+	}
+	rows = append(rows, "", dimStyle.Render("✓ chosen"))
+	if preparedLimit > 0 {
+		rows[len(rows)-1] = dimStyle.Render("✓ in spellbook • ◆ prepared")
+	}
+
+	// Details of the highlighted spell
+	var detail strings.Builder
+	if m.rpgSpellCursor < len(spells) {
+		spell := spells[m.rpgSpellCursor]
+		detail.WriteString(headingStyle.Render(spell.Name) + "\n")
+		detail.WriteString(dimStyle.Render(describeSpell(spell)) + "\n\n")
+		detail.WriteString(fmt.Sprintf("Casting time: %s\nRange: %s\nComponents: %s\nDuration: %s\nClasses: %s\n\n",
+			spell.CastingTime, spell.Range, spell.Components, spell.Duration, strings.Join(spell.Classes, ", ")))
+		detail.WriteString(spell.Description)
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top, listStyle.Render(strings.Join(rows, "\n")), "  ", detailStyle.Render(detail.String()))
+	elements := []string{title, lipgloss.NewStyle().MarginBottom(1).Render(strings.Join(summary, "\n")), body}
+	if m.rpgSpellMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginTop(1).Render(m.rpgSpellMessage))
+	}
+	help := "↑/↓ to select • Space to choose • / to search • A for all spells"
+	if preparedLimit > 0 {
+		help = "↑/↓ to select • Space to choose • P to prepare • / to search • A for all spells"
+	}
+	help += "\nF to fill at random • 1-9 to spend a slot • R long rest • T short rest • Enter when done"
+	elements = append(elements, lipgloss.NewStyle().MarginTop(1).Render(helpStyle.Render(help)))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
+
+// truncateSpellName shortens a long spell name to fit its column.
+func truncateSpellName(name string, width int) string {
+	runes := []rune(name)
+	if len(runes) <= width {
+		return name
+	}
+	return string(runes[:width-1]) + "…"
+}
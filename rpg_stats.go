@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// The 18 SRD skills in sheet order, with the ability each one uses.
+var skillNames = []string{
+	"Acrobatics", "Animal Handling", "Arcana", "Athletics", "Deception", "History",
+	"Insight", "Intimidation", "Investigation", "Medicine", "Nature", "Perception",
+	"Performance", "Persuasion", "Religion", "Sleight of Hand", "Stealth", "Survival",
+}
+
+var skillAbilities = map[string]string{
+	"Acrobatics":      "Dexterity",
+	"Animal Handling": "Wisdom",
+	"Arcana":          "Intelligence",
+	"Athletics":       "Strength",
+	"Deception":       "Charisma",
+	"History":         "Intelligence",
+	"Insight":         "Wisdom",
+	"Intimidation":    "Charisma",
+	"Investigation":   "Intelligence",
+	"Medicine":        "Wisdom",
+	"Nature":          "Intelligence",
+	"Perception":      "Wisdom",
+	"Performance":     "Charisma",
+	"Persuasion":      "Charisma",
+	"Religion":        "Intelligence",
+	"Sleight of Hand": "Dexterity",
+	"Stealth":         "Dexterity",
+	"Survival":        "Wisdom",
+}
+
+func (pack ContentPack) getArmorStats(armorName string) (ArmorStats, bool) {
+	for _, armor := range pack.Armor {
+		if armor.Name == armorName {
+			return armor.ArmorStats, true
+		}
+	}
+	return ArmorStats{}, false
+}
+
+// chooseClassSkills picks the class's skill proficiencies, plus any free
+// racial picks, skipping skills the background or race already grant. Skills
+// that use the character's best abilities are preferred; ties are broken at
+// random.
+func (pack ContentPack) chooseClassSkills(c Character) []string {
+	classStats := pack.getClassStats(c.Class)
+	race := pack.getRaceStats(c.Race)
+
+	taken := make(map[string]bool)
+	for _, skill := range append(pack.getBackgroundStats(c.Background).Skills, race.Skills...) {
+		taken[skill] = true
+	}
+
+	best := func(options []string) []string {
+		var candidates []string
+		for _, skill := range options {
+			if !taken[skill] {
+				candidates = append(candidates, skill)
+			}
+		}
+		rand.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return c.Abilities[skillAbilities[candidates[i]]] > c.Abilities[skillAbilities[candidates[j]]]
+		})
+		return candidates
+	}
+
+	var chosen []string
+	pick := func(options []string, count int) {
+		for _, skill := range best(options) {
+			if count == 0 {
+				break
+			}
+			chosen = append(chosen, skill)
+			taken[skill] = true
+			count--
+		}
+	}
+
+	options := classStats.SkillOptions
+	if options == nil {
+		options = skillNames
+	}
+	pick(options, classStats.SkillChoices)
+	pick(skillNames, race.SkillChoices)
+	return chosen
+}
+
+// isWeaponProficient checks the class and race weapon proficiencies, which
+// name either a whole category or a single weapon.
+func (pack ContentPack) isWeaponProficient(c Character, name string, weapon WeaponStats) bool {
+	proficiencies := append(append([]string{}, pack.getClassStats(c.Class).WeaponProficiencies...), pack.getRaceStats(c.Race).Weapons...)
+	for _, proficiency := range proficiencies {
+		if proficiency == weapon.Category || proficiency == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWeaponProperty reports whether a weapon has a property such as "heavy".
+func hasWeaponProperty(weapon WeaponStats, property string) bool {
+	for _, p := range weapon.Properties {
+		if p == property {
+			return true
+		}
+	}
+	return false
+}
+
+// damageExpression appends a modifier to a damage roll, leaving it off when
+// it is zero.
+func damageExpression(dice string, mod int) string {
+	if mod == 0 {
+		return dice
+	}
+	return dice + formatModifier(mod)
+}
+
+// characterArmorClass works out the best AC the character's gear allows:
+// worn armor plus DEX (capped at +2 for medium, none for heavy), or 10 + DEX
+// unarmored, with the Barbarian and Monk unarmored defense rules. A shield
+// adds 2 except to a Monk's unarmored defense.
+func (pack ContentPack) characterArmorClass(c Character, mods map[string]int) (int, string) {
+	dex := mods["Dexterity"]
+	shield := false
+	var worn []string
+	for _, item := range append(append([]string{}, c.Gear.Armor...), c.Gear.Weapons...) {
+		name := gearBaseName(item)
+		if name == "Shield" {
+			shield = true
+		} else if _, ok := pack.getArmorStats(name); ok {
+			worn = append(worn, name)
+		}
+	}
+
+	best, source := 10+dex, "Unarmored"
+	switch c.Class {
+	case "Barbarian":
+		best, source = 10+dex+mods["Constitution"], "Unarmored Defense"
+	case "Monk":
+		best, source = 10+dex+mods["Wisdom"], "Unarmored Defense"
+	}
+	if shield && c.Class != "Monk" {
+		best += 2
+		source += " + Shield"
+	}
+
+	for _, name := range worn {
+		armor, _ := pack.getArmorStats(name)
+		ac := armor.BaseAC
+		switch armor.Category {
+		case "light":
+			ac += dex
+		case "medium":
+			ac += min(dex, 2)
+		}
+		label := name
+		if shield {
+			ac += 2
+			label += " + Shield"
+		}
+		if ac > best {
+			best, source = ac, label
+		}
+	}
+	return best, source
+}
+
+// deriveStats works out a character's modifiers, hit points, armor class,
+// saves, skills and weapon attacks.
+func (pack ContentPack) deriveStats(c Character) DerivedStats {
+	level := max(1, c.Level)
+	classStats := pack.getClassStats(c.Class)
+	race := pack.getRaceStats(c.Race)
+
+	stats := DerivedStats{
+		Level:       level,
+		Modifiers:   make(map[string]int),
+		Proficiency: proficiencyBonus(level),
+		Speed:       race.Speed,
+		Saves:       make(map[string]int),
+		Skills:      make(map[string]int),
+	}
+	for _, ability := range abilityNames {
+		stats.Modifiers[ability] = abilityModifier(c.Abilities[ability])
+	}
+
+	// Hit die maximum plus CON at first level, then each level's roll plus
+	// CON, never less than 1
+	stats.HitPoints = max(1, classStats.HitDie+stats.Modifiers["Constitution"])
+	for _, roll := range c.HitDieRolls {
+		stats.HitPoints += max(1, roll+stats.Modifiers["Constitution"])
+	}
+	stats.HitPoints += race.HitPointBonus * level
+	stats.ArmorClass, stats.ArmorSource = pack.characterArmorClass(c, stats.Modifiers)
+	stats.Initiative = stats.Modifiers["Dexterity"]
+
+	for _, ability := range abilityNames {
+		stats.Saves[ability] = stats.Modifiers[ability]
+	}
+	for _, ability := range classStats.SavingThrows {
+		stats.Saves[ability] += stats.Proficiency
+		stats.SaveProficiencies = append(stats.SaveProficiencies, ability)
+	}
+
+	proficient := make(map[string]bool)
+	for _, skill := range append(append(append([]string{}, pack.getBackgroundStats(c.Background).Skills...), race.Skills...), c.Skills...) {
+		proficient[skill] = true
+	}
+	for _, skill := range skillNames {
+		stats.Skills[skill] = stats.Modifiers[skillAbilities[skill]]
+		if proficient[skill] {
+			stats.Skills[skill] += stats.Proficiency
+			stats.SkillProficiencies = append(stats.SkillProficiencies, skill)
+		}
+	}
+	stats.PassivePerception = 10 + stats.Skills["Perception"]
+
+	strMod, dexMod := stats.Modifiers["Strength"], stats.Modifiers["Dexterity"]
+	seen := make(map[string]bool)
+	for _, item := range c.Gear.Weapons {
+		name := gearBaseName(item)
+		weapon, ok := pack.getWeaponStats(name)
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		// Martial Arts lets a Monk use DEX with shortswords and light simple melee weapons
+		monkWeapon := c.Class == "Monk" && !weapon.Ranged && (name == "Shortsword" ||
+			(weapon.Category == "simple" && !hasWeaponProperty(weapon, "heavy") && !hasWeaponProperty(weapon, "two-handed")))
+
+		mod := strMod
+		if weapon.Ranged {
+			mod = dexMod
+		} else if weapon.Finesse || monkWeapon {
+			mod = max(strMod, dexMod)
+		}
+
+		attack := WeaponAttack{
+			Name:        name,
+			AttackBonus: mod,
+			Damage:      damageExpression(weapon.Damage, mod),
+			DamageType:  weapon.DamageType,
+			Proficient:  pack.isWeaponProficient(c, name, weapon),
+		}
+		if attack.Proficient {
+			attack.AttackBonus += stats.Proficiency
+		}
+		stats.Attacks = append(stats.Attacks, attack)
+	}
+
+	return stats
+}
+
+// formatSkillList lists skills with their bonuses, e.g. "Insight +4, Religion +2".
+func formatSkillList(stats DerivedStats, skills []string) string {
+	var parts []string
+	for _, skill := range skills {
+		parts = append(parts, fmt.Sprintf("%s %s", skill, formatModifier(stats.Skills[skill])))
+	}
+	return strings.Join(parts, ", ")
+}
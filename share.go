@@ -0,0 +1,559 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/skip2/go-qrcode"
+)
+
+// Download limits and timeouts offered by the share form. A limit of 0 means unlimited.
+var shareDownloadLimits = []int{1, 2, 3, 5, 10, 0}
+var shareTimeouts = []time.Duration{time.Minute, 5 * time.Minute, 10 * time.Minute, 30 * time.Minute, time.Hour}
+
+const (
+	shareFieldSource = iota
+	shareFieldKind
+	shareFieldAddress
+	shareFieldDownloads
+	shareFieldTimeout
+	shareFieldCount
+)
+
+type shareAddress struct {
+	Interface string
+	IP        string
+}
+
+type shareServer struct {
+	server       *http.Server
+	url          string
+	kind         string // "file", "directory" or "text"
+	name         string
+	maxDownloads int
+	expiresAt    time.Time
+	downloads    atomic.Int32
+	mu           sync.Mutex
+	reserved     int // downloads finished or in progress, guarded by mu
+	limitOnce    sync.Once
+	limitReached chan struct{}
+}
+
+// getShareAddresses lists the IPv4 addresses of interfaces that are up, with
+// loopback addresses last since they are useless for reaching another device.
+func getShareAddresses() []shareAddress {
+	var addresses, loopback []shareAddress
+	for _, iface := range getNetworkInfo() {
+		if !iface.IsUp {
+			continue
+		}
+		for _, addr := range iface.Addresses {
+			ip, _, err := net.ParseCIDR(addr)
+			if err != nil || ip.To4() == nil {
+				continue
+			}
+			entry := shareAddress{Interface: iface.Name, IP: ip.String()}
+			if iface.IsLoopback {
+				loopback = append(loopback, entry)
+			} else {
+				addresses = append(addresses, entry)
+			}
+		}
+	}
+	return append(addresses, loopback...)
+}
+
+// shareTickMsg drives the status of a running share. Its id ties it to one
+// share, so the ticks of a share that was stopped die out.
+type shareTickMsg struct {
+	id int
+}
+
+func shareTick(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return shareTickMsg{id: id}
+	})
+}
+
+// resolveShareSource works out what to share once, when the share starts:
+// the input as a text snippet, or the file or directory it names.
+func resolveShareSource(input string, asText bool) (kind, path string, err error) {
+	if asText {
+		return "text", "", nil
+	}
+	path = strings.TrimSpace(input)
+	if strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(homeDir, path[2:])
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", "", fmt.Errorf("%s is not a file or folder (switch Share as to text to send it as text)", path)
+	}
+	if info.IsDir() {
+		return "directory", path, nil
+	}
+	return "file", path, nil
+}
+
+func generateShareToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func startShareServer(input string, asText bool, address shareAddress, maxDownloads int, timeout time.Duration) (*shareServer, error) {
+	kind, path, err := resolveShareSource(input, asText)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	name := "snippet.txt"
+	if kind == "file" {
+		name = filepath.Base(path)
+	} else if kind == "directory" {
+		name = filepath.Base(filepath.Clean(path)) + ".zip"
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(address.IP, "0"))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &shareServer{
+		kind:         kind,
+		name:         name,
+		maxDownloads: maxDownloads,
+		expiresAt:    time.Now().Add(timeout),
+		limitReached: make(chan struct{}),
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	sharePath := "/" + token + "/" + name
+	s.url = fmt.Sprintf("http://%s/%s/%s", net.JoinHostPort(address.IP, fmt.Sprint(port)), token, url.PathEscape(name))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != sharePath {
+			http.NotFound(w, r)
+			return
+		}
+		// GETs hold a download slot while they run, so parallel requests can't
+		// go past the limit; HEAD probes and link previews don't need one
+		get := r.Method == http.MethodGet
+		if (get && !s.reserveDownload()) || (!get && s.limitHit()) {
+			http.Error(w, "This share has reached its download limit", http.StatusGone)
+			return
+		}
+
+		rw := &shareResponseWriter{ResponseWriter: w}
+		var err error
+		switch kind {
+		case "file":
+			err = serveShareFile(rw, r, path)
+		case "directory":
+			err = serveShareDirectory(rw, path, name)
+		default:
+			rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, err = io.WriteString(rw, input)
+		}
+
+		// Only full downloads count, not ranges, 304s or aborted transfers
+		if get {
+			s.finishDownload(err == nil && rw.status == http.StatusOK)
+		}
+	})
+
+	s.server = &http.Server{Handler: mux}
+	go s.server.Serve(listener)
+
+	return s, nil
+}
+
+// shareResponseWriter records the status and how much of the body was sent,
+// so the handler can tell a full download from a partial one.
+type shareResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+func (w *shareResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *shareResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+func serveShareFile(w *shareResponseWriter, r *http.Request, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "File is no longer available", http.StatusNotFound)
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "File is no longer available", http.StatusNotFound)
+		return err
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", info.Name()))
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+	if w.status == http.StatusOK && w.written < info.Size() {
+		return io.ErrShortWrite
+	}
+	return nil
+}
+
+// serveShareDirectory streams the directory as a zip archive, which phones can open natively.
+func serveShareDirectory(w http.ResponseWriter, root, name string) error {
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+
+	archive := zip.NewWriter(w)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entry, err := archive.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(entry, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// reserveDownload takes a download slot, or reports that none are left.
+func (s *shareServer) reserveDownload() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxDownloads > 0 && s.reserved >= s.maxDownloads {
+		return false
+	}
+	s.reserved++
+	return true
+}
+
+// finishDownload counts a reserved download that completed, or gives its
+// slot back when it didn't.
+func (s *shareServer) finishDownload(complete bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !complete {
+		s.reserved--
+		return
+	}
+	count := int(s.downloads.Add(1))
+	if s.maxDownloads > 0 && count >= s.maxDownloads {
+		s.limitOnce.Do(func() { close(s.limitReached) })
+	}
+}
+
+func (s *shareServer) limitHit() bool {
+	select {
+	case <-s.limitReached:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *shareServer) stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
+
+func formatShareDownloadLimit(limit int) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	if limit == 1 {
+		return "1 download"
+	}
+	return fmt.Sprintf("%d downloads", limit)
+}
+
+func (m model) updateShare(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.shareServer != nil {
+			switch msg.String() {
+			case "ctrl+c":
+				m.shareServer.stop()
+				return m, tea.Quit
+			case "esc", "s":
+				m.shareServer.stop()
+				m.shareServer = nil
+				m.shareMessage = "Share stopped"
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.state = menuView
+		case "up":
+			if m.shareField > 0 {
+				m.shareField--
+			}
+		case "down", "tab":
+			if m.shareField < shareFieldCount-1 {
+				m.shareField++
+			}
+		case "left", "right":
+			step := 1
+			if msg.String() == "left" {
+				step = -1
+			}
+			switch m.shareField {
+			case shareFieldKind:
+				m.shareAsText = !m.shareAsText
+			case shareFieldAddress:
+				if len(m.shareAddresses) > 0 {
+					m.shareAddressCursor = (m.shareAddressCursor + step + len(m.shareAddresses)) % len(m.shareAddresses)
+				}
+			case shareFieldDownloads:
+				m.shareDownloadCursor = (m.shareDownloadCursor + step + len(shareDownloadLimits)) % len(shareDownloadLimits)
+			case shareFieldTimeout:
+				m.shareTimeoutCursor = (m.shareTimeoutCursor + step + len(shareTimeouts)) % len(shareTimeouts)
+			}
+		case "ctrl+r":
+			m.shareAddresses = getShareAddresses()
+			m.shareAddressCursor = 0
+			m.shareMessage = "Network interfaces refreshed"
+		case "enter":
+			if strings.TrimSpace(m.shareInput) == "" {
+				m.shareMessage = "Enter a file path, directory or some text to share"
+				return m, nil
+			}
+			if len(m.shareAddresses) == 0 {
+				m.shareMessage = "❌ No network interfaces are up"
+				return m, nil
+			}
+			address := m.shareAddresses[m.shareAddressCursor]
+			server, err := startShareServer(m.shareInput, m.shareAsText, address, shareDownloadLimits[m.shareDownloadCursor], shareTimeouts[m.shareTimeoutCursor])
+			if err != nil {
+				m.shareMessage = "❌ Failed to start share: " + err.Error()
+				return m, nil
+			}
+			m.shareServer = server
+			m.shareMessage = ""
+			if qr, err := qrcode.New(server.url, qrcode.Medium); err == nil {
+				m.shareQRCode = qr.ToSmallString(false)
+			}
+			m.shareID++
+			return m, shareTick(m.shareID)
+		case "backspace":
+			if m.shareField == shareFieldSource && len(m.shareInput) > 0 {
+				m.shareInput = m.shareInput[:len(m.shareInput)-1]
+			}
+		default:
+			if m.shareField == shareFieldSource {
+				if msg.Paste {
+					m.shareInput += string(msg.Runes)
+				} else if len(msg.String()) == 1 {
+					m.shareInput += msg.String()
+				}
+			}
+		}
+	case shareTickMsg:
+		if msg.id == m.shareID && m.shareServer != nil {
+			downloads := int(m.shareServer.downloads.Load())
+			if m.shareServer.limitHit() {
+				m.shareServer.stop()
+				m.shareServer = nil
+				m.shareMessage = fmt.Sprintf("✅ Share finished after %s", formatShareDownloadLimit(downloads))
+			} else if time.Now().After(m.shareServer.expiresAt) {
+				m.shareServer.stop()
+				m.shareServer = nil
+				m.shareMessage = fmt.Sprintf("⏰ Share timed out (%d downloaded)", downloads)
+			} else {
+				return m, shareTick(m.shareID)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewShare() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#16A085")).
+		Padding(1, 2).
+		MarginBottom(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#16A085")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	formStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#16A085")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(70)
+
+	selectedFieldStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#16A085")).
+		Padding(0, 1)
+
+	normalFieldStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#16A085")).
+		Padding(0, 1)
+
+	qrStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#000000")).
+		Background(lipgloss.Color("#FFFFFF")).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#16A085")).
+		MarginBottom(1).
+		AlignHorizontal(lipgloss.Center)
+
+	statusStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#16A085")).
+		Padding(0, 2).
+		MarginBottom(1).
+		Width(70)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	messageStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#16A085")).
+		Bold(true).
+		AlignHorizontal(lipgloss.Center).
+		MarginBottom(1)
+
+	title := titleStyle.Render("📡 File Share")
+
+	var elements []string
+	elements = append(elements, title)
+
+	if m.shareServer != nil {
+		s := m.shareServer
+		remaining := time.Until(s.expiresAt).Round(time.Second)
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		var status strings.Builder
+		status.WriteString(fmt.Sprintf("Sharing %s: %s\n", s.kind, s.name))
+		status.WriteString(fmt.Sprintf("URL:       %s\n", s.url))
+		if s.maxDownloads > 0 {
+			status.WriteString(fmt.Sprintf("Downloads: %d / %d\n", s.downloads.Load(), s.maxDownloads))
+		} else {
+			status.WriteString(fmt.Sprintf("Downloads: %d\n", s.downloads.Load()))
+		}
+		status.WriteString(fmt.Sprintf("Stops in:  %s", remaining))
+
+		if m.shareQRCode != "" {
+			elements = append(elements, qrStyle.Render(m.shareQRCode))
+		}
+		elements = append(elements, statusStyle.Render(status.String()))
+		elements = append(elements, helpStyle.Render("Scan the QR code on the same network • S or ESC to stop sharing • Ctrl+C to quit"))
+		return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+	}
+
+	shareAs := "file or folder"
+	if m.shareAsText {
+		shareAs = "text"
+	}
+
+	address := "no interfaces up"
+	if len(m.shareAddresses) > 0 {
+		a := m.shareAddresses[m.shareAddressCursor]
+		address = fmt.Sprintf("%s (%s)", a.IP, a.Interface)
+	}
+
+	fields := []string{
+		fmt.Sprintf("Share:     %s█", m.shareInput),
+		fmt.Sprintf("Share as:  ◀ %s ▶", shareAs),
+		fmt.Sprintf("Interface: ◀ %s ▶", address),
+		fmt.Sprintf("Stop after:◀ %s ▶", formatShareDownloadLimit(shareDownloadLimits[m.shareDownloadCursor])),
+		fmt.Sprintf("Timeout:   ◀ %s ▶", shareTimeouts[m.shareTimeoutCursor]),
+	}
+
+	var form strings.Builder
+	form.WriteString("Path of a file or folder, or some text to share\n\n")
+	for i, field := range fields {
+		if i == m.shareField {
+			form.WriteString(selectedFieldStyle.Render("▶ "+field) + "\n")
+		} else {
+			form.WriteString(normalFieldStyle.Render("  "+field) + "\n")
+		}
+	}
+	elements = append(elements, formStyle.Render(strings.TrimRight(form.String(), "\n")))
+
+	if m.shareMessage != "" {
+		elements = append(elements, messageStyle.Render(m.shareMessage))
+	}
+
+	elements = append(elements, helpStyle.Render("↑/↓ to pick field • ←/→ to change • Enter to start sharing • Ctrl+R to refresh interfaces • ESC to go back"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
@@ -1,10 +1,7 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -12,39 +9,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-func getTodoFilePath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "todos.json"
-	}
-	return filepath.Join(homeDir, ".big-dumb-toolbox-todos.json")
-}
-
-func loadTodos() []TodoItem {
-	filePath := getTodoFilePath()
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return []TodoItem{}
-	}
-	
-	var todos []TodoItem
-	if err := json.Unmarshal(data, &todos); err != nil {
-		return []TodoItem{}
-	}
-	
-	return todos
-}
-
-func saveTodos(todos []TodoItem) error {
-	filePath := getTodoFilePath()
-	data, err := json.MarshalIndent(todos, "", "  ")
-	if err != nil {
-		return err
-	}
-	
-	return os.WriteFile(filePath, data, 0644)
-}
-
 func generateTodoID() string {
 	return fmt.Sprintf("todo_%d", time.Now().UnixNano())
 }
@@ -80,12 +44,21 @@ func formatTimeRelative(t time.Time) string {
 
 func (m model) updateTodoList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case todoWatchMsg:
+		return m.checkTodoFile(msg)
 	case tea.KeyMsg:
+		if m.todoLoadError != "" {
+			return m.updateTodoRecovery(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "esc":
 			if m.todoInputMode {
+				if m.todoInputKind == "search" && m.todoQuery != "" {
+					m.todoQuery = ""
+					m.todoCursor = 0
+				}
 				m.todoInputMode = false
 				m.todoInput = ""
 				m.todoMessage = ""
@@ -93,48 +66,29 @@ func (m model) updateTodoList(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = menuView
 			}
 		case "tab":
-			m.todoInputMode = !m.todoInputMode
-			m.todoInput = ""
-			m.todoMessage = ""
+			if m.todoInputMode && m.todoInputKind == "" {
+				m.todoInputMode = false
+				m.todoInput = ""
+				m.todoMessage = ""
+			} else {
+				m = m.openTodoInput("", "")
+			}
 		case "enter":
 			if m.todoInputMode {
-				if strings.TrimSpace(m.todoInput) != "" {
-					newTodo := TodoItem{
-						ID:        generateTodoID(),
-						Text:      strings.TrimSpace(m.todoInput),
-						Completed: false,
-						CreatedAt: time.Now(),
-					}
-					m.todoItems = append(m.todoItems, newTodo)
-					if err := saveTodos(m.todoItems); err != nil {
-						m.todoMessage = "❌ Failed to save todo"
-					} else {
-						m.todoMessage = "✅ Todo added successfully"
-					}
-					m.todoInput = ""
-					m.todoInputMode = false
-				}
+				m = m.applyTodoInput()
 			} else if len(m.getFilteredTodos()) > 0 {
 				filtered := m.getFilteredTodos()
 				if m.todoCursor < len(filtered) {
-					targetID := filtered[m.todoCursor].ID
-					for i := range m.todoItems {
-						if m.todoItems[i].ID == targetID {
-							m.todoItems[i].Completed = !m.todoItems[i].Completed
-							if m.todoItems[i].Completed {
-								now := time.Now()
-								m.todoItems[i].CompletedAt = &now
-							} else {
-								m.todoItems[i].CompletedAt = nil
-							}
-							break
+					completed := !filtered[m.todoCursor].Completed
+					m, _ = m.storeTodos(editTodo(filtered[m.todoCursor].ID, func(todo *TodoItem) {
+						todo.Completed = completed
+						if completed {
+							now := time.Now()
+							todo.CompletedAt = &now
+						} else {
+							todo.CompletedAt = nil
 						}
-					}
-					if err := saveTodos(m.todoItems); err != nil {
-						m.todoMessage = "❌ Failed to save changes"
-					} else {
-						m.todoMessage = "✅ Todo updated"
-					}
+					}), "✅ Todo updated", "❌ Failed to save changes")
 				}
 			}
 		case "backspace":
@@ -174,17 +128,14 @@ func (m model) updateTodoList(msg tea.Msg) (tea.Model, tea.Cmd) {
 				filtered := m.getFilteredTodos()
 				if m.todoCursor < len(filtered) {
 					targetID := filtered[m.todoCursor].ID
-					for i := len(m.todoItems) - 1; i >= 0; i-- {
-						if m.todoItems[i].ID == targetID {
-							m.todoItems = append(m.todoItems[:i], m.todoItems[i+1:]...)
-							break
+					m, _ = m.storeTodos(func(todos []TodoItem) ([]TodoItem, bool) {
+						for i := len(todos) - 1; i >= 0; i-- {
+							if todos[i].ID == targetID {
+								return append(todos[:i], todos[i+1:]...), true
+							}
 						}
-					}
-					if err := saveTodos(m.todoItems); err != nil {
-						m.todoMessage = "❌ Failed to delete todo"
-					} else {
-						m.todoMessage = "✅ Todo deleted"
-					}
+						return todos, true // already deleted elsewhere
+					}, "✅ Todo deleted", "❌ Failed to delete todo")
 					if m.todoCursor >= len(m.getFilteredTodos()) && m.todoCursor > 0 {
 						m.todoCursor--
 					}
@@ -205,6 +156,46 @@ func (m model) updateTodoList(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.todoCursor = 0
 				m.todoMessage = fmt.Sprintf("Filter: %s", m.todoFilter)
 			}
+		case "e":
+			if m.todoInputMode {
+				m.todoInput += "e"
+			} else if i := m.selectedTodoIndex(); i >= 0 {
+				m = m.openTodoInput("edit", formatTodoLine(m.todoItems[i]))
+			}
+		case "n":
+			if m.todoInputMode {
+				m.todoInput += "n"
+			} else if i := m.selectedTodoIndex(); i >= 0 {
+				m = m.openTodoInput("note", m.todoItems[i].Notes)
+			}
+		case "p":
+			if m.todoInputMode {
+				m.todoInput += "p"
+			} else if i := m.selectedTodoIndex(); i >= 0 {
+				// Cycle A → B → C → none
+				next := indexOf(todoPriorities, m.todoItems[i].Priority) + 1
+				priority := ""
+				if next < len(todoPriorities) {
+					priority = todoPriorities[next]
+				}
+				m, _ = m.storeTodos(editTodo(m.todoItems[i].ID, func(todo *TodoItem) {
+					todo.Priority = priority
+				}), "✅ Priority: "+orDefault(priority, "none"), "❌ Failed to save changes")
+			}
+		case "s":
+			if m.todoInputMode {
+				m.todoInput += "s"
+			} else {
+				m.todoSort = todoSorts[(indexOf(todoSorts, m.todoSort)+1)%len(todoSorts)]
+				m.todoCursor = 0
+				m.todoMessage = fmt.Sprintf("Sort: %s", m.todoSort)
+			}
+		case "/":
+			if m.todoInputMode {
+				m.todoInput += "/"
+			} else {
+				m = m.openTodoInput("search", m.todoQuery)
+			}
 		default:
 			if m.todoInputMode && len(msg.String()) == 1 {
 				m.todoInput += msg.String()
@@ -214,9 +205,99 @@ func (m model) updateTodoList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// openTodoInput opens the input box to add a todo (kind ""), edit the
+// selected one ("edit"), write its notes ("note") or search ("search").
+func (m model) openTodoInput(kind, input string) model {
+	m.todoInputMode = true
+	m.todoInputKind = kind
+	m.todoInput = input
+	// Remember the todo now, as the list can be reloaded while the box is open
+	m.todoEditID = ""
+	if i := m.selectedTodoIndex(); i >= 0 && (kind == "edit" || kind == "note") {
+		m.todoEditID = m.todoItems[i].ID
+	}
+	m.todoMessage = ""
+	return m
+}
+
+// applyTodoInput acts on what was typed in the input box. On an error the
+// box stays open so the entry can be fixed.
+func (m model) applyTodoInput() model {
+	input := strings.TrimSpace(m.todoInput)
+	switch m.todoInputKind {
+	case "search":
+		m.todoQuery = input
+		m.todoCursor = 0
+		m.todoMessage = ""
+	case "note":
+		if m.todoEditID == "" {
+			break
+		}
+		var saved bool
+		m, saved = m.storeTodos(editTodo(m.todoEditID, func(todo *TodoItem) {
+			todo.Notes = input
+		}), "✅ Notes saved", "❌ Failed to save changes")
+		if !saved {
+			return m // keep what was typed
+		}
+	default:
+		if input == "" {
+			return m
+		}
+		todo, err := parseTodoLine(input, time.Now())
+		if err != nil {
+			m.todoMessage = "❌ " + err.Error()
+			return m
+		}
+		var saved bool
+		if m.todoInputKind == "edit" {
+			if m.todoEditID == "" {
+				break
+			}
+			m, saved = m.storeTodos(editTodo(m.todoEditID, func(existing *TodoItem) {
+				existing.Text, existing.Priority, existing.Due, existing.Scheduled = todo.Text, todo.Priority, todo.Due, todo.Scheduled
+				existing.Tags, existing.Projects = todo.Tags, todo.Projects
+			}), "✅ Todo updated", "❌ Failed to save changes")
+		} else {
+			todo.ID = generateTodoID()
+			todo.CreatedAt = time.Now()
+			m, saved = m.storeTodos(func(todos []TodoItem) ([]TodoItem, bool) {
+				return append(todos, todo), true
+			}, "✅ Todo added successfully", "❌ Failed to save todo")
+		}
+		if !saved {
+			return m // keep what was typed
+		}
+	}
+	m.todoInput = ""
+	m.todoInputMode = false
+	return m
+}
+
+// selectedTodoIndex is the position in todoItems of the todo under the
+// cursor, or -1.
+func (m model) selectedTodoIndex() int {
+	filtered := m.getFilteredTodos()
+	if m.todoCursor >= len(filtered) {
+		return -1
+	}
+	for i := range m.todoItems {
+		if m.todoItems[i].ID == filtered[m.todoCursor].ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// getFilteredTodos is the list as shown: narrowed by the status filter and
+// any search, then sorted.
 func (m model) getFilteredTodos() []TodoItem {
 	var filtered []TodoItem
+	now := time.Now()
 	for _, todo := range m.todoItems {
+		if !matchesTodoQuery(todo, m.todoQuery, now) {
+			continue
+		}
 		switch m.todoFilter {
 		case "active":
 			if !todo.Completed {
@@ -230,10 +311,15 @@ func (m model) getFilteredTodos() []TodoItem {
 			filtered = append(filtered, todo)
 		}
 	}
+	sortTodos(filtered, m.todoSort)
 	return filtered
 }
 
 func (m model) viewTodoList() string {
+	if m.todoLoadError != "" {
+		return m.viewTodoRecovery()
+	}
+
 	containerStyle := lipgloss.NewStyle().
 		Width(m.width).
 		Height(m.height).
@@ -292,26 +378,66 @@ func (m model) viewTodoList() string {
 		Foreground(lipgloss.Color("#FF9500")).
 		Padding(0, 1)
 
+	// Overdue todos are red and todos due today amber
+	dueColors := map[int]lipgloss.Color{
+		todoOverdue:  lipgloss.Color("#EF4444"),
+		todoDueToday: lipgloss.Color("#FBBF24"),
+	}
+
+	notesStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true)
+
 	title := titleStyle.Render("📝 Todo List")
 	
 	var todoDisplay strings.Builder
-	todoDisplay.WriteString(fmt.Sprintf("Filter: %s\n\n", strings.ToUpper(m.todoFilter)))
+	header := fmt.Sprintf("Filter: %s • Sort: %s", strings.ToUpper(m.todoFilter), strings.ToUpper(orDefault(m.todoSort, "created")))
+	if m.todoQuery != "" {
+		header += " • 🔍 " + m.todoQuery
+	}
+	todoDisplay.WriteString(header + "\n\n")
 	
+	now := time.Now()
 	filtered := m.getFilteredTodos()
-	if len(filtered) == 0 {
+	if len(filtered) == 0 && m.todoQuery != "" {
+		todoDisplay.WriteString("No todos match the search.\n\nPress / then ESC to clear it.")
+	} else if len(filtered) == 0 {
 		todoDisplay.WriteString("No todos found.\n\nPress Tab to add your first todo!")
 	} else {
 		for i, todo := range filtered {
 			cursor := "  "
 			style := normalStyle
-			if i == m.todoCursor && !m.todoInputMode {
+			selected := i == m.todoCursor && !m.todoInputMode
+			if selected {
 				cursor = "▶ "
 				style = selectedStyle
 			}
+			dueState := todoDueState(todo, now)
+			if color, ok := dueColors[dueState]; ok {
+				if selected {
+					style = style.Background(color)
+				} else {
+					style = style.Foreground(color).Bold(true)
+				}
+			}
 			
 			status := "☐"
 			text := todo.Text
+			if todo.Priority != "" {
+				text = fmt.Sprintf("(%s) %s", todo.Priority, text)
+			}
 			timeInfo := fmt.Sprintf(" (created %s)", formatTimeRelative(todo.CreatedAt))
+			switch {
+			case dueState == todoOverdue:
+				timeInfo = fmt.Sprintf(" (overdue, due %s)", formatTodoDate(todo.Due, now))
+			case todo.Due != "":
+				timeInfo = fmt.Sprintf(" (due %s)", formatTodoDate(todo.Due, now))
+			case todo.Scheduled != "":
+				timeInfo = fmt.Sprintf(" (scheduled %s)", formatTodoDate(todo.Scheduled, now))
+			}
+			if todo.Notes != "" {
+				timeInfo += " 📝"
+			}
 			
 			if todo.Completed {
 				status = "✅"
@@ -323,22 +449,45 @@ func (m model) viewTodoList() string {
 			
 			todoDisplay.WriteString(style.Render(fmt.Sprintf("%s%s %s%s", cursor, status, text, timeInfo)) + "\n")
 		}
+		if i := m.selectedTodoIndex(); i >= 0 && !m.todoInputMode {
+			todo := m.todoItems[i]
+			var details []string
+			if todo.Due != "" && todo.Scheduled != "" {
+				details = append(details, fmt.Sprintf("scheduled %s", formatTodoDate(todo.Scheduled, now)))
+			}
+			if todo.Notes != "" {
+				details = append(details, "📝 "+todo.Notes)
+			}
+			if len(details) > 0 {
+				todoDisplay.WriteString("\n" + notesStyle.Render(strings.Join(details, "\n")))
+			}
+		}
 	}
 	
 	todoList := todoListStyle.Render(todoDisplay.String())
 	
 	var inputDisplay string
 	if m.todoInputMode {
-		inputPrompt := "Add new todo:"
+		inputPrompt := map[string]string{
+			"":       "Add new todo (e.g. Pay rent (A) due:fri #bills +home):",
+			"edit":   "Edit todo:",
+			"note":   "Notes:",
+			"search": "Search (#tag +project pri:A due:fri overdue today notes or text):",
+		}[m.todoInputKind]
 		inputText := fmt.Sprintf("▶ %s█", m.todoInput)
 		inputDisplay = inputStyle.Render(inputPrompt + "\n" + inputText)
 	}
 	
 	var helpText string
-	if m.todoInputMode {
-		helpText = "Type todo text • Enter to add • ESC to cancel"
-	} else {
-		helpText = "Enter to toggle • D to delete • F to filter • Tab to add • ↑/↓ to navigate • ESC to go back"
+	switch {
+	case m.todoInputMode && m.todoInputKind == "search":
+		helpText = "Enter to search • ESC to clear the search"
+	case m.todoInputMode && m.todoInputKind == "note":
+		helpText = "Enter to save • ESC to cancel"
+	case m.todoInputMode:
+		helpText = "(A)-(C) priority • #tags • +projects\ndue: and sched: take today, tomorrow, fri, +3d or 2026-12-25\nEnter to save • ESC to cancel"
+	default:
+		helpText = "Enter to toggle • D to delete • Tab to add • ↑/↓ to navigate\nE to edit • N for notes • P for priority\nF to filter • / to search • S to sort • ESC to go back"
 	}
 	help := helpStyle.Render(helpText)
 	
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const todoDateLayout = "2006-01-02"
+
+var todoPriorities = []string{"A", "B", "C"}
+
+// Orders the todo list can be sorted in; "created" keeps the order todos
+// were added.
+var todoSorts = []string{"created", "priority", "due", "scheduled", "project", "text"}
+
+// Where a todo's due date stands.
+const (
+	todoDueNone = iota
+	todoDueLater
+	todoDueToday
+	todoOverdue
+)
+
+var todoWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// parseTodoDate reads a date as "today", "tomorrow", "yesterday", a
+// weekday ("fri" is the next Friday after today), an offset ("+3d", "+2w",
+// "+1m") or YYYY-MM-DD, and returns it as YYYY-MM-DD.
+func parseTodoDate(input string, now time.Time) (string, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch input {
+	case "today", "tod":
+		return today.Format(todoDateLayout), nil
+	case "tomorrow", "tom":
+		return today.AddDate(0, 0, 1).Format(todoDateLayout), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1).Format(todoDateLayout), nil
+	}
+	if day, ok := todoWeekdays[input]; ok {
+		days := (int(day)-int(today.Weekday())+6)%7 + 1
+		return today.AddDate(0, 0, days).Format(todoDateLayout), nil
+	}
+	if strings.HasPrefix(input, "+") && len(input) > 2 {
+		n, err := strconv.Atoi(input[1 : len(input)-1])
+		if err == nil && n >= 0 {
+			switch input[len(input)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n).Format(todoDateLayout), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n).Format(todoDateLayout), nil
+			case 'm':
+				return today.AddDate(0, n, 0).Format(todoDateLayout), nil
+			}
+		}
+	}
+	if date, err := time.ParseInLocation(todoDateLayout, input, now.Location()); err == nil {
+		return date.Format(todoDateLayout), nil
+	}
+	return "", fmt.Errorf("%q isn't a date (try today, tomorrow, fri, +3d, +2w or 2026-12-25)", input)
+}
+
+// parseTodoLine reads a todo typed as one line, such as
+// "Pay rent (A) due:fri #bills +home". "(A)" to "(C)" set the priority,
+// "due:" and "sched:" take dates, and #tags and +projects are picked out
+// of the text, where they stay.
+func parseTodoLine(line string, now time.Time) (TodoItem, error) {
+	var todo TodoItem
+	var words []string
+	for _, word := range strings.Fields(line) {
+		lower := strings.ToLower(word)
+		switch {
+		case len(word) == 3 && word[0] == '(' && word[2] == ')' && indexOf(todoPriorities, strings.ToUpper(word[1:2])) >= 0:
+			todo.Priority = strings.ToUpper(word[1:2])
+		case strings.HasPrefix(lower, "due:"):
+			date, err := parseTodoDate(word[4:], now)
+			if err != nil {
+				return TodoItem{}, fmt.Errorf("due: %v", err)
+			}
+			todo.Due = date
+		case strings.HasPrefix(lower, "sched:"):
+			date, err := parseTodoDate(word[6:], now)
+			if err != nil {
+				return TodoItem{}, fmt.Errorf("sched: %v", err)
+			}
+			todo.Scheduled = date
+		default:
+			words = append(words, word)
+		}
+	}
+	todo.Text = strings.Join(words, " ")
+	if todo.Text == "" {
+		return TodoItem{}, fmt.Errorf("the todo needs some text")
+	}
+	todo.Tags, todo.Projects = todoTagsAndProjects(todo.Text)
+	return todo, nil
+}
+
+// todoTagsAndProjects picks the #tags and +projects out of some text.
+func todoTagsAndProjects(text string) (tags, projects []string) {
+	for _, word := range strings.Fields(text) {
+		word = strings.TrimRight(word, ".,;:!?")
+		if len(word) < 2 || !isTodoNameStart(word[1]) {
+			continue
+		}
+		switch word[0] {
+		case '#':
+			tags = appendUnique(tags, word[1:])
+		case '+':
+			projects = appendUnique(projects, word[1:])
+		}
+	}
+	return tags, projects
+}
+
+// isTodoNameStart stops "#1" and "+3d" being read as a tag or project.
+func isTodoNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if strings.EqualFold(existing, value) {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// formatTodoLine writes a todo back as the line it could have been typed
+// as, for editing.
+func formatTodoLine(todo TodoItem) string {
+	line := todo.Text
+	if todo.Priority != "" {
+		line += " (" + todo.Priority + ")"
+	}
+	if todo.Due != "" {
+		line += " due:" + todo.Due
+	}
+	if todo.Scheduled != "" {
+		line += " sched:" + todo.Scheduled
+	}
+	return line
+}
+
+// todoDueState says whether a todo not yet done is overdue, due today or
+// due later.
+func todoDueState(todo TodoItem, now time.Time) int {
+	if todo.Due == "" || todo.Completed {
+		return todoDueNone
+	}
+	today := now.Format(todoDateLayout)
+	switch {
+	case todo.Due < today:
+		return todoOverdue
+	case todo.Due == today:
+		return todoDueToday
+	}
+	return todoDueLater
+}
+
+// formatTodoDate shows a date relative to today where that reads better:
+// "today", "tomorrow", "yesterday", a weekday within the week, or the date.
+func formatTodoDate(date string, now time.Time) string {
+	t, err := time.ParseInLocation(todoDateLayout, date, now.Location())
+	if err != nil {
+		return date
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days := int(math.Round(t.Sub(today).Hours() / 24)) // a day can be 23 or 25 hours
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 1 && days < 7:
+		return t.Format("Mon")
+	case t.Year() == now.Year():
+		return t.Format("Jan 2")
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// matchesTodoQuery checks a todo against a search such as
+// "#work +site pri:A overdue report". Every term has to match: #tag,
+// +project, pri:X, due:DATE (due by then), sched:DATE (scheduled by then),
+// "overdue", "today" (due today or overdue), "notes" (has notes), and any
+// other word is looked for in the text and notes.
+func matchesTodoQuery(todo TodoItem, query string, now time.Time) bool {
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if !matchesTodoTerm(todo, term, now) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesTodoTerm(todo TodoItem, term string, now time.Time) bool {
+	has := func(list []string, value string) bool {
+		for _, item := range list {
+			if strings.EqualFold(item, value) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case len(term) > 1 && term[0] == '#':
+		return has(todo.Tags, term[1:])
+	case len(term) > 1 && term[0] == '+' && isTodoNameStart(term[1]):
+		return has(todo.Projects, term[1:])
+	case strings.HasPrefix(term, "pri:"):
+		return strings.EqualFold(todo.Priority, term[4:])
+	case strings.HasPrefix(term, "due:"), strings.HasPrefix(term, "sched:"):
+		field, value, _ := strings.Cut(term, ":")
+		date, err := parseTodoDate(value, now)
+		if err != nil {
+			return false
+		}
+		have := todo.Due
+		if field == "sched" {
+			have = todo.Scheduled
+		}
+		return have != "" && have <= date
+	case term == "overdue":
+		return todoDueState(todo, now) == todoOverdue
+	case term == "today":
+		return todoDueState(todo, now) >= todoDueToday
+	case term == "notes":
+		return todo.Notes != ""
+	}
+	return strings.Contains(strings.ToLower(todo.Text+"\n"+todo.Notes), term)
+}
+
+// sortTodos orders todos by one of todoSorts. Todos missing what they are
+// sorted by go last, and ties keep their order.
+func sortTodos(todos []TodoItem, by string) {
+	key := func(todo TodoItem) string {
+		switch by {
+		case "priority":
+			return todo.Priority
+		case "due":
+			return todo.Due
+		case "scheduled":
+			return todo.Scheduled
+		case "project":
+			if len(todo.Projects) > 0 {
+				return strings.ToLower(todo.Projects[0])
+			}
+		case "text":
+			return strings.ToLower(todo.Text)
+		}
+		return ""
+	}
+	if by == "created" || by == "" {
+		return
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		a, b := key(todos[i]), key(todos[j])
+		if a == "" || b == "" {
+			return a != "" && b == ""
+		}
+		return a < b
+	})
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	maxTodoBackups     = 10
+	todoBackupInterval = 15 * time.Minute
+	todoLockTimeout    = 2 * time.Second
+	todoLockStale      = 10 * time.Second
+	todoWatchInterval  = 2 * time.Second
+
+	// todoSchemaVersion is the version of the todo file this bdt writes.
+	// Version 1 was a bare list of todos with just their text, status and
+	// timestamps.
+	todoSchemaVersion = 2
+)
+
+// errTodosChanged means another bdt saved the todo file since this one last
+// read it.
+var errTodosChanged = errors.New("the todo list was changed in another window")
+
+// todoCorruptError is a todo file that exists but can't be read as a list.
+type todoCorruptError struct {
+	Detail string
+}
+
+func (e *todoCorruptError) Error() string {
+	return "the todo file is damaged (" + e.Detail + ")"
+}
+
+// todoFileStamp identifies a version of the todo file, so changes made by
+// another process can be noticed. The zero stamp is a missing file.
+type todoFileStamp struct {
+	ModTime time.Time
+	Size    int64
+}
+
+type todosFile struct {
+	Version int        `json:"version"`
+	Todos   []TodoItem `json:"todos"`
+}
+
+// todoWatchMsg asks the todo list to check the file for outside changes.
+// Its id ties it to one visit to the list, so an old check left running
+// from an earlier visit dies out.
+type todoWatchMsg struct {
+	id int
+}
+
+func getTodoFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "todos.json"
+	}
+	return filepath.Join(homeDir, ".big-dumb-toolbox-todos.json")
+}
+
+func getTodoBackupDir() string {
+	return filepath.Join(getDataDir(), "backups")
+}
+
+func statTodoFile() (todoFileStamp, error) {
+	info, err := os.Stat(getTodoFilePath())
+	if os.IsNotExist(err) {
+		return todoFileStamp{}, nil
+	}
+	if err != nil {
+		return todoFileStamp{}, err
+	}
+	return todoFileStamp{ModTime: info.ModTime(), Size: info.Size()}, nil
+}
+
+// loadTodos reads the todo list. A missing file is an empty list; a file
+// that can't be read or parsed is an error, never an empty list, so that a
+// later save can't overwrite it.
+func loadTodos() ([]TodoItem, todoFileStamp, error) {
+	stamp, err := statTodoFile()
+	if err != nil {
+		return nil, todoFileStamp{}, err
+	}
+	data, err := os.ReadFile(getTodoFilePath())
+	if os.IsNotExist(err) {
+		return []TodoItem{}, todoFileStamp{}, nil
+	}
+	if err != nil {
+		return nil, stamp, err
+	}
+	todos, err := parseTodos(data)
+	return todos, stamp, err
+}
+
+// parseTodos reads a todo file of any schema version, migrating older
+// files up to the current one.
+func parseTodos(data []byte) ([]TodoItem, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return nil, &todoCorruptError{Detail: "the file is empty"}
+	}
+	file := todosFile{Version: 1}
+	var err error
+	if strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(data, &file.Todos)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, &todoCorruptError{Detail: describeJSONError(data, 0, err)}
+	}
+	if file.Version > todoSchemaVersion {
+		return nil, fmt.Errorf("the todo file was saved by a newer bdt (version %d); update bdt to open it", file.Version)
+	}
+	todos := migrateTodos(file.Todos, file.Version)
+	if todos == nil {
+		todos = []TodoItem{}
+	}
+	return todos, nil
+}
+
+// migrateTodos brings todos read from an older schema version up to date.
+func migrateTodos(todos []TodoItem, version int) []TodoItem {
+	if version < 2 {
+		// Version 2 added metadata; pick up tags and projects already
+		// written into the text
+		for i := range todos {
+			todos[i].Tags, todos[i].Projects = todoTagsAndProjects(todos[i].Text)
+		}
+	}
+	return todos
+}
+
+// saveTodos writes the todo list whatever is on disk.
+func saveTodos(todos []TodoItem) error {
+	_, err := writeTodos(todos, nil)
+	return err
+}
+
+// writeTodos saves the list while holding the todo lock: it backs up the
+// current file, then writes the new list to a temporary file and renames it
+// into place so a crash never leaves half a file. With an expected stamp,
+// the save is refused with errTodosChanged if the file has moved on since.
+func writeTodos(todos []TodoItem, expected *todoFileStamp) (todoFileStamp, error) {
+	unlock, err := lockTodoFile()
+	if err != nil {
+		return todoFileStamp{}, err
+	}
+	defer unlock()
+
+	if expected != nil {
+		current, err := statTodoFile()
+		if err != nil {
+			return todoFileStamp{}, err
+		}
+		if !current.ModTime.Equal(expected.ModTime) || current.Size != expected.Size {
+			return todoFileStamp{}, errTodosChanged
+		}
+	}
+
+	if todos == nil {
+		todos = []TodoItem{}
+	}
+	data, err := json.MarshalIndent(todosFile{Version: todoSchemaVersion, Todos: todos}, "", "  ")
+	if err != nil {
+		return todoFileStamp{}, err
+	}
+	if err := backupTodoFile(); err != nil {
+		return todoFileStamp{}, fmt.Errorf("backup failed: %v", err)
+	}
+	if err := writeFileAtomic(getTodoFilePath(), data, 0600); err != nil {
+		return todoFileStamp{}, err
+	}
+	return statTodoFile()
+}
+
+// writeFileAtomic writes data next to path and renames it over path.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// lockTodoFile takes the advisory lock every bdt holds while saving todos:
+// a lock file created only if it doesn't exist. A lock older than
+// todoLockStale is left over from a crash and is broken.
+func lockTodoFile() (func(), error) {
+	lockPath := getTodoFilePath() + ".lock"
+	deadline := time.Now().Add(todoLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > todoLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the todo list is locked by another bdt (%s)", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// todoBackups lists the backups of the todo file, newest first.
+func todoBackups() []string {
+	matches, _ := filepath.Glob(filepath.Join(getTodoBackupDir(), "todos-*.json"))
+	// Timestamped names sort oldest first
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches
+}
+
+// backupTodoFile copies the todo file into the backups folder, at most once
+// every todoBackupInterval, keeping the newest maxTodoBackups.
+func backupTodoFile() error {
+	data, err := os.ReadFile(getTodoFilePath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	backups := todoBackups()
+	if len(backups) > 0 {
+		if info, err := os.Stat(backups[0]); err == nil && time.Since(info.ModTime()) < todoBackupInterval {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(getTodoBackupDir(), 0700); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("todos-%s.json", time.Now().Format("20060102-150405.000"))
+	if err := writeFileAtomic(filepath.Join(getTodoBackupDir(), name), data, 0600); err != nil {
+		return err
+	}
+	backups = todoBackups()
+	for len(backups) > maxTodoBackups {
+		os.Remove(backups[len(backups)-1])
+		backups = backups[:len(backups)-1]
+	}
+	return nil
+}
+
+// backupTime reads when a backup was taken from its name.
+func backupTime(path string) time.Time {
+	stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "todos-"), ".json")
+	t, _ := time.ParseInLocation("20060102-150405.000", stamp, time.Local)
+	return t
+}
+
+// setAsideCorruptTodos renames a damaged todo file out of the way, keeping
+// it for the user to rescue by hand, and returns where it went.
+func setAsideCorruptTodos() (string, error) {
+	aside := fmt.Sprintf("%s.corrupt-%s", getTodoFilePath(), time.Now().Format("20060102-150405"))
+	if err := os.Rename(getTodoFilePath(), aside); err != nil {
+		return "", err
+	}
+	return aside, nil
+}
+
+func watchTodoFile(id int) tea.Cmd {
+	return tea.Tick(todoWatchInterval, func(time.Time) tea.Msg {
+		return todoWatchMsg{id: id}
+	})
+}
+
+// openTodoList loads the list fresh and starts watching the file for
+// changes made by other bdt windows.
+func (m model) openTodoList() (model, tea.Cmd) {
+	m.state = todoListView
+	m.todoInputMode = false
+	m.todoInput = ""
+	m.todoCursor = 0
+	m.todoMessage = ""
+	m = m.reloadTodos()
+	m.todoWatchID++
+	return m, watchTodoFile(m.todoWatchID)
+}
+
+// reloadTodos reads the todo file into the model, switching to the
+// recovery screen if it is damaged.
+func (m model) reloadTodos() model {
+	todos, stamp, err := loadTodos()
+	m.todoStamp = stamp
+	m.todoLoadError = ""
+	if err != nil {
+		m.todoItems = nil
+		m.todoLoadError = err.Error()
+		m.todoMessage = ""
+		m.todoBackups = todoBackups()
+		m.todoBackupCursor = 0
+		return m
+	}
+	m.todoItems = todos
+	m.todoCursor = min(m.todoCursor, max(len(m.getFilteredTodos())-1, 0))
+	return m
+}
+
+// todoChange is an edit to the list, made by ID so that it can be made
+// again on a list reloaded from disk. It is given a copy of the list, and
+// reports false when the todo it edits is gone.
+type todoChange func(todos []TodoItem) ([]TodoItem, bool)
+
+// editTodo makes a change to the todo with the given ID.
+func editTodo(id string, edit func(todo *TodoItem)) todoChange {
+	return func(todos []TodoItem) ([]TodoItem, bool) {
+		todos = append([]TodoItem{}, todos...)
+		for i := range todos {
+			if todos[i].ID == id {
+				edit(&todos[i])
+				return todos, true
+			}
+		}
+		return todos, false
+	}
+}
+
+// storeTodos makes a change and saves the list. If another window has saved
+// since this one loaded it, the other window's list is loaded and the change
+// is made again on that, so that neither clobbers the other. The list only
+// takes the change once it is saved, and storeTodos reports whether it was.
+func (m model) storeTodos(change todoChange, success, failure string) (model, bool) {
+	for attempt := 0; ; attempt++ {
+		todos, ok := change(append([]TodoItem{}, m.todoItems...))
+		if !ok {
+			m.todoMessage = "❌ That todo was deleted in another window"
+			return m, false
+		}
+		stamp, err := writeTodos(todos, &m.todoStamp)
+		switch {
+		case errors.Is(err, errTodosChanged) && attempt == 0:
+			if m = m.reloadTodos(); m.todoLoadError != "" {
+				return m, false
+			}
+			continue
+		case errors.Is(err, errTodosChanged):
+			m = m.reloadTodos()
+			m.todoMessage = "❌ The list keeps changing in another window • reloaded it, so try again"
+		case err != nil:
+			m.todoMessage = fmt.Sprintf("%s: %v", failure, err)
+		default:
+			m.todoItems = todos
+			m.todoStamp = stamp
+			m.todoMessage = success
+			return m, true
+		}
+		return m, false
+	}
+}
+
+// checkTodoFile reloads the list when another process has changed the file.
+func (m model) checkTodoFile(msg todoWatchMsg) (tea.Model, tea.Cmd) {
+	if msg.id != m.todoWatchID || m.state != todoListView {
+		return m, nil
+	}
+	stamp, err := statTodoFile()
+	if err == nil && (!stamp.ModTime.Equal(m.todoStamp.ModTime) || stamp.Size != m.todoStamp.Size) {
+		m = m.reloadTodos()
+		if m.todoLoadError == "" {
+			m.todoMessage = "🔄 Reloaded changes from another window"
+		}
+	}
+	return m, watchTodoFile(m.todoWatchID)
+}
+
+// updateTodoRecovery handles the recovery screen shown instead of the list
+// when the todo file is damaged. Nothing is written until the user picks a
+// way out.
+func (m model) updateTodoRecovery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = menuView
+	case "up", "k":
+		if m.todoBackupCursor > 0 {
+			m.todoBackupCursor--
+		}
+	case "down", "j":
+		if m.todoBackupCursor < len(m.todoBackups)-1 {
+			m.todoBackupCursor++
+		}
+	case "enter":
+		if m.todoBackupCursor >= len(m.todoBackups) {
+			break
+		}
+		backup := m.todoBackups[m.todoBackupCursor]
+		data, err := os.ReadFile(backup)
+		if err != nil {
+			m.todoMessage = fmt.Sprintf("❌ Couldn't read the backup: %v", err)
+			break
+		}
+		todos, err := parseTodos(data)
+		if err != nil {
+			m.todoMessage = fmt.Sprintf("❌ That backup is damaged too: %v", err)
+			break
+		}
+		aside, err := setAsideCorruptTodos()
+		if err != nil {
+			m.todoMessage = fmt.Sprintf("❌ Couldn't move the damaged file aside: %v", err)
+			break
+		}
+		if err := saveTodos(todos); err != nil {
+			m.todoMessage = fmt.Sprintf("❌ Restore failed: %v", err)
+			break
+		}
+		m = m.reloadTodos()
+		m.todoMessage = fmt.Sprintf("✅ Restored %d todos from %s • damaged file kept as %s", len(todos), backupTime(backup).Format("Jan 2 15:04"), filepath.Base(aside))
+	case "n":
+		aside, err := setAsideCorruptTodos()
+		if err != nil {
+			m.todoMessage = fmt.Sprintf("❌ Couldn't move the damaged file aside: %v", err)
+			break
+		}
+		m = m.reloadTodos()
+		m.todoMessage = "✅ Started a new list • damaged file kept as " + filepath.Base(aside)
+	case "r":
+		m = m.reloadTodos()
+		if m.todoLoadError == "" {
+			m.todoMessage = "✅ The todo file reads fine now"
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewTodoRecovery() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#DC2626")).
+		Padding(1, 2).
+		MarginBottom(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#DC2626")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#DC2626")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(70)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#DC2626"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render("⚠️  Todo List Needs Recovery")
+
+	var rows []string
+	rows = append(rows, "❌ "+m.todoLoadError, "", getTodoFilePath()+" has been left as it is.", "")
+	if len(m.todoBackups) == 0 {
+		rows = append(rows, "There are no backups to restore.")
+	} else {
+		rows = append(rows, "Restore a backup:")
+	}
+	for i, backup := range m.todoBackups {
+		count := "damaged"
+		if data, err := os.ReadFile(backup); err == nil {
+			if todos, err := parseTodos(data); err == nil {
+				count = strconv.Itoa(len(todos)) + " todos"
+			}
+		}
+		row := fmt.Sprintf("%-22s %s", backupTime(backup).Format("Mon Jan 2 15:04:05"), count)
+		if i == m.todoBackupCursor {
+			row = selectedStyle.Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	elements := []string{title, listStyle.Render(strings.Join(rows, "\n"))}
+	if m.todoMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.todoMessage))
+	}
+	help := "N to set the damaged file aside and start a new list • R to read it again • ESC to go back"
+	if len(m.todoBackups) > 0 {
+		help = "↑/↓ to select • Enter to restore (the damaged file is kept)\n" + help
+	}
+	elements = append(elements, helpStyle.Render(help))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
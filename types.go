@@ -10,24 +10,274 @@ const (
 	diceRollerView
 	wheelSpinnerView
 	rpgCharacterView
+	rpgRaceSelectionView
 	rpgClassSelectionView
+	rpgBackgroundSelectionView
+	rpgAbilityMethodView
+	rpgAbilityScoresView
+	rpgRosterView
+	rpgEditView
+	rpgLevelUpView
+	rpgPackView
+	rpgEquipmentView
+	rpgExportView
+	rpgPersonaView
+	rpgPartyView
+	rpgSpellsView
 	todoListView
 	pomodoroView
 	base64View
 	systemInfoView
 	networkInfoView
 	unitConverterView
+	shareView
+	combatView
+	encounterView
 )
 
 type ClassStats struct {
-	Primary   string
-	Secondary string
+	Primary             string        `json:"primary"`
+	Secondary           string        `json:"secondary"`
+	HitDie              int           `json:"hit_die"`
+	SavingThrows        []string      `json:"saving_throws"`
+	SkillChoices        int           `json:"skill_choices"`
+	SkillOptions        []string      `json:"skill_options,omitempty"` // nil means any skill
+	WeaponProficiencies []string      `json:"weapon_proficiencies"`    // "simple", "martial" or weapon names
+	Spellcasting        *Spellcasting `json:"spellcasting,omitempty"`
+}
+
+// Spellcasting describes how a class casts spells. The tables give the
+// number at each class level, 1 to 20.
+type Spellcasting struct {
+	Ability   string `json:"ability"`
+	Slots     string `json:"slots"`                // "full", "half" or "pact"
+	Cantrips  []int  `json:"cantrips,omitempty"`   // cantrips known
+	Known     []int  `json:"known,omitempty"`      // spells known, or in the spellbook for a class that also prepares
+	Prepares  string `json:"prepares,omitempty"`   // "level" or "half level": prepares its ability modifier plus that many
+	SpellList string `json:"spell_list,omitempty"` // another class whose spell list it uses
 }
 
 type StartingGear struct {
-	Weapons []string
-	Armor   []string
-	Items   []string
+	Weapons []string `json:"weapons"`
+	Armor   []string `json:"armor"`
+	Items   []string `json:"items"`
+}
+
+type RaceStats struct {
+	AbilityIncreases map[string]int `json:"ability_increases"`
+	FlexibleIncrease int            `json:"flexible_increase,omitempty"` // abilities of the player's choice that get +1
+	Speed            int            `json:"speed"`
+	Size             string         `json:"size"`
+	Traits           []string       `json:"traits"`
+	Languages        []string       `json:"languages"`
+	Skills           []string       `json:"skills,omitempty"`          // fixed skill proficiencies
+	SkillChoices     int            `json:"skill_choices,omitempty"`   // skills of the player's choice
+	Weapons          []string       `json:"weapons,omitempty"`         // weapon proficiencies
+	HitPointBonus    int            `json:"hit_point_bonus,omitempty"` // extra hit points per level
+	Names            NameTables     `json:"names"`
+}
+
+// NameTables are the names a race's name generator learns from. Alternate
+// names, such as tiefling virtue names, are sometimes used on their own.
+type NameTables struct {
+	First     []string `json:"first"`
+	Family    []string `json:"family,omitempty"`
+	Alternate []string `json:"alternate,omitempty"`
+}
+
+type BackgroundStats struct {
+	Skills      []string          `json:"skills"`
+	Tools       []string          `json:"tools,omitempty"`
+	Languages   int               `json:"languages,omitempty"`
+	Equipment   []string          `json:"equipment"`
+	Gold        int               `json:"gold"`
+	Feature     string            `json:"feature"`
+	Personality PersonalityTables `json:"personality"`
+}
+
+// PersonalityTables are a background's tables for rolling personality and a
+// backstory hook. Hooks can use {name}, {race}, {class}, {npc} (another
+// name of the character's race) and {years}.
+type PersonalityTables struct {
+	Traits []string `json:"traits"`
+	Ideals []string `json:"ideals"`
+	Bonds  []string `json:"bonds"`
+	Flaws  []string `json:"flaws"`
+	Hooks  []string `json:"hooks"`
+}
+
+type Character struct {
+	ID          string         `json:"id,omitempty"` // set once saved to the roster
+	Name        string         `json:"name,omitempty"`
+	Alignment   string         `json:"alignment,omitempty"`
+	Race        string         `json:"race"`
+	Class       string         `json:"class"`
+	Background  string         `json:"background"`
+	Level       int            `json:"level,omitempty"`
+	XP          int            `json:"xp,omitempty"`
+	HitDieRolls []int          `json:"hit_die_rolls,omitempty"` // hit points rolled or averaged at each level after the first
+	Method      string         `json:"method,omitempty"`        // how the ability scores were generated
+	Abilities   map[string]int `json:"abilities"`
+	RaceBonus   map[string]int `json:"race_bonus,omitempty"`
+	Skills      []string       `json:"skills,omitempty"` // chosen class and racial skills
+	Gear        StartingGear   `json:"gear"`
+	Gold        int            `json:"gold"`
+	Equipment   []string       `json:"equipment,omitempty"`       // starting equipment choices made
+	Wealth      string         `json:"starting_wealth,omitempty"` // the roll, when starting wealth was taken instead
+	Notes       string         `json:"notes,omitempty"`
+	Traits      []string       `json:"personality_traits,omitempty"`
+	Ideal       string         `json:"ideal,omitempty"`
+	Bond        string         `json:"bond,omitempty"`
+	Flaw        string         `json:"flaw,omitempty"`
+	Backstory   string         `json:"backstory,omitempty"`
+	Cantrips    []string       `json:"cantrips,omitempty"`
+	Spells      []string       `json:"spells,omitempty"`     // spells known, in the spellbook or prepared
+	Prepared    []string       `json:"prepared,omitempty"`   // spells prepared from the spellbook
+	SlotsUsed   []int          `json:"slots_used,omitempty"` // spell slots spent at each slot level, 1st first
+	Pack        string         `json:"pack,omitempty"`       // ID of the content pack it was made with
+}
+
+type ArmorStats struct {
+	BaseAC   int    `json:"base_ac"`
+	Category string `json:"category"` // "light", "medium" or "heavy"
+}
+
+type WeaponAttack struct {
+	Name        string
+	AttackBonus int
+	Damage      string
+	DamageType  string
+	Proficient  bool
+}
+
+// DerivedStats are the numbers worked out from a character's scores, class,
+// race and gear.
+type DerivedStats struct {
+	Level              int
+	Modifiers          map[string]int
+	Proficiency        int
+	HitPoints          int
+	ArmorClass         int
+	ArmorSource        string
+	Initiative         int
+	Speed              int
+	PassivePerception  int
+	Saves              map[string]int
+	SaveProficiencies  []string
+	Skills             map[string]int
+	SkillProficiencies []string
+	Attacks            []WeaponAttack
+}
+
+type WeaponStats struct {
+	Damage     string   `json:"damage"`
+	DamageType string   `json:"damage_type"`
+	Category   string   `json:"category"` // "simple" or "martial"
+	Ranged     bool     `json:"ranged,omitempty"`
+	Finesse    bool     `json:"finesse,omitempty"`
+	Properties []string `json:"properties,omitempty"`
+}
+
+// ContentPack holds the classes, races, backgrounds, gear and gold rules the
+// character creator offers. Packs are JSON files; entries in a pack that
+// extends another replace or add to the base pack's entries by name.
+type ContentPack struct {
+	ID             string           `json:"id"`
+	Name           string           `json:"name"`
+	Description    string           `json:"description,omitempty"`
+	Extends        string           `json:"extends,omitempty"`
+	Classes        []PackClass      `json:"classes,omitempty"`
+	Races          []PackRace       `json:"races,omitempty"`
+	Backgrounds    []PackBackground `json:"backgrounds,omitempty"`
+	Weapons        []PackWeapon     `json:"weapons,omitempty"`
+	Armor          []PackArmor      `json:"armor,omitempty"`
+	EquipmentPacks []EquipmentPack  `json:"equipment_packs,omitempty"`
+	Spells         []PackSpell      `json:"spells,omitempty"`
+	Monsters       []PackMonster    `json:"monsters,omitempty"`
+
+	Source string   `json:"-"` // "built-in" or the file it was loaded from
+	Errors []string `json:"-"` // problems that stop the pack being used
+}
+
+type PackClass struct {
+	Name string `json:"name"`
+	ClassStats
+	Roles          []string          `json:"roles,omitempty"` // party roles, best first: tank, healer, damage or utility
+	Equipment      []EquipmentChoice `json:"equipment"`
+	StartingWealth string            `json:"starting_wealth"` // dice for gold instead of equipment, e.g. "5d4x10"
+}
+
+// EquipmentChoice is one line of a class's starting equipment: the options
+// to choose between, each a list of items. A line with one option is given
+// outright. "{simple}", "{martial melee}" and the like stand for any weapon
+// of that kind.
+type EquipmentChoice [][]string
+
+// EquipmentPack is a bundle such as an explorer's pack, handed out as its
+// contents.
+type EquipmentPack struct {
+	Name     string   `json:"name"`
+	Contents []string `json:"contents"`
+}
+
+// EquipmentSelection is what the player chose from their class's starting
+// equipment.
+type EquipmentSelection struct {
+	Options []int    // option taken on each line of the class's equipment
+	Weapons []string // weapons picked for the "{simple}" style slots, in order
+	Wealth  bool     // take starting wealth instead of equipment
+}
+
+type PackRace struct {
+	Name string `json:"name"`
+	RaceStats
+}
+
+type PackBackground struct {
+	Name string `json:"name"`
+	BackgroundStats
+}
+
+type PackWeapon struct {
+	Name string `json:"name"`
+	WeaponStats
+}
+
+type PackArmor struct {
+	Name string `json:"name"`
+	ArmorStats
+}
+
+// PackMonster is the little of a monster's stat block the encounter builder
+// and initiative tracker need.
+type PackMonster struct {
+	Name       string `json:"name"`
+	CR         string `json:"cr"` // challenge rating, e.g. "1/4" or "5"
+	Type       string `json:"type"`
+	Size       string `json:"size"`
+	AC         int    `json:"ac"`
+	HP         int    `json:"hp"`
+	Initiative int    `json:"initiative,omitempty"` // initiative modifier
+}
+
+type PackSpell struct {
+	Name          string   `json:"name"`
+	Level         int      `json:"level"` // 0 for a cantrip
+	School        string   `json:"school"`
+	CastingTime   string   `json:"casting_time"`
+	Range         string   `json:"range"`
+	Components    string   `json:"components"`
+	Duration      string   `json:"duration"`
+	Concentration bool     `json:"concentration,omitempty"`
+	Ritual        bool     `json:"ritual,omitempty"`
+	Classes       []string `json:"classes"`
+	Description   string   `json:"description"`
+}
+
+
+type WheelItem struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
 }
 
 type TodoItem struct {
@@ -36,6 +286,12 @@ type TodoItem struct {
 	Completed   bool       `json:"completed"`
 	CreatedAt   time.Time  `json:"created_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Priority    string     `json:"priority,omitempty"`  // "A", "B" or "C"
+	Due         string     `json:"due,omitempty"`       // YYYY-MM-DD
+	Scheduled   string     `json:"scheduled,omitempty"` // YYYY-MM-DD
+	Tags        []string   `json:"tags,omitempty"`      // #tags in the text
+	Projects    []string   `json:"projects,omitempty"`  // +projects in the text
+	Notes       string     `json:"notes,omitempty"`
 }
 
 type SystemInfo struct {
@@ -80,31 +336,125 @@ type model struct {
 	diceType     string
 	diceRolling  bool
 	diceRollTime time.Time
+	diceInput     string
+	diceInputMode bool
+	diceRoll      DiceRollResult
+	diceMessage   string
+	diceHistory        []DiceHistoryEntry
+	diceHistoryPersist bool
+	diceShowHistory    bool
+	diceHistoryCursor  int
+	diceShowCalc       bool
+	diceCalcInput      string
+	diceCalcReport     string
+	diceMacros         []RollMacro
+	diceShowMacros     bool
+	diceMacroSearch    string
+	diceMacroCursor    int
+	diceMacroAdding    bool
+	diceMacroInput     string
+	diceShowTray       bool
+	diceTray           []trayDie
+	diceTrayCursor     int
+	diceTrayPicker     int
 	
-	wheelItems     []string
-	wheelInput     string
-	wheelSpinning  bool
-	wheelSpinTime  time.Time
-	wheelResult    string
-	wheelSpinIndex int
-	wheelInputMode bool
+	wheelItems         []WheelItem
+	wheelInput         string
+	wheelSpinning      bool
+	wheelSpinTime      time.Time
+	wheelResult        string
+	wheelSpinIndex     int
+	wheelInputMode     bool
+	wheelWeightInput   string
+	wheelInputField    int
+	wheelAngle         float64 // wheel rotation in radians, clockwise
+	wheelSpinFrom      float64
+	wheelSpinVelocity  float64 // radians per second at the start of a spin
+	wheelSpinFriction  float64 // radians per second squared
+	wheelWinner        int
+	wheelCursor        int
+	wheelName          string
+	wheelSaved         []SavedWheel
+	wheelShowPicker    bool
+	wheelPickerCursor  int
+	wheelPrompt        string // "save", "rename", "import" or "export"
+	wheelPromptInput   string
+	wheelMessage       string
+	wheelEditing       bool
+	wheelUndo          [][]WheelItem
+	wheelMode          int
+	wheelModeN         int // teams, group size or winners to draw
+	wheelOutcome       []string
+	wheelEliminated    []string
+	wheelHistory       []WheelSpin
+	wheelShowHistory   bool
+	wheelHistoryCursor int
 	
-	rpgCharacter     map[string]int
-	rpgRolling       bool
-	rpgRollTime      time.Time
-	rpgClasses       []string
-	rpgClassCursor   int
-	rpgSelectedClass string
-	rpgGear          StartingGear
-	rpgGold          int
-	rpgExportStatus  string
+	rpgCharacter        Character
+	rpgRolling          bool
+	rpgRollTime         time.Time
+	rpgPack             ContentPack // the pack the character creator is using
+	rpgRaces            []string
+	rpgClasses          []string
+	rpgBackgrounds      []string
+	rpgRaceCursor       int
+	rpgClassCursor      int
+	rpgBackgroundCursor int
+	rpgAbilityMethod    int
+	rpgScoreValues      []int            // base scores in abilityNames order
+	rpgScoreRolls       []DiceRollResult // the roll behind each score, for rolled methods
+	rpgScoreCursor      int
+	rpgScoreHeld        int // score picked up for swapping, -1 for none
+	rpgRoster           []Character
+	rpgRosterCursor     int
+	rpgRosterRenaming   bool
+	rpgRosterInput      string
+	rpgRosterMessage    string
+	rpgRosterReturn     sessionState
+	rpgEditFields       []string
+	rpgEditCursor       int
+	rpgEditError        string
+	rpgLevelUpAverage   bool
+	rpgPacks            []ContentPack
+	rpgPackCursor       int
+	rpgPackMessage      string
+	rpgEquipment        EquipmentSelection
+	rpgEquipmentCursor  int
+	rpgEquipmentReturn  sessionState
+	rpgExportStatus     string
+	rpgExportCursor     int
+	rpgExportDirEditing bool
+	rpgExportDirInput   string
+	rpgPersonaCursor    int
+	rpgParty            []Character
+	rpgPartyRoles       []string // the role each member was picked for
+	rpgPartyCursor      int
+	rpgPartySettings    rpgSettings
+	rpgPartyFormat      int
+	rpgPartyEditing     bool
+	rpgPartyInput       string
+	rpgPartyMessage     string
+	rpgSpellCursor      int
+	rpgSpellSearch      string
+	rpgSpellSearching   bool
+	rpgSpellAll         bool // list every spell, not just the class's
+	rpgSpellMessage     string
 	
-	todoItems     []TodoItem
-	todoInput     string
-	todoInputMode bool
-	todoCursor    int
-	todoMessage   string
-	todoFilter    string
+	todoItems        []TodoItem
+	todoInput        string
+	todoInputMode    bool
+	todoCursor       int
+	todoMessage      string
+	todoFilter       string
+	todoInputKind    string // "" to add, "edit", "note" or "search"
+	todoEditID       string // the todo an "edit" or "note" box was opened on
+	todoQuery        string
+	todoSort         string // one of todoSorts
+	todoStamp        todoFileStamp // the version of the todo file on screen
+	todoWatchID      int
+	todoLoadError    string // set when the todo file is damaged
+	todoBackups      []string
+	todoBackupCursor int
 	
 	pomodoroRunning   bool
 	pomodoroStartTime time.Time
@@ -139,6 +489,50 @@ type model struct {
 	unitConverterInputMode  string // "value", "from", "to", "category"
 	unitConverterMessage    string
 	
+	shareInput          string
+	shareAsText         bool // share the input as a snippet rather than the file it names
+	shareField          int
+	shareAddresses      []shareAddress
+	shareAddressCursor  int
+	shareDownloadCursor int
+	shareTimeoutCursor  int
+	shareServer         *shareServer
+	shareID             int // ties status ticks to the running share
+	shareQRCode         string
+	shareMessage        string
+	
+	combatEncounter    Encounter
+	combatCursor       int
+	combatPrompt       string // "add", "damage", "heal", "temp", "condition", "initiative" or "save"
+	combatInput        string
+	combatMessage      string
+	combatSaved        []Encounter
+	combatShowPicker   bool
+	combatPickerCursor int
+	combatRoster       []Character
+	combatShowImport   bool
+	combatImportCursor int
+	
+	encounterParty         []PartyMember
+	encounterGroups        []EncounterGroup
+	encounterTables        []EncounterTable
+	encounterFocus         int // 0 party, 1 monsters, 2 encounter
+	encounterPartyCursor   int
+	encounterMonsterCursor int
+	encounterGroupCursor   int
+	encounterCRFilter      int // 0 for all, else 1 + index into challengeRatings
+	encounterTypeFilter    string
+	encounterSearch        string
+	encounterSearching     bool
+	encounterRoster        []Character
+	encounterShowImport    bool
+	encounterImportCursor  int
+	encounterShowTables    bool
+	encounterTableCursor   int
+	encounterPrompt        string // "table" or "entry"
+	encounterInput         string
+	encounterMessage       string
+	
 	width  int
 	height int
 }
\ No newline at end of file
@@ -4,10 +4,49 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 )
 
+// getDataDir returns the directory bdt keeps its data files in, creating it if needed.
+func getDataDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	dir := filepath.Join(homeDir, ".big-dumb-toolbox")
+	os.MkdirAll(dir, 0755)
+	return dir
+}
+
+// copyTextToClipboard puts text on the system clipboard using the platform's
+// clipboard command.
+func copyTextToClipboard(text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("pbcopy")
+	case "linux":
+		if _, err := exec.LookPath("wl-copy"); err == nil && os.Getenv("WAYLAND_DISPLAY") != "" {
+			cmd = exec.Command("wl-copy")
+		} else if _, err := exec.LookPath("xclip"); err == nil {
+			cmd = exec.Command("xclip", "-selection", "clipboard")
+		} else if _, err := exec.LookPath("xsel"); err == nil {
+			cmd = exec.Command("xsel", "--clipboard", "--input")
+		} else {
+			return fmt.Errorf("no suitable clipboard tool found (xclip, xsel or wl-copy required)")
+		}
+	case "windows":
+		cmd = exec.Command("clip")
+	default:
+		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
 func copyImageToClipboard(imagePath string) error {
 	switch runtime.GOOS {
 	case "darwin": // macOS
@@ -36,8 +75,21 @@ func copyImageToClipboard(imagePath string) error {
 	}
 }
 
+// testTodoPersistence saves and loads some todos in a scratch home
+// directory, so the real todo list is never touched.
 func testTodoPersistence() {
 	fmt.Println("Testing todo persistence...")
+
+	home, err := os.MkdirTemp("", "bdt-todo-test-")
+	if err != nil {
+		fmt.Printf("Error creating a test directory: %v\n", err)
+		return
+	}
+	defer os.RemoveAll(home)
+	for _, name := range []string{"HOME", "USERPROFILE"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Setenv(name, home)
+	}
 	
 	testTodos := []TodoItem{
 		{
@@ -55,7 +107,7 @@ func testTodoPersistence() {
 	}
 	
 	fmt.Printf("Saving %d todos...\n", len(testTodos))
-	err := saveTodos(testTodos)
+	err = saveTodos(testTodos)
 	if err != nil {
 		fmt.Printf("Error saving todos: %v\n", err)
 		return
@@ -63,7 +115,11 @@ func testTodoPersistence() {
 	fmt.Println("✅ Todos saved successfully")
 	
 	fmt.Println("Loading todos...")
-	loadedTodos := loadTodos()
+	loadedTodos, _, err := loadTodos()
+	if err != nil {
+		fmt.Printf("Error loading todos: %v\n", err)
+		return
+	}
 	fmt.Printf("✅ Loaded %d todos\n", len(loadedTodos))
 	
 	for _, todo := range loadedTodos {
@@ -76,7 +132,6 @@ func testTodoPersistence() {
 	
 	fmt.Printf("Todo file location: %s\n", getTodoFilePath())
 	
-	fmt.Println("Cleaning up test file...")
-	os.Remove(getTodoFilePath())
+	fmt.Println("Cleaning up test directory...")
 	fmt.Println("✅ Test completed")
 }
\ No newline at end of file
@@ -3,77 +3,177 @@ package main
 import (
 	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Largest weight a single wheel item can carry.
+const maxWheelWeight = 100
+
+// parseWheelItem reads an entry typed as "pizza x3", where the optional xN
+// suffix is the item's weight. Entries without a suffix have weight 1.
+func parseWheelItem(input string) WheelItem {
+	item := WheelItem{Name: strings.TrimSpace(input), Weight: 1}
+	if i := strings.LastIndexAny(item.Name, " \t"); i > 0 {
+		suffix := strings.TrimLeft(item.Name[i+1:], "xX×")
+		if len(suffix) < len(item.Name[i+1:]) {
+			if weight, err := strconv.Atoi(suffix); err == nil && weight > 0 {
+				item.Name = strings.TrimSpace(item.Name[:i])
+				item.Weight = min(weight, maxWheelWeight)
+			}
+		}
+	}
+	return item
+}
+
+func wheelTotalWeight(items []WheelItem) int {
+	total := 0
+	for _, item := range items {
+		total += item.Weight
+	}
+	return total
+}
+
+// pickWheelItem draws an item index with probability proportional to its weight.
+func pickWheelItem(items []WheelItem) int {
+	n := rand.Intn(wheelTotalWeight(items))
+	for i, item := range items {
+		if n < item.Weight {
+			return i
+		}
+		n -= item.Weight
+	}
+	return len(items) - 1
+}
+
 func (m model) updateWheelSpinner(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.wheelPrompt != "" {
+			return m.updateWheelPrompt(msg)
+		}
+		if m.wheelShowPicker {
+			return m.updateWheelPicker(msg)
+		}
+		if m.wheelShowHistory {
+			return m.updateWheelHistory(msg)
+		}
+		if msg.Paste && !m.wheelSpinning {
+			return m.pasteWheelItems(string(msg.Runes))
+		}
+
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "esc":
 			if m.wheelInputMode {
 				m.wheelInputMode = false
+				m.wheelEditing = false
 				m.wheelInput = ""
+				m.wheelWeightInput = ""
 			} else {
 				m.state = menuView
 			}
 		case "tab":
 			m.wheelInputMode = !m.wheelInputMode
+			m.wheelEditing = false
 			m.wheelInput = ""
+			m.wheelWeightInput = ""
+			m.wheelInputField = 0
+		case "up", "down":
+			if m.wheelInputMode {
+				// Switch between the name and weight fields
+				m.wheelInputField = 1 - m.wheelInputField
+			} else if msg.String() == "up" && m.wheelCursor > 0 {
+				m.wheelCursor--
+			} else if msg.String() == "down" && m.wheelCursor < len(m.wheelItems)-1 {
+				m.wheelCursor++
+			}
 		case "enter":
 			if m.wheelInputMode {
-				// Add new item
-				if m.wheelInput != "" {
-					m.wheelItems = append(m.wheelItems, m.wheelInput)
+				// Add a new item, or replace the one being edited
+				item := parseWheelItem(m.wheelInput)
+				if weight, err := strconv.Atoi(m.wheelWeightInput); err == nil && weight > 0 {
+					item.Weight = min(weight, maxWheelWeight)
+				}
+				if item.Name != "" {
+					m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+					if m.wheelEditing && m.wheelCursor < len(m.wheelItems) {
+						m.wheelItems[m.wheelCursor] = item
+					} else {
+						m.wheelItems = append(m.wheelItems, item)
+						m.wheelCursor = len(m.wheelItems) - 1
+					}
+					m.wheelEditing = false
 					m.wheelInput = ""
+					m.wheelWeightInput = ""
+					m.wheelInputField = 0
 					m.wheelInputMode = false
 				}
 			} else if len(m.wheelItems) > 0 && !m.wheelSpinning {
-				// Start spinning
+				// Choose the outcome up front, then start the animation far
+				// enough back that its last step lands on it
+				winner, outcome := m.drawWheelOutcome()
+				m.wheelResult = m.wheelItems[winner].Name
+				m.wheelOutcome = outcome
+				m.wheelMessage = ""
 				m.wheelSpinning = true
 				m.wheelSpinTime = time.Now()
-				m.wheelSpinIndex = 0
-				
-				// Choose random result
-				m.wheelResult = m.wheelItems[rand.Intn(len(m.wheelItems))]
-				
-				return m, tea.Tick(time.Millisecond*50, func(t time.Time) tea.Msg {
+				m = m.startWheelSpin(winner)
+
+				return m, tea.Tick(time.Second/30, func(t time.Time) tea.Msg {
 					return t
 				})
 			}
+		case "+", "=", "-":
+			if !m.wheelInputMode && !m.wheelSpinning && m.wheelCursor < len(m.wheelItems) {
+				m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+				if msg.String() == "-" {
+					m.wheelItems[m.wheelCursor].Weight = max(1, m.wheelItems[m.wheelCursor].Weight-1)
+				} else {
+					m.wheelItems[m.wheelCursor].Weight = min(maxWheelWeight, m.wheelItems[m.wheelCursor].Weight+1)
+				}
+			} else if m.wheelInputMode && m.wheelInputField == 0 {
+				m.wheelInput += msg.String()
+			}
 		case "backspace":
 			if m.wheelInputMode {
-				if len(m.wheelInput) > 0 {
+				if m.wheelInputField == 1 {
+					if len(m.wheelWeightInput) > 0 {
+						m.wheelWeightInput = m.wheelWeightInput[:len(m.wheelWeightInput)-1]
+					}
+				} else if len(m.wheelInput) > 0 {
 					m.wheelInput = m.wheelInput[:len(m.wheelInput)-1]
 				}
-			} else if len(m.wheelItems) > 0 {
-				// Remove last item
-				m.wheelItems = m.wheelItems[:len(m.wheelItems)-1]
-				// Clear result if list becomes empty
-				if len(m.wheelItems) == 0 {
-					m.wheelResult = ""
-				}
+			} else if !m.wheelSpinning {
+				return m.updateWheelList(msg)
 			}
 		default:
 			if m.wheelInputMode && len(msg.String()) == 1 {
-				m.wheelInput += msg.String()
+				if m.wheelInputField == 1 {
+					if msg.String() >= "0" && msg.String() <= "9" {
+						m.wheelWeightInput += msg.String()
+					}
+				} else {
+					m.wheelInput += msg.String()
+				}
+			} else if !m.wheelInputMode && !m.wheelSpinning {
+				return m.updateWheelList(msg)
 			}
 		}
 	case time.Time:
 		if m.wheelSpinning {
-			elapsed := time.Since(m.wheelSpinTime)
-			if elapsed > time.Second*3 { // Spin for 3 seconds
+			var done bool
+			m, done = m.advanceWheelSpin()
+			if done {
 				m.wheelSpinning = false
+				m = m.finishWheelSpin()
 			} else {
-				// Cycle through items faster early on, slower later
-				speed := time.Millisecond * time.Duration(50 + int64(elapsed/time.Millisecond)/20)
-				m.wheelSpinIndex = (m.wheelSpinIndex + 1) % len(m.wheelItems)
-				return m, tea.Tick(speed, func(t time.Time) tea.Msg {
+				return m, tea.Tick(time.Second/30, func(t time.Time) tea.Msg {
 					return t
 				})
 			}
@@ -95,7 +195,7 @@ func (m model) viewWheelSpinner() string {
 		Foreground(lipgloss.Color("#FAFAFA")).
 		Background(lipgloss.Color("#9B59B6")).
 		Padding(1, 2).
-		MarginBottom(2).
+		MarginBottom(1).
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("#9B59B6")).
 		Width(60).
@@ -113,24 +213,25 @@ func (m model) viewWheelSpinner() string {
 		Bold(true).
 		Foreground(lipgloss.Color("#E74C3C")).
 		Background(lipgloss.Color("#FFF3CD")).
-		Padding(1, 2).
+		Padding(0, 2).
+		MarginBottom(1).
 		AlignHorizontal(lipgloss.Center)
 
 	resultStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("#FAFAFA")).
 		Background(lipgloss.Color("#27AE60")).
-		Padding(2, 4).
+		Padding(1, 4).
 		Border(lipgloss.DoubleBorder()).
 		BorderForeground(lipgloss.Color("#27AE60")).
 		AlignHorizontal(lipgloss.Center).
-		MarginBottom(2)
+		MarginBottom(1)
 
 	itemsListStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("#9B59B6")).
 		Padding(1, 2).
-		MarginBottom(2).
+		MarginBottom(1).
 		Width(60)
 
 	inputStyle := lipgloss.NewStyle().
@@ -147,62 +248,145 @@ func (m model) viewWheelSpinner() string {
 		Width(60)
 
 	// Build content
-	title := titleStyle.Render("🎡 Wheel Spinner")
+	if m.wheelShowPicker {
+		return m.viewWheelPicker()
+	}
+	if m.wheelShowHistory {
+		return m.viewWheelHistory()
+	}
+
+	titleText := "🎡 Wheel Spinner"
+	if m.wheelName != "" {
+		titleText += " — " + m.wheelName
+	}
+	title := titleStyle.Render(titleText)
 	
 	// Current items list
 	var itemsDisplay string
 	if len(m.wheelItems) == 0 {
 		itemsDisplay = "No items yet!\n\nPress Tab to add your first item"
 	} else {
-		itemsDisplay = "Current Items:\n"
+		itemsDisplay = fmt.Sprintf("%-46s %6s\n", "Current Items:", "Chance")
+		total := wheelTotalWeight(m.wheelItems)
 		for i, item := range m.wheelItems {
-			itemsDisplay += fmt.Sprintf("  %d. %s\n", i+1, item)
+			marker := "  "
+			if i == m.wheelCursor && !m.wheelInputMode {
+				marker = "▶ "
+			}
+			swatch := lipgloss.NewStyle().Foreground(lipgloss.Color(wheelSegmentColor(i, len(m.wheelItems)))).Render("██")
+			name := fmt.Sprintf("%d. %s", i+1, item.Name)
+			if item.Weight > 1 {
+				name += fmt.Sprintf(" ×%d", item.Weight)
+			}
+			if len([]rune(name)) > 41 {
+				name = string([]rune(name)[:40]) + "…"
+			}
+			chance := float64(item.Weight) / float64(total) * 100
+			itemsDisplay += fmt.Sprintf("%s%s %-41s %5.1f%%\n", marker, swatch, name, chance)
 		}
 	}
 	itemsList := itemsListStyle.Render(itemsDisplay)
 	
+	// The rendered wheel sits beside the item list, or above it when the
+	// terminal is too narrow for both
+	if len(m.wheelItems) > 0 {
+		radius := m.wheelRadius()
+		wheel := lipgloss.NewStyle().MarginBottom(1).Render(renderWheel(m.wheelItems, m.wheelAngle, radius))
+		if m.width >= 2*radius+66 {
+			itemsList = lipgloss.JoinHorizontal(lipgloss.Center, wheel, "    ", itemsList)
+		} else {
+			itemsList = lipgloss.JoinVertical(lipgloss.Center, wheel, itemsList)
+		}
+	}
+
 	// Wheel display
 	var wheelDisplay string
 	if m.wheelSpinning {
-		// Show spinning animation
-		currentItem := m.wheelItems[m.wheelSpinIndex]
-		spinSymbols := []string{"🔄", "⭮", "⭯", "🔃"}
-		spinSymbol := spinSymbols[int(time.Since(m.wheelSpinTime)/time.Millisecond/125)%len(spinSymbols)]
-		wheelDisplay = wheelStyle.Render(fmt.Sprintf("🎡 SPINNING %s\n\n%s", spinSymbol, spinningItemStyle.Render(currentItem)))
+		// Show the item under the pointer
+		currentItem := m.wheelItems[m.wheelSpinIndex].Name
+		wheelDisplay = spinningItemStyle.Render("🎡 " + currentItem)
 	} else if m.wheelResult != "" {
 		// Show result
-		wheelDisplay = resultStyle.Render(fmt.Sprintf("🎉 WINNER! 🎉\n\n%s", m.wheelResult))
+		switch m.wheelMode {
+		case wheelModePick:
+			wheelDisplay = resultStyle.Render(fmt.Sprintf("🎉 WINNER! 🎉\n\n%s", m.wheelResult))
+		case wheelModeElimination:
+			order := ""
+			for i, name := range m.wheelEliminated {
+				order += fmt.Sprintf("\n%d. %s", i+1, name)
+			}
+			if len(m.wheelItems) == 0 {
+				wheelDisplay = resultStyle.Render("🏁 Everyone is out!\n\nElimination order:" + order)
+			} else {
+				wheelDisplay = resultStyle.Render(fmt.Sprintf("❌ OUT: %s\n\n%d left on the wheel\n\nOut so far:%s", m.wheelResult, len(m.wheelItems), order))
+			}
+		default:
+			headings := map[int]string{
+				wheelModeQueue:  "📋 Queue Order",
+				wheelModeTeams:  "👥 Teams",
+				wheelModeGroups: "👥 Groups",
+				wheelModeDraw:   "🎉 Winners 🎉",
+			}
+			wheelDisplay = resultStyle.Render(headings[m.wheelMode] + "\n\n" + strings.Join(m.wheelOutcome, "\n"))
+		}
 	} else if len(m.wheelItems) == 0 {
 		// Show empty state
 		wheelDisplay = wheelStyle.Render("🎡 Wheel is Empty\n\nAdd some items first!")
 	} else {
 		// Show ready to spin
-		wheelDisplay = wheelStyle.Render("🎡 Ready to Spin!\n\nPress Enter to start")
+		wheelDisplay = lipgloss.NewStyle().Bold(true).MarginBottom(1).Render("🎡 Ready to Spin! Press Enter to start")
 	}
+
+	modeHint := "M to change mode"
+	if m.wheelMode >= wheelModeTeams {
+		modeHint += " • </> to adjust"
+	}
+	modeLine := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#9B59B6")).
+		Bold(true).
+		MarginBottom(1).
+		Render(fmt.Sprintf("Mode: %s (%s)", m.wheelModeLabel(), modeHint))
+	wheelDisplay = lipgloss.JoinVertical(lipgloss.Center, modeLine, wheelDisplay)
 	
 	// Input area
 	var inputDisplay string
 	if m.wheelInputMode {
-		inputPrompt := "Add new item:"
-		inputText := fmt.Sprintf("▶ %s█", m.wheelInput)
-		inputDisplay = inputStyle.Render(inputPrompt + "\n" + inputText)
+		inputPrompt := "Add new item (append x3 to the name to set its weight):"
+		if m.wheelEditing {
+			inputPrompt = fmt.Sprintf("Edit item %d:", m.wheelCursor+1)
+		}
+		nameText := fmt.Sprintf("  Name:   %s", m.wheelInput)
+		weightText := fmt.Sprintf("  Weight: %s", m.wheelWeightInput)
+		if m.wheelInputField == 0 {
+			nameText = "▶" + nameText[1:] + "█"
+		} else {
+			weightText = "▶" + weightText[1:] + "█"
+		}
+		inputDisplay = inputStyle.Render(inputPrompt + "\n" + nameText + "\n" + weightText)
+	} else if m.wheelPrompt != "" {
+		inputDisplay = inputStyle.Render(wheelPromptLabels[m.wheelPrompt] + "\n" + fmt.Sprintf("▶ %s█", m.wheelPromptInput))
 	}
 	
 	// Help text
 	var helpText string
 	if m.wheelInputMode {
-		helpText = "Type item name • Enter to add • ESC to cancel"
+		helpText = "Type item name • ↑/↓ to switch to weight • Enter to save • ESC to cancel"
+	} else if m.wheelPrompt != "" {
+		helpText = "Enter to confirm • ESC to cancel"
 	} else if len(m.wheelItems) == 0 {
-		helpText = "Tab to add items • ESC to go back"
+		helpText = "Tab to add items • Paste a list to add many • L to load a saved wheel • I to import • ESC to go back"
 	} else {
-		helpText = "Enter to spin • Tab to add item • Backspace to remove last • ESC to go back"
+		helpText = "Enter spin • Tab add • E edit • D delete • Shift+↑/↓ move • C duplicate • R dedupe • U undo • +/- weight • M mode • Y copy • H history • S save • L load • I/X import/export • N new • ESC back"
 	}
 	help := helpStyle.Render(helpText)
 	
 	// Combine all elements
 	var content string
-	if m.wheelInputMode {
+	if m.wheelInputMode || m.wheelPrompt != "" {
 		content = lipgloss.JoinVertical(lipgloss.Center, title, itemsList, inputDisplay, help)
+	} else if m.wheelMessage != "" {
+		message := lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.wheelMessage)
+		content = lipgloss.JoinVertical(lipgloss.Center, title, itemsList, wheelDisplay, message, help)
 	} else {
 		content = lipgloss.JoinVertical(lipgloss.Center, title, itemsList, wheelDisplay, help)
 	}
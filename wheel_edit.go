@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Number of earlier versions of the item list kept for undo.
+const maxWheelUndo = 50
+
+// pushWheelUndo records a copy of items so the next change can be undone.
+func pushWheelUndo(stack [][]WheelItem, items []WheelItem) [][]WheelItem {
+	stack = append(stack, append([]WheelItem{}, items...))
+	if len(stack) > maxWheelUndo {
+		stack = stack[len(stack)-maxWheelUndo:]
+	}
+	return stack
+}
+
+// parseWheelItemLines splits pasted text into one item per non-empty line.
+func parseWheelItemLines(text string) []WheelItem {
+	var items []WheelItem
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r", "\n"), "\n") {
+		if item := parseWheelItem(line); item.Name != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// dedupeWheelItems drops items whose name repeats an earlier one, ignoring
+// case and surrounding space, and reports how many were removed.
+func dedupeWheelItems(items []WheelItem) ([]WheelItem, int) {
+	seen := make(map[string]bool)
+	var unique []WheelItem
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.Name))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, item)
+	}
+	return unique, len(items) - len(unique)
+}
+
+// pasteWheelItems handles bracketed paste. Multi-line text adds one item per
+// line; a single line is typed into the field being edited.
+func (m model) pasteWheelItems(text string) (tea.Model, tea.Cmd) {
+	if !strings.ContainsAny(strings.TrimSpace(text), "\r\n") {
+		if m.wheelInputMode && m.wheelInputField == 0 {
+			m.wheelInput += strings.TrimSpace(text)
+		} else if !m.wheelInputMode {
+			m.wheelInput = strings.TrimSpace(text)
+			m.wheelInputMode = true
+			m.wheelInputField = 0
+		}
+		return m, nil
+	}
+
+	items := parseWheelItemLines(text)
+	if len(items) == 0 {
+		return m, nil
+	}
+	m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+	m.wheelItems = append(m.wheelItems, items...)
+	m.wheelCursor = len(m.wheelItems) - 1
+	m.wheelInputMode = false
+	m.wheelEditing = false
+	m.wheelInput = ""
+	m.wheelWeightInput = ""
+	m.wheelMessage = fmt.Sprintf("✅ Added %d items", len(items))
+	return m, nil
+}
+
+// updateWheelList handles the keys that act on the item list and the saved
+// wheels while the wheel is idle.
+func (m model) updateWheelList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.wheelMessage = ""
+	if next, ok := m.updateWheelMode(msg.String()); ok {
+		return next, nil
+	}
+	hasItem := m.wheelCursor < len(m.wheelItems)
+
+	switch msg.String() {
+	case "e":
+		if hasItem {
+			item := m.wheelItems[m.wheelCursor]
+			m.wheelInputMode = true
+			m.wheelEditing = true
+			m.wheelInputField = 0
+			m.wheelInput = item.Name
+			m.wheelWeightInput = fmt.Sprint(item.Weight)
+		}
+	case "backspace", "delete", "d":
+		if hasItem {
+			m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+			m.wheelItems = append(m.wheelItems[:m.wheelCursor], m.wheelItems[m.wheelCursor+1:]...)
+			m.wheelCursor = max(0, min(m.wheelCursor, len(m.wheelItems)-1))
+			// Clear result if list becomes empty
+			if len(m.wheelItems) == 0 {
+				m.wheelResult = ""
+			}
+		}
+	case "shift+up", "K":
+		if hasItem && m.wheelCursor > 0 {
+			m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+			m.wheelItems[m.wheelCursor], m.wheelItems[m.wheelCursor-1] = m.wheelItems[m.wheelCursor-1], m.wheelItems[m.wheelCursor]
+			m.wheelCursor--
+		}
+	case "shift+down", "J":
+		if hasItem && m.wheelCursor < len(m.wheelItems)-1 {
+			m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+			m.wheelItems[m.wheelCursor], m.wheelItems[m.wheelCursor+1] = m.wheelItems[m.wheelCursor+1], m.wheelItems[m.wheelCursor]
+			m.wheelCursor++
+		}
+	case "c":
+		if hasItem {
+			m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+			item := m.wheelItems[m.wheelCursor]
+			m.wheelItems = append(m.wheelItems[:m.wheelCursor+1], append([]WheelItem{item}, m.wheelItems[m.wheelCursor+1:]...)...)
+			m.wheelCursor++
+		}
+	case "r":
+		unique, removed := dedupeWheelItems(m.wheelItems)
+		if removed == 0 {
+			m.wheelMessage = "No duplicates found"
+		} else {
+			m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+			m.wheelItems = unique
+			m.wheelCursor = min(m.wheelCursor, len(m.wheelItems)-1)
+			m.wheelMessage = fmt.Sprintf("✅ Removed %d duplicates", removed)
+		}
+	case "u", "ctrl+z":
+		if len(m.wheelUndo) == 0 {
+			m.wheelMessage = "Nothing to undo"
+		} else {
+			m.wheelItems = m.wheelUndo[len(m.wheelUndo)-1]
+			m.wheelUndo = m.wheelUndo[:len(m.wheelUndo)-1]
+			m.wheelCursor = max(0, min(m.wheelCursor, len(m.wheelItems)-1))
+			if len(m.wheelItems) == 0 {
+				m.wheelResult = ""
+			}
+		}
+	case "s":
+		m.wheelPrompt = "save"
+		m.wheelPromptInput = m.wheelName
+	case "l":
+		var err error
+		if m.wheelSaved, err = loadWheels(); err != nil {
+			m.wheelMessage = "❌ " + err.Error()
+		}
+		m.wheelShowPicker = true
+		m.wheelPickerCursor = max(0, findWheel(m.wheelSaved, m.wheelName))
+	case "i":
+		m.wheelPrompt = "import"
+		m.wheelPromptInput = ""
+	case "x":
+		if len(m.wheelItems) > 0 {
+			m.wheelPrompt = "export"
+			m.wheelPromptInput = wheelExportFilename(m.wheelName)
+		}
+	case "n":
+		m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+		m.wheelItems = []WheelItem{}
+		m.wheelName = ""
+		m.wheelResult = ""
+		m.wheelOutcome = nil
+		m.wheelEliminated = nil
+		m.wheelCursor = 0
+	}
+	return m, nil
+}
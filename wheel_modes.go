@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Wheel modes, in the order the M key cycles through them.
+const (
+	wheelModePick = iota
+	wheelModeElimination
+	wheelModeQueue
+	wheelModeTeams
+	wheelModeGroups
+	wheelModeDraw
+)
+
+var wheelModeNames = []string{"Pick one", "Elimination", "Queue", "Teams", "Groups", "Draw winners"}
+
+// Most spins kept in the spin history file.
+const maxWheelHistory = 500
+
+// WheelSpin is one finished spin in the spin history.
+type WheelSpin struct {
+	Time   time.Time `json:"time"`
+	Wheel  string    `json:"wheel,omitempty"`
+	Mode   string    `json:"mode"`
+	Result []string  `json:"result"`
+}
+
+type wheelHistoryFile struct {
+	Spins []WheelSpin `json:"spins"`
+}
+
+func getWheelHistoryFilePath() string {
+	return filepath.Join(getDataDir(), "wheel-history.json")
+}
+
+// loadWheelHistory reads the spin history. A damaged file is moved aside so
+// the next spin doesn't overwrite it, and the error says where it went.
+func loadWheelHistory() ([]WheelSpin, error) {
+	path := getWheelHistoryFilePath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []WheelSpin{}, nil
+	}
+	if err != nil {
+		return []WheelSpin{}, err
+	}
+
+	var file wheelHistoryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		detail := describeJSONError(data, 0, err)
+		aside := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+		if renameErr := os.Rename(path, aside); renameErr != nil {
+			return []WheelSpin{}, fmt.Errorf("spin history is damaged (%s) and couldn't be moved aside: %v", detail, renameErr)
+		}
+		return []WheelSpin{}, fmt.Errorf("spin history was damaged (%s) • moved it to %s and started a new one", detail, aside)
+	}
+	return file.Spins, nil
+}
+
+// saveWheelHistory writes the spin history, unless the file on disk is
+// damaged and couldn't be moved aside.
+func saveWheelHistory(spins []WheelSpin) error {
+	if data, err := os.ReadFile(getWheelHistoryFilePath()); err == nil && !json.Valid(data) {
+		return fmt.Errorf("%s is damaged • fix or move it to keep spin history", getWheelHistoryFilePath())
+	}
+	data, err := json.MarshalIndent(wheelHistoryFile{Spins: spins}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getWheelHistoryFilePath(), data, 0644)
+}
+
+// weightedWheelOrder draws every item without replacement, each draw
+// proportional to the weights of the items still left, and returns the
+// indices in the order they were drawn.
+func weightedWheelOrder(items []WheelItem) []int {
+	remaining := append([]WheelItem{}, items...)
+	indices := make([]int, len(items))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	var order []int
+	for len(remaining) > 0 {
+		pick := pickWheelItem(remaining)
+		order = append(order, indices[pick])
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+		indices = append(indices[:pick], indices[pick+1:]...)
+	}
+	return order
+}
+
+// splitWheelTeams deals a random shuffle of the items round-robin into n
+// teams, so team sizes never differ by more than one.
+func splitWheelTeams(items []WheelItem, n int) [][]string {
+	n = max(1, min(n, len(items)))
+	teams := make([][]string, n)
+	for i, j := range rand.Perm(len(items)) {
+		teams[i%n] = append(teams[i%n], items[j].Name)
+	}
+	return teams
+}
+
+// splitWheelGroups cuts a random shuffle of the items into groups of size;
+// the last group holds whoever is left over.
+func splitWheelGroups(items []WheelItem, size int) [][]string {
+	size = max(1, size)
+	var groups [][]string
+	for i, j := range rand.Perm(len(items)) {
+		if i%size == 0 {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], items[j].Name)
+	}
+	return groups
+}
+
+// drawWheelOutcome decides the result of a spin in the current mode. It
+// returns the item the animation should stop on and the result lines.
+func (m model) drawWheelOutcome() (int, []string) {
+	switch m.wheelMode {
+	case wheelModeQueue, wheelModeDraw:
+		order := weightedWheelOrder(m.wheelItems)
+		if m.wheelMode == wheelModeDraw {
+			order = order[:min(m.wheelModeN, len(order))]
+		}
+		var lines []string
+		for i, index := range order {
+			lines = append(lines, fmt.Sprintf("%d. %s", i+1, m.wheelItems[index].Name))
+		}
+		return order[0], lines
+
+	case wheelModeTeams, wheelModeGroups:
+		var split [][]string
+		label := "Team"
+		if m.wheelMode == wheelModeTeams {
+			split = splitWheelTeams(m.wheelItems, m.wheelModeN)
+		} else {
+			split = splitWheelGroups(m.wheelItems, m.wheelModeN)
+			label = "Group"
+		}
+		var lines []string
+		for i, members := range split {
+			lines = append(lines, fmt.Sprintf("%s %d: %s", label, i+1, strings.Join(members, ", ")))
+		}
+		return rand.Intn(len(m.wheelItems)), lines
+
+	default:
+		winner := pickWheelItem(m.wheelItems)
+		return winner, []string{m.wheelItems[winner].Name}
+	}
+}
+
+// finishWheelSpin runs once the animation stops: it logs the spin and, in
+// elimination mode, takes the winner off the wheel.
+func (m model) finishWheelSpin() model {
+	m.wheelHistory = append(m.wheelHistory, WheelSpin{
+		Time:   time.Now(),
+		Wheel:  m.wheelName,
+		Mode:   wheelModeNames[m.wheelMode],
+		Result: m.wheelOutcome,
+	})
+	if len(m.wheelHistory) > maxWheelHistory {
+		m.wheelHistory = m.wheelHistory[len(m.wheelHistory)-maxWheelHistory:]
+	}
+	if err := saveWheelHistory(m.wheelHistory); err != nil {
+		m.wheelMessage = fmt.Sprintf("❌ Could not save spin history: %v", err)
+	}
+
+	if m.wheelMode == wheelModeElimination && m.wheelSpinIndex < len(m.wheelItems) {
+		m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+		m.wheelEliminated = append(m.wheelEliminated, m.wheelItems[m.wheelSpinIndex].Name)
+		m.wheelItems = append(m.wheelItems[:m.wheelSpinIndex], m.wheelItems[m.wheelSpinIndex+1:]...)
+		m.wheelCursor = max(0, min(m.wheelCursor, len(m.wheelItems)-1))
+	}
+	return m
+}
+
+// wheelModeLabel describes the current mode and its size setting.
+func (m model) wheelModeLabel() string {
+	switch m.wheelMode {
+	case wheelModeTeams:
+		return fmt.Sprintf("Teams: split into %d teams", m.wheelModeN)
+	case wheelModeGroups:
+		return fmt.Sprintf("Groups: split into groups of %d", m.wheelModeN)
+	case wheelModeDraw:
+		return fmt.Sprintf("Draw winners: %d without replacement", m.wheelModeN)
+	case wheelModeElimination:
+		return "Elimination: each winner leaves the wheel"
+	case wheelModeQueue:
+		return "Queue: shuffle everyone into an order"
+	}
+	return "Pick one winner"
+}
+
+// wheelOutcomeText is the plain text of the last result, for the clipboard.
+func (m model) wheelOutcomeText() string {
+	text := strings.Join(m.wheelOutcome, "\n")
+	if m.wheelMode == wheelModeElimination && len(m.wheelEliminated) > 0 {
+		text = "Elimination order:\n"
+		for i, name := range m.wheelEliminated {
+			text += fmt.Sprintf("%d. %s\n", i+1, name)
+		}
+		text = strings.TrimSuffix(text, "\n")
+	}
+	return text
+}
+
+// updateWheelMode handles the keys that change the mode and act on results.
+// It reports whether the key was one of them.
+func (m model) updateWheelMode(key string) (model, bool) {
+	switch key {
+	case "m":
+		m.wheelMode = (m.wheelMode + 1) % len(wheelModeNames)
+		m.wheelOutcome = nil
+		m.wheelResult = ""
+		m.wheelEliminated = nil
+	case ">", ".":
+		m.wheelModeN = min(m.wheelModeN+1, 99)
+	case "<", ",":
+		m.wheelModeN = max(m.wheelModeN-1, 1)
+	case "y":
+		if len(m.wheelOutcome) == 0 {
+			m.wheelMessage = "Nothing to copy yet"
+		} else if err := copyTextToClipboard(m.wheelOutcomeText()); err != nil {
+			m.wheelMessage = fmt.Sprintf("❌ Copy failed: %v", err)
+		} else {
+			m.wheelMessage = "✅ Result copied to clipboard"
+		}
+	case "h":
+		m.wheelShowHistory = true
+		m.wheelHistoryCursor = 0
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+func (m model) updateWheelHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "h":
+		m.wheelShowHistory = false
+	case "up", "k":
+		if m.wheelHistoryCursor > 0 {
+			m.wheelHistoryCursor--
+		}
+	case "down", "j":
+		if m.wheelHistoryCursor < len(m.wheelHistory)-1 {
+			m.wheelHistoryCursor++
+		}
+	case "y":
+		if m.wheelHistoryCursor < len(m.wheelHistory) {
+			spin := m.wheelHistory[len(m.wheelHistory)-1-m.wheelHistoryCursor]
+			if err := copyTextToClipboard(strings.Join(spin.Result, "\n")); err != nil {
+				m.wheelMessage = fmt.Sprintf("❌ Copy failed: %v", err)
+			} else {
+				m.wheelMessage = "✅ Result copied to clipboard"
+			}
+		}
+	case "X":
+		m.wheelHistory = []WheelSpin{}
+		m.wheelHistoryCursor = 0
+		if err := saveWheelHistory(m.wheelHistory); err != nil {
+			m.wheelMessage = fmt.Sprintf("❌ Could not clear history: %v", err)
+		} else {
+			m.wheelMessage = "✅ Spin history cleared"
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewWheelHistory() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#9B59B6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#9B59B6")).
+		Width(70).
+		AlignHorizontal(lipgloss.Center)
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#9B59B6")).
+		Padding(0, 2).
+		MarginBottom(1).
+		Width(70)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#9B59B6"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(70)
+
+	title := titleStyle.Render(fmt.Sprintf("📜 Spin History (%d spins)", len(m.wheelHistory)))
+
+	// Newest spins first, windowed around the cursor
+	const visibleRows = 10
+	var rows []string
+	if len(m.wheelHistory) == 0 {
+		rows = append(rows, "No spins yet.")
+	} else {
+		start := max(0, min(m.wheelHistoryCursor-visibleRows/2, len(m.wheelHistory)-visibleRows))
+		end := min(len(m.wheelHistory), start+visibleRows)
+		for i := start; i < end; i++ {
+			spin := m.wheelHistory[len(m.wheelHistory)-1-i]
+			wheel := spin.Wheel
+			if wheel == "" {
+				wheel = "unsaved wheel"
+			}
+			row := fmt.Sprintf("%s  %-12s %-16s %s", spin.Time.Format("01-02 15:04"), spin.Mode, wheel, strings.Join(spin.Result, " • "))
+			if len([]rune(row)) > 64 {
+				row = string([]rune(row)[:63]) + "…"
+			}
+			if i == m.wheelHistoryCursor {
+				row = selectedStyle.Render(row)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	elements := []string{title, panelStyle.Render(strings.Join(rows, "\n"))}
+	if m.wheelHistoryCursor < len(m.wheelHistory) {
+		spin := m.wheelHistory[len(m.wheelHistory)-1-m.wheelHistoryCursor]
+		elements = append(elements, panelStyle.Render(strings.Join(spin.Result, "\n")))
+	}
+	if m.wheelMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.wheelMessage))
+	}
+	elements = append(elements, helpStyle.Render("↑/↓ to select • Y to copy result • X to clear history • H/ESC to close"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
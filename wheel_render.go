@@ -0,0 +1,212 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Segment colours, picked to stay readable under dark label text.
+var wheelPalette = []string{
+	"#FF6B6B", "#4ECDC4", "#FFD93D", "#6BCB77", "#4D96FF", "#F7A072",
+	"#C77DFF", "#FF8FAB", "#00C2A8", "#FFB347", "#9BF6FF", "#B5E48C",
+}
+
+const (
+	wheelRimColor   = "#2C3E50"
+	wheelHubColor   = "#FAFAFA"
+	wheelLabelColor = "#1A1A1A"
+)
+
+// wheelSegmentBounds returns n+1 angles in radians splitting the circle into
+// one segment per item, each sized by the item's share of the total weight.
+func wheelSegmentBounds(items []WheelItem) []float64 {
+	total := float64(wheelTotalWeight(items))
+	bounds := []float64{0}
+	for _, item := range items {
+		bounds = append(bounds, bounds[len(bounds)-1]+2*math.Pi*float64(item.Weight)/total)
+	}
+	return bounds
+}
+
+// wheelSegmentAt finds the segment containing an angle measured on the wheel.
+func wheelSegmentAt(bounds []float64, angle float64) int {
+	angle = math.Mod(angle, 2*math.Pi)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	for i := 1; i < len(bounds); i++ {
+		if angle < bounds[i] {
+			return i - 1
+		}
+	}
+	return len(bounds) - 2
+}
+
+// wheelSegmentColor picks a palette colour for segment i of n, making sure
+// the last segment never matches its neighbour, the first.
+func wheelSegmentColor(i, n int) string {
+	color := i % len(wheelPalette)
+	if i == n-1 && n > 1 && color == 0 {
+		color = 1
+	}
+	return wheelPalette[color]
+}
+
+// startWheelSpin sets up a spin that ends on the winner. The wheel leaves at a
+// random speed and slows under constant friction, chosen so that it comes to
+// rest after a few full turns with the pointer somewhere inside the winner's
+// segment, away from its edges.
+func (m model) startWheelSpin(winner int) model {
+	bounds := wheelSegmentBounds(m.wheelItems)
+	target := bounds[winner] + (0.15+0.7*rand.Float64())*(bounds[winner+1]-bounds[winner])
+
+	// The pointer sits at the top, so it shows the wheel angle -rotation
+	offset := math.Mod(-target-m.wheelAngle, 2*math.Pi)
+	if offset < 0 {
+		offset += 2 * math.Pi
+	}
+	distance := float64(3+rand.Intn(3))*2*math.Pi + offset
+
+	m.wheelSpinFrom = m.wheelAngle
+	m.wheelSpinVelocity = (2.5 + 1.5*rand.Float64()) * 2 * math.Pi
+	m.wheelSpinFriction = m.wheelSpinVelocity * m.wheelSpinVelocity / (2 * distance)
+	m.wheelSpinIndex = wheelSegmentAt(bounds, -m.wheelAngle)
+	m.wheelWinner = winner
+	return m
+}
+
+// advanceWheelSpin moves the wheel to where it should be at this moment of
+// the spin and reports whether it has come to rest.
+func (m model) advanceWheelSpin() (model, bool) {
+	duration := m.wheelSpinVelocity / m.wheelSpinFriction
+	t := time.Since(m.wheelSpinTime).Seconds()
+	done := t >= duration
+	t = min(t, duration)
+
+	m.wheelAngle = m.wheelSpinFrom + m.wheelSpinVelocity*t - m.wheelSpinFriction*t*t/2
+	m.wheelSpinIndex = wheelSegmentAt(wheelSegmentBounds(m.wheelItems), -m.wheelAngle)
+	if done {
+		m.wheelAngle = math.Mod(m.wheelAngle, 2*math.Pi)
+		m.wheelSpinIndex = m.wheelWinner
+	}
+	return m, done
+}
+
+// wheelRadius sizes the wheel to the terminal. The radius is in half-block
+// pixels, which is also the wheel's height in rows.
+func (m model) wheelRadius() int {
+	return max(6, min(m.height-26, 16))
+}
+
+type wheelCell struct {
+	top, bottom string // pixel colours, "" outside the wheel
+	label       rune
+}
+
+// renderWheel rasterises the wheel with half-block characters, two square
+// pixels per terminal cell, and writes item names into their segments. The
+// wheel is rotated clockwise by angle; a fixed pointer sits above it.
+func renderWheel(items []WheelItem, angle float64, radius int) string {
+	size := 2 * radius
+	r := float64(radius)
+	bounds := wheelSegmentBounds(items)
+
+	colors := make([]string, len(items))
+	for i := range items {
+		colors[i] = wheelSegmentColor(i, len(items))
+	}
+
+	pixel := func(x, y float64) string {
+		dx, dy := x-r, y-r
+		dist := math.Hypot(dx, dy)
+		switch {
+		case dist > r:
+			return ""
+		case dist > r-1:
+			return wheelRimColor
+		case dist < r/8:
+			return wheelHubColor
+		}
+		// Screen angle runs clockwise from 12 o'clock
+		return colors[wheelSegmentAt(bounds, math.Atan2(dx, -dy)-angle)]
+	}
+
+	cells := make([][]wheelCell, radius)
+	for row := range cells {
+		cells[row] = make([]wheelCell, size)
+		for col := range cells[row] {
+			cells[row][col] = wheelCell{
+				top:    pixel(float64(col)+0.5, float64(2*row)+0.5),
+				bottom: pixel(float64(col)+0.5, float64(2*row)+1.5),
+			}
+		}
+	}
+
+	// Labels sit across the middle of each segment, cut to fit its arc
+	for i, item := range items {
+		mid := (bounds[i]+bounds[i+1])/2 + angle
+		labelRadius := 0.6 * r
+		room := int(math.Min((bounds[i+1]-bounds[i])*labelRadius*0.9, 0.8*r))
+		name := []rune(item.Name)
+		if room < 1 {
+			continue
+		}
+		if len(name) > room {
+			name = name[:room]
+		}
+		col := int(r+math.Sin(mid)*labelRadius) - len(name)/2
+		row := int((r - math.Cos(mid)*labelRadius) / 2)
+		for j, ch := range name {
+			if row >= 0 && row < radius && col+j >= 0 && col+j < size && cells[row][col+j].top != "" {
+				cells[row][col+j].label = ch
+			}
+		}
+	}
+
+	var out strings.Builder
+
+	// Fixed pointer at 12 o'clock
+	pointerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#E74C3C"))
+	out.WriteString(strings.Repeat(" ", radius-1) + pointerStyle.Render("▼") + strings.Repeat(" ", radius) + "\n")
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(wheelLabelColor))
+	for row := range cells {
+		// Group runs of identical cells so each run is styled once
+		col := 0
+		for col < size {
+			cell := cells[row][col]
+			end := col + 1
+			for end < size && cells[row][end].top == cell.top && cells[row][end].bottom == cell.bottom && (cells[row][end].label != 0) == (cell.label != 0) {
+				end++
+			}
+			run := cells[row][col:end]
+
+			switch {
+			case cell.label != 0:
+				var text strings.Builder
+				for _, c := range run {
+					text.WriteRune(c.label)
+				}
+				out.WriteString(labelStyle.Background(lipgloss.Color(cell.top)).Render(text.String()))
+			case cell.top == "" && cell.bottom == "":
+				out.WriteString(strings.Repeat(" ", len(run)))
+			case cell.bottom == "":
+				out.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(cell.top)).Render(strings.Repeat("▀", len(run))))
+			case cell.top == "":
+				out.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(cell.bottom)).Render(strings.Repeat("▄", len(run))))
+			default:
+				out.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(cell.top)).Background(lipgloss.Color(cell.bottom)).Render(strings.Repeat("▀", len(run))))
+			}
+			col = end
+		}
+		if row < radius-1 {
+			out.WriteString("\n")
+		}
+	}
+
+	return out.String()
+}
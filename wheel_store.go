@@ -0,0 +1,486 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SavedWheel is a named set of wheel items kept between runs.
+type SavedWheel struct {
+	Name  string      `json:"name"`
+	Items []WheelItem `json:"items"`
+}
+
+type wheelsFile struct {
+	Wheels []SavedWheel `json:"wheels"`
+}
+
+func getWheelsFilePath() string {
+	return filepath.Join(getDataDir(), "wheels.json")
+}
+
+// loadWheels reads the saved wheels. A missing file means none are saved,
+// but a file that can't be read is an error, and saveWheels won't write
+// over it.
+func loadWheels() ([]SavedWheel, error) {
+	data, err := os.ReadFile(getWheelsFilePath())
+	if os.IsNotExist(err) {
+		return []SavedWheel{}, nil
+	}
+	if err != nil {
+		return []SavedWheel{}, err
+	}
+
+	var file wheelsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return []SavedWheel{}, fmt.Errorf("%s is damaged (%s) • fix or move it to save wheels again", getWheelsFilePath(), describeJSONError(data, 0, err))
+	}
+	return file.Wheels, nil
+}
+
+func saveWheels(wheels []SavedWheel) error {
+	if _, err := loadWheels(); err != nil {
+		return err
+	}
+	sort.SliceStable(wheels, func(i, j int) bool {
+		return strings.ToLower(wheels[i].Name) < strings.ToLower(wheels[j].Name)
+	})
+	data, err := json.MarshalIndent(wheelsFile{Wheels: wheels}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getWheelsFilePath(), data, 0644)
+}
+
+// findWheel returns the index of the saved wheel with the given name,
+// ignoring case, or -1.
+func findWheel(wheels []SavedWheel, name string) int {
+	for i, wheel := range wheels {
+		if strings.EqualFold(wheel.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// storeWheel saves items under name, replacing any wheel with the same name.
+func storeWheel(wheels []SavedWheel, name string, items []WheelItem) []SavedWheel {
+	stored := SavedWheel{Name: name, Items: append([]WheelItem{}, items...)}
+	if i := findWheel(wheels, name); i >= 0 {
+		wheels[i] = stored
+		return wheels
+	}
+	return append(wheels, stored)
+}
+
+// readWheelItems parses one item per line. Lines are plain text with an
+// optional "x3" weight suffix, commas and all, unless the file is CSV: read
+// as CSV (csvFormat), starting with a "name,weight" header, or a line whose
+// second field is a weight, such as "Tacos,3". Blank lines and lines
+// starting with # are skipped.
+func readWheelItems(r io.Reader, csvFormat bool) ([]WheelItem, error) {
+	var items []WheelItem
+	scanner := bufio.NewScanner(r)
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var fields []string
+		if strings.Contains(line, ",") {
+			var err error
+			if fields, err = csv.NewReader(strings.NewReader(line)).Read(); err != nil && csvFormat {
+				return items, err
+			}
+		}
+		isHeader := len(fields) == 2 && strings.EqualFold(strings.TrimSpace(fields[0]), "name") && strings.EqualFold(strings.TrimSpace(fields[1]), "weight")
+		if first && isHeader {
+			csvFormat = true
+		}
+		first = false
+		if isHeader {
+			continue
+		}
+
+		weight, weightErr := 0, fmt.Errorf("no weight")
+		if len(fields) > 1 {
+			weight, weightErr = strconv.Atoi(strings.TrimSpace(fields[1]))
+		}
+		if !csvFormat && (len(fields) != 2 || weightErr != nil || weight < 1) {
+			// Plain text, where a comma is part of the name
+			if item := parseWheelItem(line); item.Name != "" {
+				items = append(items, item)
+			}
+			continue
+		}
+
+		if len(fields) == 0 {
+			fields = []string{line}
+		}
+		item := parseWheelItem(fields[0])
+		if weightErr == nil && weight > 0 {
+			item.Weight = min(weight, maxWheelWeight)
+		}
+		if item.Name != "" {
+			items = append(items, item)
+		}
+	}
+	return items, scanner.Err()
+}
+
+// writeWheelItems writes items as CSV rows when csvFormat is set, otherwise
+// as plain text lines that readWheelItems reads back.
+func writeWheelItems(w io.Writer, items []WheelItem, csvFormat bool) error {
+	if csvFormat {
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"name", "weight"})
+		for _, item := range items {
+			writer.Write([]string{item.Name, strconv.Itoa(item.Weight)})
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	for _, item := range items {
+		line := item.Name
+		if item.Weight > 1 {
+			line += fmt.Sprintf(" x%d", item.Weight)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importWheelFile(path string) ([]WheelItem, error) {
+	file, err := os.Open(expandHomePath(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readWheelItems(file, strings.EqualFold(filepath.Ext(path), ".csv"))
+}
+
+func exportWheelFile(path string, items []WheelItem) error {
+	path = expandHomePath(path)
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeWheelItems(file, items, strings.EqualFold(filepath.Ext(path), ".csv"))
+}
+
+// expandHomePath replaces a leading ~/ with the user's home directory.
+func expandHomePath(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+var wheelPromptLabels = map[string]string{
+	"save":   "Save wheel as:",
+	"rename": "Rename wheel to:",
+	"import": "Import items from file (text, one per line, or CSV name,weight):",
+	"export": "Export items to file (.csv for CSV, anything else for text):",
+}
+
+// wheelExportFilename suggests a file name for exporting the named wheel.
+func wheelExportFilename(name string) string {
+	if name == "" {
+		return "wheel.txt"
+	}
+	return strings.ReplaceAll(strings.ToLower(name), " ", "-") + ".txt"
+}
+
+func (m model) updateWheelPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.wheelPrompt = ""
+		m.wheelPromptInput = ""
+	case "backspace":
+		if len(m.wheelPromptInput) > 0 {
+			m.wheelPromptInput = m.wheelPromptInput[:len(m.wheelPromptInput)-1]
+		}
+	case "enter":
+		input := strings.TrimSpace(m.wheelPromptInput)
+		if input == "" {
+			return m, nil
+		}
+
+		switch m.wheelPrompt {
+		case "save":
+			saved, err := loadWheels()
+			if err == nil {
+				saved = storeWheel(saved, input, m.wheelItems)
+				err = saveWheels(saved)
+			}
+			if err != nil {
+				m.wheelMessage = fmt.Sprintf("❌ Save failed: %v", err)
+			} else {
+				m.wheelSaved = saved
+				m.wheelName = input
+				m.wheelMessage = fmt.Sprintf("✅ Saved %q", input)
+			}
+		case "rename":
+			if i := findWheel(m.wheelSaved, input); i >= 0 && i != m.wheelPickerCursor {
+				m.wheelMessage = fmt.Sprintf("❌ A wheel named %q already exists", input)
+				break
+			}
+			old := m.wheelSaved[m.wheelPickerCursor].Name
+			saved := append([]SavedWheel{}, m.wheelSaved...)
+			saved[m.wheelPickerCursor].Name = input
+			if err := saveWheels(saved); err != nil {
+				m.wheelMessage = fmt.Sprintf("❌ Rename failed: %v", err)
+			} else {
+				m.wheelSaved = saved
+				if strings.EqualFold(m.wheelName, old) {
+					m.wheelName = input
+				}
+				m.wheelPickerCursor = max(0, findWheel(m.wheelSaved, input))
+				m.wheelMessage = fmt.Sprintf("✅ Renamed %q to %q", old, input)
+			}
+		case "import":
+			items, err := importWheelFile(input)
+			if err != nil {
+				m.wheelMessage = fmt.Sprintf("❌ Import failed: %v", err)
+			} else {
+				m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+				m.wheelItems = append(m.wheelItems, items...)
+				m.wheelMessage = fmt.Sprintf("✅ Imported %d items", len(items))
+			}
+		case "export":
+			if err := exportWheelFile(input, m.wheelItems); err != nil {
+				m.wheelMessage = fmt.Sprintf("❌ Export failed: %v", err)
+			} else {
+				m.wheelMessage = fmt.Sprintf("✅ Exported to %s", input)
+			}
+		}
+		m.wheelPrompt = ""
+		m.wheelPromptInput = ""
+	default:
+		if len(msg.String()) == 1 {
+			m.wheelPromptInput += msg.String()
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateWheelPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "l":
+		m.wheelShowPicker = false
+		m.wheelMessage = ""
+	case "up", "k":
+		if m.wheelPickerCursor > 0 {
+			m.wheelPickerCursor--
+		}
+	case "down", "j":
+		if m.wheelPickerCursor < len(m.wheelSaved)-1 {
+			m.wheelPickerCursor++
+		}
+	case "enter":
+		if m.wheelPickerCursor < len(m.wheelSaved) {
+			wheel := m.wheelSaved[m.wheelPickerCursor]
+			m.wheelUndo = pushWheelUndo(m.wheelUndo, m.wheelItems)
+			m.wheelItems = append([]WheelItem{}, wheel.Items...)
+			m.wheelName = wheel.Name
+			m.wheelResult = ""
+			m.wheelCursor = 0
+			m.wheelShowPicker = false
+			m.wheelMessage = fmt.Sprintf("✅ Loaded %q", wheel.Name)
+		}
+	case "r":
+		if m.wheelPickerCursor < len(m.wheelSaved) {
+			m.wheelPrompt = "rename"
+			m.wheelPromptInput = m.wheelSaved[m.wheelPickerCursor].Name
+			m.wheelMessage = ""
+		}
+	case "d":
+		if m.wheelPickerCursor < len(m.wheelSaved) {
+			name := m.wheelSaved[m.wheelPickerCursor].Name
+			saved := append(append([]SavedWheel{}, m.wheelSaved[:m.wheelPickerCursor]...), m.wheelSaved[m.wheelPickerCursor+1:]...)
+			if err := saveWheels(saved); err != nil {
+				m.wheelMessage = fmt.Sprintf("❌ Delete failed: %v", err)
+			} else {
+				m.wheelSaved = saved
+				m.wheelMessage = fmt.Sprintf("✅ Deleted %q", name)
+			}
+			if m.wheelPickerCursor > 0 && m.wheelPickerCursor >= len(m.wheelSaved) {
+				m.wheelPickerCursor--
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewWheelPicker() string {
+	containerStyle := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#9B59B6")).
+		Padding(1, 2).
+		MarginBottom(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#9B59B6")).
+		Width(60).
+		AlignHorizontal(lipgloss.Center)
+
+	listStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#9B59B6")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(60)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#9B59B6"))
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3498DB")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(60)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		AlignHorizontal(lipgloss.Center).
+		Width(60)
+
+	title := titleStyle.Render("💾 Saved Wheels")
+
+	var rows []string
+	if len(m.wheelSaved) == 0 {
+		rows = append(rows, "No saved wheels yet.\n\nPress S on the wheel screen to save one.")
+	}
+	for i, wheel := range m.wheelSaved {
+		row := fmt.Sprintf("%-40s %3d items", wheel.Name, len(wheel.Items))
+		if i == m.wheelPickerCursor {
+			row = selectedStyle.Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	elements := []string{title, listStyle.Render(strings.Join(rows, "\n"))}
+	if m.wheelPrompt != "" {
+		elements = append(elements, inputStyle.Render(wheelPromptLabels[m.wheelPrompt]+"\n"+fmt.Sprintf("▶ %s█", m.wheelPromptInput)))
+	}
+	if m.wheelMessage != "" {
+		elements = append(elements, lipgloss.NewStyle().Bold(true).MarginBottom(1).Render(m.wheelMessage))
+	}
+	elements = append(elements, helpStyle.Render("↑/↓ to select • Enter to load • R to rename • D to delete • ESC to close"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, elements...))
+}
+
+// runWheelCommand handles the "wheel" command line subcommands so wheels can
+// be scripted without opening the TUI. It returns the process exit code.
+func runWheelCommand(args []string) int {
+	usage := "usage: big-dumb-toolbox wheel list\n" +
+		"       big-dumb-toolbox wheel import <name> [file|-]\n" +
+		"       big-dumb-toolbox wheel export <name> [file|-]\n" +
+		"       big-dumb-toolbox wheel delete <name>"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	wheels, err := loadWheels()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		for _, wheel := range wheels {
+			fmt.Printf("%s (%d items)\n", wheel.Name, len(wheel.Items))
+		}
+		return 0
+
+	case args[0] == "import" && (len(args) == 2 || len(args) == 3):
+		var items []WheelItem
+		var err error
+		if len(args) == 2 || args[2] == "-" {
+			items, err = readWheelItems(os.Stdin, false)
+		} else {
+			items, err = importWheelFile(args[2])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if err := saveWheels(storeWheel(wheels, args[1], items)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Imported %d items into %q\n", len(items), args[1])
+		return 0
+
+	case args[0] == "export" && (len(args) == 2 || len(args) == 3):
+		i := findWheel(wheels, args[1])
+		if i < 0 {
+			fmt.Fprintf(os.Stderr, "Error: no wheel named %q\n", args[1])
+			return 1
+		}
+		var err error
+		if len(args) == 2 || args[2] == "-" {
+			err = writeWheelItems(os.Stdout, wheels[i].Items, false)
+		} else {
+			err = exportWheelFile(args[2], wheels[i].Items)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+
+	case args[0] == "delete" && len(args) == 2:
+		i := findWheel(wheels, args[1])
+		if i < 0 {
+			fmt.Fprintf(os.Stderr, "Error: no wheel named %q\n", args[1])
+			return 1
+		}
+		if err := saveWheels(append(wheels[:i], wheels[i+1:]...)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintln(os.Stderr, usage)
+	return 2
+}